@@ -1,15 +1,47 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
-	"time"
 )
 
 type (
 	// AccountsService handles communication with the accounts related methods
 	// of the recurly API.
-	AccountsService struct {
+	AccountsService interface {
+		List(params Params) (*Response, []Account, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Account, error)
+		ListPager(params Params) *Pager[Account]
+		Get(code string) (*Response, Account, error)
+		GetContext(ctx context.Context, code string) (*Response, Account, error)
+		Create(a Account) (*Response, Account, error)
+		CreateContext(ctx context.Context, a Account) (*Response, Account, error)
+		Update(code string, a Account) (*Response, Account, error)
+		UpdateContext(ctx context.Context, code string, a Account) (*Response, Account, error)
+		Close(code string) (*Response, error)
+		CloseContext(ctx context.Context, code string) (*Response, error)
+		Reopen(code string) (*Response, error)
+		ReopenContext(ctx context.Context, code string) (*Response, error)
+		ListNotes(code string) (*Response, []Note, error)
+		ListNotesContext(ctx context.Context, code string) (*Response, []Note, error)
+		ListChildAccounts(code string, params Params) (*Response, []Account, error)
+		ListChildAccountsContext(ctx context.Context, code string, params Params) (*Response, []Account, error)
+		ListChildAccountsPager(code string, params Params) *Pager[Account]
+		GetBalance(code string) (*Response, AccountBalance, error)
+		GetBalanceContext(ctx context.Context, code string) (*Response, AccountBalance, error)
+		GetAcquisition(code string) (*Response, AccountAcquisition, error)
+		GetAcquisitionContext(ctx context.Context, code string) (*Response, AccountAcquisition, error)
+		CreateAcquisition(code string, a AccountAcquisition) (*Response, AccountAcquisition, error)
+		CreateAcquisitionContext(ctx context.Context, code string, a AccountAcquisition) (*Response, AccountAcquisition, error)
+		UpdateAcquisition(code string, a AccountAcquisition) (*Response, AccountAcquisition, error)
+		UpdateAcquisitionContext(ctx context.Context, code string, a AccountAcquisition) (*Response, AccountAcquisition, error)
+		DeleteAcquisition(code string) (*Response, error)
+		DeleteAcquisitionContext(ctx context.Context, code string) (*Response, error)
+	}
+
+	// accountsImpl implements AccountsService.
+	accountsImpl struct {
 		client *Client
 	}
 
@@ -20,16 +52,34 @@ type (
 		State            string   `xml:"state,omitempty"`
 		Username         string   `xml:"username,omitempty"`
 		Email            string   `xml:"email,omitempty"`
+		CCEmails         string   `xml:"cc_emails,omitempty"`
 		FirstName        string   `xml:"first_name,omitempty"`
 		LastName         string   `xml:"last_name,omitempty"`
 		CompanyName      string   `xml:"company_name,omitempty"`
 		VATNumber        string   `xml:"vat_number,omitempty"`
+		VATLocationValid NullBool `xml:"vat_location_valid,omitempty"`
 		TaxExempt        NullBool `xml:"tax_exempt,omitempty"`
+		EntityUseCode    string   `xml:"entity_use_code,omitempty"`
 		BillingInfo      *Billing `xml:"billing_info,omitempty"`
 		Address          Address  `xml:"address,omitempty"`
 		AcceptLanguage   string   `xml:"accept_language,omitempty"`
+		PreferredLocale  string   `xml:"preferred_locale,omitempty"`
 		HostedLoginToken string   `xml:"hosted_login_token,omitempty"`
-		CreatedAt        NullTime `xml:"created_at,omitempty"`
+
+		// ParentAccount links to the parent account, if any. It is read
+		// only: set ParentAccountCode to make the account a child account.
+		ParentAccount     href   `xml:"parent_account,omitempty"`
+		ParentAccountCode string `xml:"parent_account_code,omitempty"`
+
+		HasLiveSubscription     NullBool `xml:"has_live_subscription,omitempty"`
+		HasActiveSubscription   NullBool `xml:"has_active_subscription,omitempty"`
+		HasFutureSubscription   NullBool `xml:"has_future_subscription,omitempty"`
+		HasCanceledSubscription NullBool `xml:"has_canceled_subscription,omitempty"`
+		HasPausedSubscription   NullBool `xml:"has_paused_subscription,omitempty"`
+		HasPastDueInvoice       NullBool `xml:"has_past_due_invoice,omitempty"`
+		CreatedAt               NullTime `xml:"created_at,omitempty"`
+		UpdatedAt               NullTime `xml:"updated_at,omitempty"`
+		ClosedAt                NullTime `xml:"closed_at,omitempty"`
 	}
 
 	// Address is used for embedded addresses within other structs.
@@ -45,9 +95,30 @@ type (
 
 	// Note holds account notes.
 	Note struct {
-		XMLName   xml.Name  `xml:"note"`
-		Message   string    `xml:"message,omitempty"`
-		CreatedAt time.Time `xml:"created_at,omitempty"`
+		XMLName   xml.Name `xml:"note"`
+		Account   href     `xml:"account,omitempty"`
+		Message   string   `xml:"message,omitempty"`
+		CreatedAt NullTime `xml:"created_at,omitempty"`
+	}
+
+	// AccountBalance holds the amount an account owes, per currency.
+	AccountBalance struct {
+		XMLName xml.Name   `xml:"account_balance"`
+		PastDue NullBool   `xml:"past_due,omitempty"`
+		Balance UnitAmount `xml:"balance_in_cents"`
+	}
+
+	// AccountAcquisition holds how an account was acquired, for attributing
+	// customers to marketing channels and campaigns.
+	AccountAcquisition struct {
+		XMLName     xml.Name `xml:"account_acquisition"`
+		CostInCents int      `xml:"cost_in_cents,omitempty"`
+		Currency    string   `xml:"currency,omitempty"`
+		Channel     string   `xml:"channel,omitempty"`
+		Subchannel  string   `xml:"subchannel,omitempty"`
+		Campaign    string   `xml:"campaign,omitempty"`
+		CreatedAt   NullTime `xml:"created_at,omitempty"`
+		UpdatedAt   NullTime `xml:"updated_at,omitempty"`
 	}
 )
 
@@ -121,10 +192,33 @@ const (
 	AccountStateClosed = "closed"
 )
 
+// Acquisition channels.
+const (
+	AcquisitionChannelAdvertising      = "advertising"
+	AcquisitionChannelBlog             = "blog"
+	AcquisitionChannelDirectTraffic    = "direct_traffic"
+	AcquisitionChannelEmail            = "email"
+	AcquisitionChannelEvents           = "events"
+	AcquisitionChannelMarketingContent = "marketing_content"
+	AcquisitionChannelOrganicSearch    = "organic_search"
+	AcquisitionChannelOther            = "other"
+	AcquisitionChannelOutboundSales    = "outbound_sales"
+	AcquisitionChannelPaidSearch       = "paid_search"
+	AcquisitionChannelPublicRelations  = "public_relations"
+	AcquisitionChannelReferral         = "referral"
+	AcquisitionChannelSocialMedia      = "social_media"
+)
+
 // List returns a list of the accounts on your site.
 // https://docs.recurly.com/api/accounts#list-accounts
-func (service AccountsService) List(params Params) (*Response, []Account, error) {
-	req, err := service.client.newRequest("GET", "accounts", params, nil)
+func (service accountsImpl) List(params Params) (*Response, []Account, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service accountsImpl) ListContext(ctx context.Context, params Params) (*Response, []Account, error) {
+	ctx = withOperation(ctx, "Accounts.List", "accounts")
+	req, err := service.client.newRequest(ctx, "GET", "accounts", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -142,11 +236,24 @@ func (service AccountsService) List(params Params) (*Response, []Account, error)
 	return res, a.Accounts, err
 }
 
+// ListPager returns a Pager that walks every page of accounts on your site.
+func (service accountsImpl) ListPager(params Params) *Pager[Account] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Account, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // Get returns information about a single account.
 // https://docs.recurly.com/api/accounts#get-account
-func (service AccountsService) Get(code string) (*Response, Account, error) {
+func (service accountsImpl) Get(code string) (*Response, Account, error) {
+	return service.GetContext(context.Background(), code)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service accountsImpl) GetContext(ctx context.Context, code string) (*Response, Account, error) {
+	ctx = withOperation(ctx, "Accounts.Get", "accounts/{account_code}")
 	action := fmt.Sprintf("accounts/%s", code)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Account{}, err
 	}
@@ -161,8 +268,14 @@ func (service AccountsService) Get(code string) (*Response, Account, error) {
 
 // Create will create a new account. You may optionally include billing information.
 // https://docs.recurly.com/api/accounts#create-account
-func (service AccountsService) Create(a Account) (*Response, Account, error) {
-	req, err := service.client.newRequest("POST", "accounts", nil, a)
+func (service accountsImpl) Create(a Account) (*Response, Account, error) {
+	return service.CreateContext(context.Background(), a)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service accountsImpl) CreateContext(ctx context.Context, a Account) (*Response, Account, error) {
+	ctx = withOperation(ctx, "Accounts.Create", "accounts")
+	req, err := service.client.newRequest(ctx, "POST", "accounts", nil, a)
 	if err != nil {
 		return nil, Account{}, err
 	}
@@ -179,9 +292,15 @@ func (service AccountsService) Create(a Account) (*Response, Account, error) {
 // It's recommended to create a new account object with only the changes you
 // want to make. The updated account object will be returned on success.
 // https://docs.recurly.com/api/accounts#update-account
-func (service AccountsService) Update(code string, a Account) (*Response, Account, error) {
+func (service accountsImpl) Update(code string, a Account) (*Response, Account, error) {
+	return service.UpdateContext(context.Background(), code, a)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service accountsImpl) UpdateContext(ctx context.Context, code string, a Account) (*Response, Account, error) {
+	ctx = withOperation(ctx, "Accounts.Update", "accounts/{account_code}")
 	action := fmt.Sprintf("accounts/%s", code)
-	req, err := service.client.newRequest("PUT", action, nil, a)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, a)
 	if err != nil {
 		return nil, Account{}, err
 	}
@@ -197,9 +316,15 @@ func (service AccountsService) Update(code string, a Account) (*Response, Accoun
 // Close marks an account as closed and cancels any active subscriptions. Any
 // saved billing information will also be permanently removed from the account.
 // https://docs.recurly.com/api/accounts#close-account
-func (service AccountsService) Close(code string) (*Response, error) {
+func (service accountsImpl) Close(code string) (*Response, error) {
+	return service.CloseContext(context.Background(), code)
+}
+
+// CloseContext is the same as Close, but uses ctx for the request.
+func (service accountsImpl) CloseContext(ctx context.Context, code string) (*Response, error) {
+	ctx = withOperation(ctx, "Accounts.Close", "accounts/{account_code}")
 	action := fmt.Sprintf("accounts/%s", code)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -209,9 +334,15 @@ func (service AccountsService) Close(code string) (*Response, error) {
 
 // Reopen transitions a closed account back to active.
 // https://docs.recurly.com/api/accounts#reopen-account
-func (service AccountsService) Reopen(code string) (*Response, error) {
+func (service accountsImpl) Reopen(code string) (*Response, error) {
+	return service.ReopenContext(context.Background(), code)
+}
+
+// ReopenContext is the same as Reopen, but uses ctx for the request.
+func (service accountsImpl) ReopenContext(ctx context.Context, code string) (*Response, error) {
+	ctx = withOperation(ctx, "Accounts.Reopen", "accounts/{account_code}/reopen")
 	action := fmt.Sprintf("accounts/%s/reopen", code)
-	req, err := service.client.newRequest("PUT", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -221,9 +352,15 @@ func (service AccountsService) Reopen(code string) (*Response, error) {
 
 // ListNotes returns a list of the notes on an account sorted in descending order.
 // https://docs.recurly.com/api/accounts#get-account-notes
-func (service AccountsService) ListNotes(code string) (*Response, []Note, error) {
+func (service accountsImpl) ListNotes(code string) (*Response, []Note, error) {
+	return service.ListNotesContext(context.Background(), code)
+}
+
+// ListNotesContext is the same as ListNotes, but uses ctx for the request.
+func (service accountsImpl) ListNotesContext(ctx context.Context, code string) (*Response, []Note, error) {
+	ctx = withOperation(ctx, "Accounts.ListNotes", "accounts/{account_code}/notes")
 	action := fmt.Sprintf("accounts/%s/notes", code)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -236,3 +373,142 @@ func (service AccountsService) ListNotes(code string) (*Response, []Note, error)
 
 	return res, n.Notes, err
 }
+
+// ListChildAccounts returns the child accounts of a parent account.
+// https://dev.recurly.com/docs/list-child-accounts
+func (service accountsImpl) ListChildAccounts(code string, params Params) (*Response, []Account, error) {
+	return service.ListChildAccountsContext(context.Background(), code, params)
+}
+
+// ListChildAccountsContext is the same as ListChildAccounts, but uses ctx for the request.
+func (service accountsImpl) ListChildAccountsContext(ctx context.Context, code string, params Params) (*Response, []Account, error) {
+	ctx = withOperation(ctx, "Accounts.ListChildAccounts", "accounts/{account_code}/child_accounts")
+	action := fmt.Sprintf("accounts/%s/child_accounts", code)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var a struct {
+		XMLName  xml.Name  `xml:"accounts"`
+		Accounts []Account `xml:"account"`
+	}
+	res, err := service.client.do(req, &a)
+
+	for i := range a.Accounts {
+		a.Accounts[i].BillingInfo = nil
+	}
+
+	return res, a.Accounts, err
+}
+
+// ListChildAccountsPager returns a Pager that walks every page of a parent
+// account's child accounts.
+func (service accountsImpl) ListChildAccountsPager(code string, params Params) *Pager[Account] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Account, error) {
+		return service.ListChildAccountsContext(ctx, code, params)
+	})
+}
+
+// GetBalance returns the account's balance in each currency, and whether
+// any of it is past due.
+// https://dev.recurly.com/docs/lookup-account-balance
+func (service accountsImpl) GetBalance(code string) (*Response, AccountBalance, error) {
+	return service.GetBalanceContext(context.Background(), code)
+}
+
+// GetBalanceContext is the same as GetBalance, but uses ctx for the request.
+func (service accountsImpl) GetBalanceContext(ctx context.Context, code string) (*Response, AccountBalance, error) {
+	ctx = withOperation(ctx, "Accounts.GetBalance", "accounts/{account_code}/balance")
+	action := fmt.Sprintf("accounts/%s/balance", code)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
+	if err != nil {
+		return nil, AccountBalance{}, err
+	}
+
+	var dest AccountBalance
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// GetAcquisition returns the account's acquisition data.
+// https://dev.recurly.com/docs/lookup-account-acquisition
+func (service accountsImpl) GetAcquisition(code string) (*Response, AccountAcquisition, error) {
+	return service.GetAcquisitionContext(context.Background(), code)
+}
+
+// GetAcquisitionContext is the same as GetAcquisition, but uses ctx for the request.
+func (service accountsImpl) GetAcquisitionContext(ctx context.Context, code string) (*Response, AccountAcquisition, error) {
+	ctx = withOperation(ctx, "Accounts.GetAcquisition", "accounts/{account_code}/acquisition")
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
+	if err != nil {
+		return nil, AccountAcquisition{}, err
+	}
+
+	var dest AccountAcquisition
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// CreateAcquisition adds acquisition data to an account.
+// https://dev.recurly.com/docs/create-account-acquisition
+func (service accountsImpl) CreateAcquisition(code string, a AccountAcquisition) (*Response, AccountAcquisition, error) {
+	return service.CreateAcquisitionContext(context.Background(), code, a)
+}
+
+// CreateAcquisitionContext is the same as CreateAcquisition, but uses ctx for the request.
+func (service accountsImpl) CreateAcquisitionContext(ctx context.Context, code string, a AccountAcquisition) (*Response, AccountAcquisition, error) {
+	ctx = withOperation(ctx, "Accounts.CreateAcquisition", "accounts/{account_code}/acquisition")
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, a)
+	if err != nil {
+		return nil, AccountAcquisition{}, err
+	}
+
+	var dest AccountAcquisition
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// UpdateAcquisition updates the account's acquisition data.
+// https://dev.recurly.com/docs/update-account-acquisition
+func (service accountsImpl) UpdateAcquisition(code string, a AccountAcquisition) (*Response, AccountAcquisition, error) {
+	return service.UpdateAcquisitionContext(context.Background(), code, a)
+}
+
+// UpdateAcquisitionContext is the same as UpdateAcquisition, but uses ctx for the request.
+func (service accountsImpl) UpdateAcquisitionContext(ctx context.Context, code string, a AccountAcquisition) (*Response, AccountAcquisition, error) {
+	ctx = withOperation(ctx, "Accounts.UpdateAcquisition", "accounts/{account_code}/acquisition")
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, a)
+	if err != nil {
+		return nil, AccountAcquisition{}, err
+	}
+
+	var dest AccountAcquisition
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// DeleteAcquisition removes the account's acquisition data.
+// https://dev.recurly.com/docs/clear-account-acquisition
+func (service accountsImpl) DeleteAcquisition(code string) (*Response, error) {
+	return service.DeleteAcquisitionContext(context.Background(), code)
+}
+
+// DeleteAcquisitionContext is the same as DeleteAcquisition, but uses ctx for the request.
+func (service accountsImpl) DeleteAcquisitionContext(ctx context.Context, code string) (*Response, error) {
+	ctx = withOperation(ctx, "Accounts.DeleteAcquisition", "accounts/{account_code}/acquisition")
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.do(req, nil)
+}
@@ -28,6 +28,9 @@ func TestAccountEncoding(t *testing.T) {
 		map[string]interface{}{"struct": Account{TaxExempt: NewBool(true)}, "xml": "<account><tax_exempt>true</tax_exempt></account>"},
 		map[string]interface{}{"struct": Account{TaxExempt: NewBool(false)}, "xml": "<account><tax_exempt>false</tax_exempt></account>"},
 		map[string]interface{}{"struct": Account{AcceptLanguage: "en_US"}, "xml": "<account><accept_language>en_US</accept_language></account>"},
+		map[string]interface{}{"struct": Account{CCEmails: "bob@example.com,susan@example.com"}, "xml": "<account><cc_emails>bob@example.com,susan@example.com</cc_emails></account>"},
+		map[string]interface{}{"struct": Account{EntityUseCode: "I", PreferredLocale: "en-US"}, "xml": "<account><entity_use_code>I</entity_use_code><preferred_locale>en-US</preferred_locale></account>"},
+		map[string]interface{}{"struct": Account{ParentAccountCode: "reseller", ParentAccount: href{Code: "other"}}, "xml": "<account><parent_account_code>reseller</parent_account_code></account>"},
 		map[string]interface{}{"struct": Account{FirstName: "Larry", Address: Address{Address: "123 Main St.", City: "San Francisco", State: "CA", Zip: "94105", Country: "US"}}, "xml": "<account><first_name>Larry</first_name><address><address1>123 Main St.</address1><city>San Francisco</city><state>CA</state><zip>94105</zip><country>US</country></address></account>"},
 		map[string]interface{}{"struct": Account{Code: "test@example.com", BillingInfo: &Billing{Token: "507c7f79bcf86cd7994f6c0e"}}, "xml": "<account><account_code>test@example.com</account_code><billing_info><token_id>507c7f79bcf86cd7994f6c0e</token_id></billing_info></account>"},
 		map[string]interface{}{"struct": Address{}, "xml": ""},
@@ -38,6 +41,8 @@ func TestAccountEncoding(t *testing.T) {
 		map[string]interface{}{"struct": Address{Zip: "94105"}, "xml": "<address><zip>94105</zip></address>"},
 		map[string]interface{}{"struct": Address{Country: "US"}, "xml": "<address><country>US</country></address>"},
 		map[string]interface{}{"struct": Address{Phone: "555-555-5555"}, "xml": "<address><phone>555-555-5555</phone></address>"},
+		map[string]interface{}{"struct": AccountAcquisition{}, "xml": "<account_acquisition></account_acquisition>"},
+		map[string]interface{}{"struct": AccountAcquisition{CostInCents: 199, Currency: "USD", Channel: AcquisitionChannelBlog, Subchannel: "Whitepaper Blog Post", Campaign: "mailchimp67a904de95.0914d8f4b4"}, "xml": "<account_acquisition><cost_in_cents>199</cost_in_cents><currency>USD</currency><channel>blog</channel><subchannel>Whitepaper Blog Post</subchannel><campaign>mailchimp67a904de95.0914d8f4b4</campaign></account_acquisition>"},
 	}
 
 	for _, s := range suite {
@@ -195,15 +200,27 @@ func TestGetAccount(t *testing.T) {
 			  <redemption href="https://your-subdomain.recurly.com/v2/accounts/1/redemption"/>
 			  <subscriptions href="https://your-subdomain.recurly.com/v2/accounts/1/subscriptions"/>
 			  <transactions href="https://your-subdomain.recurly.com/v2/accounts/1/transactions"/>
+			  <parent_account href="https://your-subdomain.recurly.com/v2/accounts/reseller"/>
+			  <child_accounts href="https://your-subdomain.recurly.com/v2/accounts/1/child_accounts"/>
 			  <account_code>1</account_code>
 			  <state>active</state>
 			  <username nil="nil"></username>
 			  <email>verena@example.com</email>
+			  <cc_emails>bob@example.com,susan@example.com</cc_emails>
 			  <first_name>Verena</first_name>
 			  <last_name>Example</last_name>
 			  <company_name></company_name>
 			  <vat_number nil="nil"></vat_number>
+			  <vat_location_valid type="boolean">true</vat_location_valid>
 			  <tax_exempt type="boolean">false</tax_exempt>
+			  <entity_use_code>I</entity_use_code>
+			  <has_live_subscription type="boolean">true</has_live_subscription>
+			  <has_active_subscription type="boolean">true</has_active_subscription>
+			  <has_future_subscription type="boolean">false</has_future_subscription>
+			  <has_canceled_subscription type="boolean">false</has_canceled_subscription>
+			  <has_paused_subscription type="boolean">false</has_paused_subscription>
+			  <has_past_due_invoice type="boolean">true</has_past_due_invoice>
+			  <preferred_locale>en-US</preferred_locale>
 			  <address>
 			    <address1>123 Main St.</address1>
 			    <address2 nil="nil"></address2>
@@ -216,6 +233,8 @@ func TestGetAccount(t *testing.T) {
 			  <accept_language nil="nil"></accept_language>
 			  <hosted_login_token>a92468579e9c4231a6c0031c4716c01d</hosted_login_token>
 			  <created_at type="datetime">2011-10-25T12:00:00Z</created_at>
+			  <updated_at type="datetime">2011-10-26T12:00:00Z</updated_at>
+			  <closed_at nil="nil"></closed_at>
 			</account>`)
 	})
 
@@ -229,14 +248,18 @@ func TestGetAccount(t *testing.T) {
 	}
 
 	ts, _ := time.Parse(datetimeFormat, "2011-10-25T12:00:00Z")
+	updated, _ := time.Parse(datetimeFormat, "2011-10-26T12:00:00Z")
 	expected := Account{
-		XMLName:   xml.Name{Local: "account"},
-		Code:      "1",
-		State:     "active",
-		Email:     "verena@example.com",
-		FirstName: "Verena",
-		LastName:  "Example",
-		TaxExempt: NewBool(false),
+		XMLName:          xml.Name{Local: "account"},
+		Code:             "1",
+		State:            "active",
+		Email:            "verena@example.com",
+		CCEmails:         "bob@example.com,susan@example.com",
+		FirstName:        "Verena",
+		LastName:         "Example",
+		VATLocationValid: NewBool(true),
+		TaxExempt:        NewBool(false),
+		EntityUseCode:    "I",
 		Address: Address{
 			Address: "123 Main St.",
 			City:    "San Francisco",
@@ -244,8 +267,20 @@ func TestGetAccount(t *testing.T) {
 			Zip:     "94105",
 			Country: "US",
 		},
+		PreferredLocale:  "en-US",
 		HostedLoginToken: "a92468579e9c4231a6c0031c4716c01d",
-		CreatedAt:        NewTime(ts),
+		ParentAccount: href{
+			HREF: "https://your-subdomain.recurly.com/v2/accounts/reseller",
+			Code: "reseller",
+		},
+		HasLiveSubscription:     NewBool(true),
+		HasActiveSubscription:   NewBool(true),
+		HasFutureSubscription:   NewBool(false),
+		HasCanceledSubscription: NewBool(false),
+		HasPausedSubscription:   NewBool(false),
+		HasPastDueInvoice:       NewBool(true),
+		CreatedAt:               NewTime(ts),
+		UpdatedAt:               NewTime(updated),
 	}
 
 	if !reflect.DeepEqual(expected, a) {
@@ -378,16 +413,22 @@ func TestAccountListNotes(t *testing.T) {
 
 	ts1, _ := time.Parse(datetimeFormat, "2013-05-14T18:52:50Z")
 	ts2, _ := time.Parse(datetimeFormat, "2013-05-14T18:53:04Z")
+	account := href{
+		HREF: "https://your-subdomain.recurly.com/v2/accounts/abcd@example.com",
+		Code: "abcd@example.com",
+	}
 	expected := []Note{
 		Note{
 			XMLName:   xml.Name{Local: "note"},
+			Account:   account,
 			Message:   "This is my second note",
-			CreatedAt: ts2,
+			CreatedAt: NewTime(ts2),
 		},
 		Note{
 			XMLName:   xml.Name{Local: "note"},
+			Account:   account,
 			Message:   "This is my first note",
-			CreatedAt: ts1,
+			CreatedAt: NewTime(ts1),
 		},
 	}
 
@@ -395,3 +436,230 @@ func TestAccountListNotes(t *testing.T) {
 		t.Errorf("TestAccountListNotes Error: expected notes to equal %#v, notes %#v", expected, notes)
 	}
 }
+
+func TestAccountListChildAccounts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/reseller/child_accounts", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestAccountListChildAccounts Error: Expected %s request, given %s", "GET", r.Method)
+		} else if r.URL.Query().Get("per_page") != "10" {
+			t.Errorf("TestAccountListChildAccounts Error: Expected per_page param, given %s", r.URL.RawQuery)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<accounts type="array">
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/1">
+			    <parent_account href="https://your-subdomain.recurly.com/v2/accounts/reseller"/>
+			    <billing_info href="https://your-subdomain.recurly.com/v2/accounts/1/billing_info"/>
+			    <account_code>1</account_code>
+			  </account>
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/2">
+			    <parent_account href="https://your-subdomain.recurly.com/v2/accounts/reseller"/>
+			    <account_code>2</account_code>
+			  </account>
+			</accounts>`)
+	})
+
+	r, accounts, err := client.Accounts.ListChildAccounts("reseller", Params{"per_page": 10})
+	if err != nil {
+		t.Errorf("TestAccountListChildAccounts Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountListChildAccounts Error: Expected list child accounts to return OK")
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf("TestAccountListChildAccounts Error: Expected 2 accounts returned, given %d", len(accounts))
+	}
+
+	for i, a := range accounts {
+		if a.Code != fmt.Sprint(i+1) || a.ParentAccount.Code != "reseller" || a.BillingInfo != nil {
+			t.Errorf("TestAccountListChildAccounts Error: Expected child account of reseller, given %#v", a)
+		}
+	}
+}
+
+func TestAccountGetBalance(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/balance", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestAccountGetBalance Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<account_balance href="https://your-subdomain.recurly.com/v2/accounts/1/balance">
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			  <past_due type="boolean">true</past_due>
+			  <balance_in_cents>
+			    <USD type="integer">3000</USD>
+			    <EUR type="integer">-1500</EUR>
+			    <GBP type="integer">800</GBP>
+			  </balance_in_cents>
+			</account_balance>`)
+	})
+
+	r, b, err := client.Accounts.GetBalance("1")
+	if err != nil {
+		t.Errorf("TestAccountGetBalance Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountGetBalance Error: Expected get balance to return OK")
+	}
+
+	expected := AccountBalance{
+		XMLName: xml.Name{Local: "account_balance"},
+		PastDue: NewBool(true),
+		Balance: UnitAmount{USD: 3000, EUR: -1500, Other: map[string]int{"GBP": 800}},
+	}
+
+	if !reflect.DeepEqual(expected, b) {
+		t.Errorf("TestAccountGetBalance Error: expected balance to equal %#v, given %#v", expected, b)
+	}
+
+	// A missing past_due element is unknown, not false.
+	var missing AccountBalance
+	if err := xml.Unmarshal([]byte(`<account_balance><balance_in_cents><USD>0</USD></balance_in_cents></account_balance>`), &missing); err != nil {
+		t.Fatalf("TestAccountGetBalance Error: Error decoding balance. Err: %s", err)
+	} else if missing.PastDue.Valid {
+		t.Errorf("TestAccountGetBalance Error: Expected past due to not be set, given %#v", missing.PastDue)
+	}
+}
+
+func TestAccountGetAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestAccountGetAcquisition Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<account_acquisition href="https://your-subdomain.recurly.com/v2/accounts/1/acquisition">
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			  <cost_in_cents type="integer">199</cost_in_cents>
+			  <currency>USD</currency>
+			  <channel>blog</channel>
+			  <subchannel>Whitepaper Blog Post</subchannel>
+			  <campaign>mailchimp67a904de95.0914d8f4b4</campaign>
+			  <created_at type="datetime">2016-08-25T20:08:27Z</created_at>
+			  <updated_at type="datetime">2016-08-26T20:08:27Z</updated_at>
+			</account_acquisition>`)
+	})
+
+	r, a, err := client.Accounts.GetAcquisition("1")
+	if err != nil {
+		t.Errorf("TestAccountGetAcquisition Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountGetAcquisition Error: Expected get acquisition to return OK")
+	}
+
+	created, _ := time.Parse(datetimeFormat, "2016-08-25T20:08:27Z")
+	updated, _ := time.Parse(datetimeFormat, "2016-08-26T20:08:27Z")
+	expected := AccountAcquisition{
+		XMLName:     xml.Name{Local: "account_acquisition"},
+		CostInCents: 199,
+		Currency:    "USD",
+		Channel:     AcquisitionChannelBlog,
+		Subchannel:  "Whitepaper Blog Post",
+		Campaign:    "mailchimp67a904de95.0914d8f4b4",
+		CreatedAt:   NewTime(created),
+		UpdatedAt:   NewTime(updated),
+	}
+
+	if !reflect.DeepEqual(expected, a) {
+		t.Errorf("TestAccountGetAcquisition Error: expected acquisition to equal %#v, given %#v", expected, a)
+	}
+
+	if a.Cost().String() != "1.99 USD" {
+		t.Errorf("TestAccountGetAcquisition Error: Expected cost of 1.99 USD, given %s", a.Cost())
+	}
+}
+
+func TestAccountCreateAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestAccountCreateAcquisition Error: Expected %s request, given %s", "POST", r.Method)
+		}
+
+		var given AccountAcquisition
+		xml.NewDecoder(r.Body).Decode(&given)
+		if given.Channel != AcquisitionChannelReferral || given.CostInCents != 500 {
+			t.Errorf("TestAccountCreateAcquisition Error: Unexpected request body: %#v", given)
+		}
+
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account_acquisition><channel>referral</channel></account_acquisition>`)
+	})
+
+	r, a, err := client.Accounts.CreateAcquisition("1", AccountAcquisition{
+		CostInCents: 500,
+		Currency:    "USD",
+		Channel:     AcquisitionChannelReferral,
+	})
+	if err != nil {
+		t.Errorf("TestAccountCreateAcquisition Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountCreateAcquisition Error: Expected create acquisition to return OK")
+	} else if a.Channel != AcquisitionChannelReferral {
+		t.Errorf("TestAccountCreateAcquisition Error: Expected referral channel, given %s", a.Channel)
+	}
+}
+
+func TestAccountUpdateAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestAccountUpdateAcquisition Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account_acquisition><campaign>spring</campaign></account_acquisition>`)
+	})
+
+	r, a, err := client.Accounts.UpdateAcquisition("1", AccountAcquisition{Campaign: "spring"})
+	if err != nil {
+		t.Errorf("TestAccountUpdateAcquisition Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountUpdateAcquisition Error: Expected update acquisition to return OK")
+	} else if a.Campaign != "spring" {
+		t.Errorf("TestAccountUpdateAcquisition Error: Expected spring campaign, given %s", a.Campaign)
+	}
+}
+
+func TestAccountDeleteAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestAccountDeleteAcquisition Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+		rw.WriteHeader(204)
+	})
+
+	r, err := client.Accounts.DeleteAcquisition("1")
+	if err != nil {
+		t.Errorf("TestAccountDeleteAcquisition Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestAccountDeleteAcquisition Error: Expected delete acquisition to return OK")
+	}
+}
@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -8,7 +9,22 @@ import (
 type (
 	// AddOnsService handles communication with the add ons related methods
 	// of the recurly API.
-	AddOnsService struct {
+	AddOnsService interface {
+		List(planCode string, params Params) (*Response, []AddOn, error)
+		ListContext(ctx context.Context, planCode string, params Params) (*Response, []AddOn, error)
+		ListPager(planCode string, params Params) *Pager[AddOn]
+		Get(planCode string, code string) (*Response, AddOn, error)
+		GetContext(ctx context.Context, planCode string, code string) (*Response, AddOn, error)
+		Create(planCode string, a AddOn) (*Response, AddOn, error)
+		CreateContext(ctx context.Context, planCode string, a AddOn) (*Response, AddOn, error)
+		Update(planCode string, code string, a AddOn) (*Response, AddOn, error)
+		UpdateContext(ctx context.Context, planCode string, code string, a AddOn) (*Response, AddOn, error)
+		Delete(planCode string, code string) (*Response, error)
+		DeleteContext(ctx context.Context, planCode string, code string) (*Response, error)
+	}
+
+	// addOnsImpl implements AddOnsService.
+	addOnsImpl struct {
 		client *Client
 	}
 
@@ -23,14 +39,44 @@ type (
 		UnitAmountInCents           UnitAmount `xml:"unit_amount_in_cents,omitempty"`
 		AccountingCode              string     `xml:"accounting_code,omitempty"`
 		CreatedAt                   NullTime   `xml:"created_at,omitempty"`
+
+		// Usage add ons are billed for the usage logged against them with
+		// the UsageService, either at UnitAmountInCents per unit or as
+		// UsagePercentage of each usage amount. MeasuredUnitID sets the
+		// unit usage is measured in; MeasuredUnit links to it when read.
+		// UsagePercentage is a decimal such as "2.5", kept as a string so it
+		// isn't changed by conversion to a binary float.
+		AddOnType       string `xml:"add_on_type,omitempty"`
+		UsageType       string `xml:"usage_type,omitempty"`
+		UsagePercentage string `xml:"usage_percentage,omitempty"`
+		MeasuredUnitID  int    `xml:"measured_unit_id,omitempty"`
+		MeasuredUnit    href   `xml:"measured_unit,omitempty"`
 	}
 )
 
+// Add on types.
+const (
+	AddOnTypeFixed = "fixed"
+	AddOnTypeUsage = "usage"
+)
+
+// Usage types of usage add ons.
+const (
+	UsageTypePrice      = "price"
+	UsageTypePercentage = "percentage"
+)
+
 // List returns a list of add ons for a plan.
 // https://docs.recurly.com/api/plans/add-ons#list-addons
-func (service AddOnsService) List(planCode string, params Params) (*Response, []AddOn, error) {
+func (service addOnsImpl) List(planCode string, params Params) (*Response, []AddOn, error) {
+	return service.ListContext(context.Background(), planCode, params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service addOnsImpl) ListContext(ctx context.Context, planCode string, params Params) (*Response, []AddOn, error) {
+	ctx = withOperation(ctx, "AddOns.List", "plans/{plan_code}/add_ons")
 	action := fmt.Sprintf("plans/%s/add_ons", planCode)
-	req, err := service.client.newRequest("GET", action, params, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -44,11 +90,24 @@ func (service AddOnsService) List(planCode string, params Params) (*Response, []
 	return res, p.AddOns, err
 }
 
+// ListPager returns a Pager that walks every page of add ons for a plan.
+func (service addOnsImpl) ListPager(planCode string, params Params) *Pager[AddOn] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []AddOn, error) {
+		return service.ListContext(ctx, planCode, params)
+	})
+}
+
 // Get returns information about an add on.
 // https://docs.recurly.com/api/plans/add-ons#lookup-addon
-func (service AddOnsService) Get(planCode string, code string) (*Response, AddOn, error) {
+func (service addOnsImpl) Get(planCode string, code string) (*Response, AddOn, error) {
+	return service.GetContext(context.Background(), planCode, code)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service addOnsImpl) GetContext(ctx context.Context, planCode string, code string) (*Response, AddOn, error) {
+	ctx = withOperation(ctx, "AddOns.Get", "plans/{plan_code}/add_ons/{add_on_code}")
 	action := fmt.Sprintf("plans/%s/add_ons/%s", planCode, code)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, AddOn{}, err
 	}
@@ -61,9 +120,15 @@ func (service AddOnsService) Get(planCode string, code string) (*Response, AddOn
 
 // Create adds an add on to a plan.
 // https://docs.recurly.com/api/plans/add-ons#create-addon
-func (service AddOnsService) Create(planCode string, a AddOn) (*Response, AddOn, error) {
+func (service addOnsImpl) Create(planCode string, a AddOn) (*Response, AddOn, error) {
+	return service.CreateContext(context.Background(), planCode, a)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service addOnsImpl) CreateContext(ctx context.Context, planCode string, a AddOn) (*Response, AddOn, error) {
+	ctx = withOperation(ctx, "AddOns.Create", "plans/{plan_code}/add_ons")
 	action := fmt.Sprintf("plans/%s/add_ons", planCode)
-	req, err := service.client.newRequest("POST", action, nil, a)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, a)
 	if err != nil {
 		return nil, AddOn{}, err
 	}
@@ -77,9 +142,15 @@ func (service AddOnsService) Create(planCode string, a AddOn) (*Response, AddOn,
 // Update will update the pricing information or description for an add-on.
 // Subscriptions who have already subscribed to the add-on will not receive the new pricing.
 // https://docs.recurly.com/api/plans/add-ons#update-addon
-func (service AddOnsService) Update(planCode string, code string, a AddOn) (*Response, AddOn, error) {
+func (service addOnsImpl) Update(planCode string, code string, a AddOn) (*Response, AddOn, error) {
+	return service.UpdateContext(context.Background(), planCode, code, a)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service addOnsImpl) UpdateContext(ctx context.Context, planCode string, code string, a AddOn) (*Response, AddOn, error) {
+	ctx = withOperation(ctx, "AddOns.Update", "plans/{plan_code}/add_ons/{add_on_code}")
 	action := fmt.Sprintf("plans/%s/add_ons/%s", planCode, code)
-	req, err := service.client.newRequest("PUT", action, nil, a)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, a)
 	if err != nil {
 		return nil, AddOn{}, err
 	}
@@ -92,9 +163,15 @@ func (service AddOnsService) Update(planCode string, code string, a AddOn) (*Res
 
 // Delete will remove an add on from a plan.
 // https://docs.recurly.com/api/plans/add-ons#delete-addon
-func (service AddOnsService) Delete(planCode string, code string) (*Response, error) {
+func (service addOnsImpl) Delete(planCode string, code string) (*Response, error) {
+	return service.DeleteContext(context.Background(), planCode, code)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service addOnsImpl) DeleteContext(ctx context.Context, planCode string, code string) (*Response, error) {
+	ctx = withOperation(ctx, "AddOns.Delete", "plans/{plan_code}/add_ons/{add_on_code}")
 	action := fmt.Sprintf("plans/%s/add_ons/%s", planCode, code)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -26,6 +26,7 @@ func TestAddOnsEncoding(t *testing.T) {
 		map[string]interface{}{"struct": AddOn{TaxCode: "digital"}, "xml": "<add_on><tax_code>digital</tax_code></add_on>"},
 		map[string]interface{}{"struct": AddOn{UnitAmountInCents: UnitAmount{USD: 200}}, "xml": "<add_on><unit_amount_in_cents><USD>200</USD></unit_amount_in_cents></add_on>"},
 		map[string]interface{}{"struct": AddOn{AccountingCode: "abc123"}, "xml": "<add_on><accounting_code>abc123</accounting_code></add_on>"},
+		map[string]interface{}{"struct": AddOn{AddOnType: AddOnTypeUsage, UsageType: UsageTypePercentage, UsagePercentage: "2.5", MeasuredUnitID: 5}, "xml": "<add_on><add_on_type>usage</add_on_type><usage_type>percentage</usage_type><usage_percentage>2.5</usage_percentage><measured_unit_id>5</measured_unit_id></add_on>"},
 	}
 
 	for _, s := range suite {
@@ -157,6 +158,36 @@ func TestGetAddOn(t *testing.T) {
 	}
 }
 
+func TestGetUsageAddOn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/plans/gold/add_ons/calls", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<add_on href="https://your-subdomain.recurly.com/v2/plans/gold/add_ons/calls">
+				<plan href="https://your-subdomain.recurly.com/v2/plans/gold"/>
+				<measured_unit href="https://your-subdomain.recurly.com/v2/measured_units/5"/>
+				<add_on_code>calls</add_on_code>
+				<add_on_type>usage</add_on_type>
+				<usage_type>price</usage_type>
+				<usage_percentage nil="nil"></usage_percentage>
+				<unit_amount_in_cents>
+					<USD type="integer">5</USD>
+				</unit_amount_in_cents>
+			</add_on>`)
+	})
+
+	_, a, err := client.AddOns.Get("gold", "calls")
+	if err != nil {
+		t.Fatalf("TestGetUsageAddOn Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if a.AddOnType != AddOnTypeUsage || a.UsageType != UsageTypePrice || a.MeasuredUnit.Code != "5" || a.UnitAmountInCents.USD != 5 {
+		t.Errorf("TestGetUsageAddOn Error: Unexpected usage add on, given %#v", a)
+	}
+}
+
 func TestCreateAddOn(t *testing.T) {
 	setup()
 	defer teardown()
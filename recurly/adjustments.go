@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -8,7 +9,20 @@ import (
 type (
 	// AdjustmentsService handles communication with the adjustments related methods
 	// of the recurly API.
-	AdjustmentsService struct {
+	AdjustmentsService interface {
+		List(accountCode string, params Params) (*Response, []Adjustment, error)
+		ListContext(ctx context.Context, accountCode string, params Params) (*Response, []Adjustment, error)
+		ListPager(accountCode string, params Params) *Pager[Adjustment]
+		Get(uuid string) (*Response, Adjustment, error)
+		GetContext(ctx context.Context, uuid string) (*Response, Adjustment, error)
+		Create(accountCode string, a Adjustment) (*Response, Adjustment, error)
+		CreateContext(ctx context.Context, accountCode string, a Adjustment) (*Response, Adjustment, error)
+		Delete(uuid string) (*Response, error)
+		DeleteContext(ctx context.Context, uuid string) (*Response, error)
+	}
+
+	// adjustmentsImpl implements AdjustmentsService.
+	adjustmentsImpl struct {
 		client *Client
 	}
 
@@ -80,11 +94,44 @@ func (a Adjustment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.Encode(am)
 }
 
+// Validate checks the adjustment for mistakes Recurly would reject, such as
+// a missing or unknown currency or a negative quantity. Unit amounts may be
+// negative, for credits. It returns a *ValidationError listing every problem
+// found, or nil. See Client.ValidateRequests.
+func (a Adjustment) Validate() error {
+	var v validation
+	v.required("adjustment.currency", a.Currency != "")
+	v.currency("adjustment.currency", a.Currency)
+	v.nonNegative("adjustment.quantity", a.Quantity)
+
+	return v.err()
+}
+
+// RefundLineItem returns a RefundLineItem for quantity units of the
+// adjustment. A quantity of 0 refunds the adjustment's full quantity.
+func (a Adjustment) RefundLineItem(quantity int, prorate bool) RefundLineItem {
+	if quantity <= 0 {
+		quantity = a.Quantity
+	}
+
+	return RefundLineItem{
+		UUID:     a.UUID,
+		Quantity: quantity,
+		Prorate:  NewBool(prorate),
+	}
+}
+
 // List retrieves all charges and credits issued for an account
 // https://docs.recurly.com/api/adjustments#list-adjustments
-func (service AdjustmentsService) List(accountCode string, params Params) (*Response, []Adjustment, error) {
+func (service adjustmentsImpl) List(accountCode string, params Params) (*Response, []Adjustment, error) {
+	return service.ListContext(context.Background(), accountCode, params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service adjustmentsImpl) ListContext(ctx context.Context, accountCode string, params Params) (*Response, []Adjustment, error) {
+	ctx = withOperation(ctx, "Adjustments.List", "accounts/{account_code}/adjustments")
 	action := fmt.Sprintf("accounts/%s/adjustments", accountCode)
-	req, err := service.client.newRequest("GET", action, params, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -98,11 +145,24 @@ func (service AdjustmentsService) List(accountCode string, params Params) (*Resp
 	return res, a.Adjustments, err
 }
 
+// ListPager returns a Pager that walks every page of charges and credits issued for an account.
+func (service adjustmentsImpl) ListPager(accountCode string, params Params) *Pager[Adjustment] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Adjustment, error) {
+		return service.ListContext(ctx, accountCode, params)
+	})
+}
+
 // Get returns information about a single adjustment.
 // https://docs.recurly.com/api/adjustments#get-adjustments
-func (service AdjustmentsService) Get(uuid string) (*Response, Adjustment, error) {
+func (service adjustmentsImpl) Get(uuid string) (*Response, Adjustment, error) {
+	return service.GetContext(context.Background(), uuid)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service adjustmentsImpl) GetContext(ctx context.Context, uuid string) (*Response, Adjustment, error) {
+	ctx = withOperation(ctx, "Adjustments.Get", "adjustments/{uuid}")
 	action := fmt.Sprintf("adjustments/%s", uuid)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Adjustment{}, err
 	}
@@ -119,9 +179,15 @@ func (service AdjustmentsService) Get(uuid string) (*Response, Adjustment, error
 // posting an invoice. Charges may be removed from an account if they have
 // not been invoiced.
 // https://docs.recurly.com/api/adjustments#create-adjustment
-func (service AdjustmentsService) Create(accountCode string, a Adjustment) (*Response, Adjustment, error) {
+func (service adjustmentsImpl) Create(accountCode string, a Adjustment) (*Response, Adjustment, error) {
+	return service.CreateContext(context.Background(), accountCode, a)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service adjustmentsImpl) CreateContext(ctx context.Context, accountCode string, a Adjustment) (*Response, Adjustment, error) {
+	ctx = withOperation(ctx, "Adjustments.Create", "accounts/{account_code}/adjustments")
 	action := fmt.Sprintf("accounts/%s/adjustments", accountCode)
-	req, err := service.client.newRequest("POST", action, nil, a)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, a)
 	if err != nil {
 		return nil, Adjustment{}, err
 	}
@@ -129,14 +195,20 @@ func (service AdjustmentsService) Create(accountCode string, a Adjustment) (*Res
 	var dest Adjustment
 	res, err := service.client.do(req, &dest)
 
-	return res, a, err
+	return res, dest, err
 }
 
 // Delete removes a non-invoiced adjustment from an account.
 // https://docs.recurly.com/api/adjustments#delete-adjustment
-func (service AdjustmentsService) Delete(uuid string) (*Response, error) {
+func (service adjustmentsImpl) Delete(uuid string) (*Response, error) {
+	return service.DeleteContext(context.Background(), uuid)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service adjustmentsImpl) DeleteContext(ctx context.Context, uuid string) (*Response, error) {
+	ctx = withOperation(ctx, "Adjustments.Delete", "adjustments/{uuid}")
 	action := fmt.Sprintf("adjustments/%s", uuid)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,15 +1,34 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 )
 
 type (
 	// BillingService handles all interaction with the billing info portion
 	// of the recurly API.
-	BillingService struct {
+	BillingService interface {
+		Get(accountCode string) (*Response, Billing, error)
+		GetContext(ctx context.Context, accountCode string) (*Response, Billing, error)
+		Create(accountCode string, b Billing) (*Response, Billing, error)
+		CreateContext(ctx context.Context, accountCode string, b Billing) (*Response, Billing, error)
+		CreateWithToken(accountCode string, token string) (*Response, Billing, error)
+		CreateWithTokenContext(ctx context.Context, accountCode string, token string) (*Response, Billing, error)
+		Update(accountCode string, b Billing) (*Response, Billing, error)
+		UpdateContext(ctx context.Context, accountCode string, b Billing) (*Response, Billing, error)
+		UpdateWithToken(accountCode string, token string) (*Response, Billing, error)
+		UpdateWithTokenContext(ctx context.Context, accountCode string, token string) (*Response, Billing, error)
+		Clear(accountCode string) (*Response, error)
+		ClearContext(ctx context.Context, accountCode string) (*Response, error)
+	}
+
+	// billingImpl implements BillingService.
+	billingImpl struct {
 		client *Client
 	}
 
@@ -31,15 +50,18 @@ type (
 		IPAddressCountry string   `xml:"ip_address_country,omitempty"`
 
 		// Credit Card Info
-		FirstSix int    `xml:"first_six,omitempty"`
-		LastFour int    `xml:"last_four,omitempty"`
+		// Note: card numbers can be up to 19 digits and verification values
+		// may start with zeros, so both are strings. FirstSix and LastFour
+		// are read only, and are strings for the same reason.
+		FirstSix string `xml:"first_six,omitempty"`
+		LastFour string `xml:"last_four,omitempty"`
 		CardType string `xml:"card_type,omitempty"`
-		Number   int    `xml:"number,omitempty"`
+		Number   string `xml:"number,omitempty"`
 		Month    int    `xml:"month,omitempty"`
 		Year     int    `xml:"year,omitempty"`
 		// VerificationValue is only used for create/update only. A Verification
 		// Value will never be returned on read.
-		VerificationValue int `xml:"verification_value,omitempty"`
+		VerificationValue string `xml:"verification_value,omitempty"`
 
 		// Paypal
 		PaypalAgreementID string `xml:"paypal_billing_agreement_id,omitempty"`
@@ -55,30 +77,172 @@ type (
 		AccountNumber string `xml:"account_number,omitempty"`
 		AccountType   string `xml:"account_type,omitempty"`
 
+		// SortCode is used with AccountNumber for BACS (UK) bank accounts,
+		// BSBCode for BECS (Australian) bank accounts, and IBAN alone for
+		// SEPA (European) bank accounts.
+		SortCode string `xml:"sort_code,omitempty"`
+		BSBCode  string `xml:"bsb_code,omitempty"`
+		IBAN     string `xml:"iban,omitempty"`
+
 		// Token is used for create/update only. A token will never be returned
 		// on read.
 		Token string `xml:"token_id,omitempty"`
 	}
 )
 
-// Type returns the billing info type. Currently options: card, bank, ""
-func (b Billing) Type() string {
-	if b.FirstSix > 0 && b.LastFour > 0 && b.Month > 0 && b.Year > 0 {
-		return "card"
+// Billing info types returned by Billing.Type.
+const (
+	BillingTypeCard   = "card"
+	BillingTypePayPal = "paypal"
+	BillingTypeAmazon = "amazon"
+
+	// BillingTypeBank is a US bank account, charged through ACH.
+	BillingTypeBank = "bank"
+	BillingTypeBACS = "bacs"
+	BillingTypeBECS = "becs"
+	BillingTypeSEPA = "sepa"
+)
+
+// UnmarshalXML decodes billing info. Expiration months and years that aren't
+// numbers, such as the blank values returned for bank accounts, are read as 0
+// instead of failing the whole response.
+func (b *Billing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Fields has Billing's fields but not this method, so decoding into it
+	// doesn't recurse. It is exported so encoding/xml can set the embedded
+	// fields.
+	type Fields Billing
+	var v struct {
+		Fields
+		Month string `xml:"month"`
+		Year  string `xml:"year"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
 	}
 
-	if b.NameOnAccount != "" && b.RoutingNumber != "" && b.AccountNumber != "" {
-		return "bank"
+	*b = Billing(v.Fields)
+	b.XMLName = start.Name
+	b.Month, _ = strconv.Atoi(strings.TrimSpace(v.Month))
+	b.Year, _ = strconv.Atoi(strings.TrimSpace(v.Year))
+
+	return nil
+}
+
+// Type returns the payment method of the billing info, one of the
+// BillingType constants, or "" if it can't be determined. It works on billing
+// info read from Recurly as well as billing info about to be sent.
+func (b Billing) Type() string {
+	switch {
+	case b.PaypalAgreementID != "":
+		return BillingTypePayPal
+	case b.AmazonAgreementID != "":
+		return BillingTypeAmazon
+	case b.IBAN != "":
+		return BillingTypeSEPA
+	case b.SortCode != "":
+		return BillingTypeBACS
+	case b.BSBCode != "":
+		return BillingTypeBECS
+	case b.RoutingNumber != "":
+		return BillingTypeBank
+	case b.Number != "" || b.CardType != "" || (b.FirstSix != "" && b.LastFour != ""):
+		return BillingTypeCard
 	}
 
 	return ""
 }
 
+// Validate checks that billing info about to be created or updated uses a
+// single payment method, and that card numbers and verification values are
+// numeric. Fields from different payment methods, such as a card number with
+// an IBAN, or a token with a card number, are rejected. It returns a
+// *ValidationError describing the first problem found. Create and Update call
+// Validate before sending any request.
+func (b Billing) Validate() error {
+	fields := []struct {
+		name   string
+		method string
+		set    bool
+	}{
+		{"token_id", "token", b.Token != ""},
+		{"number", BillingTypeCard, b.Number != ""},
+		{"verification_value", BillingTypeCard, b.VerificationValue != ""},
+		{"month", BillingTypeCard, b.Month != 0},
+		{"year", BillingTypeCard, b.Year != 0},
+		{"paypal_billing_agreement_id", BillingTypePayPal, b.PaypalAgreementID != ""},
+		{"amazon_billing_agreement_id", BillingTypeAmazon, b.AmazonAgreementID != ""},
+		{"routing_number", BillingTypeBank, b.RoutingNumber != ""},
+		{"account_type", BillingTypeBank, b.AccountType != ""},
+		{"sort_code", BillingTypeBACS, b.SortCode != ""},
+		{"bsb_code", BillingTypeBECS, b.BSBCode != ""},
+		{"iban", BillingTypeSEPA, b.IBAN != ""},
+	}
+
+	var method, first string
+	for _, f := range fields {
+		if !f.set {
+			continue
+		} else if method == "" {
+			method, first = f.method, f.name
+		} else if f.method != method {
+			return billingError(f.name, "can't be combined with "+first)
+		}
+	}
+
+	// Account numbers are shared by ACH, BACS and BECS bank accounts, and
+	// names on accounts by all bank accounts.
+	switch method {
+	case "token", BillingTypeCard, BillingTypePayPal, BillingTypeAmazon:
+		if b.AccountNumber != "" {
+			return billingError("account_number", "can't be combined with "+first)
+		} else if b.NameOnAccount != "" {
+			return billingError("name_on_account", "can't be combined with "+first)
+		}
+	case BillingTypeSEPA:
+		if b.AccountNumber != "" {
+			return billingError("account_number", "can't be combined with "+first)
+		}
+	}
+
+	if !digits(b.Number) {
+		return billingError("number", "must only contain digits")
+	} else if !digits(b.VerificationValue) {
+		return billingError("verification_value", "must only contain digits")
+	} else if b.Month < 0 || b.Month > 12 {
+		return billingError("month", "must be between 1 and 12")
+	}
+
+	return nil
+}
+
+// billingError returns a *ValidationError for a billing info field.
+func billingError(field string, message string) error {
+	var v validation
+	v.add("billing_info."+field, "invalid", message)
+	return v.err()
+}
+
+// digits returns true if s is empty or only contains the digits 0-9.
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // Get returns only the account's current billing information.
 // https://docs.recurly.com/api/billing-info#lookup-billing-info
-func (service BillingService) Get(accountCode string) (*Response, Billing, error) {
+func (service billingImpl) Get(accountCode string) (*Response, Billing, error) {
+	return service.GetContext(context.Background(), accountCode)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service billingImpl) GetContext(ctx context.Context, accountCode string) (*Response, Billing, error) {
+	ctx = withOperation(ctx, "Billing.Get", "accounts/{account_code}/billing_info")
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Billing{}, err
 	}
@@ -94,9 +258,19 @@ func (service BillingService) Get(accountCode string) (*Response, Billing, error
 // method instead.
 // https://dev.recurly.com/docs/create-an-accounts-billing-info-credit-card
 // https://dev.recurly.com/docs/create-an-accounts-billing-info-bank-account
-func (service BillingService) Create(accountCode string, b Billing) (*Response, Billing, error) {
+func (service billingImpl) Create(accountCode string, b Billing) (*Response, Billing, error) {
+	return service.CreateContext(context.Background(), accountCode, b)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service billingImpl) CreateContext(ctx context.Context, accountCode string, b Billing) (*Response, Billing, error) {
+	ctx = withOperation(ctx, "Billing.Create", "accounts/{account_code}/billing_info")
+	if err := b.Validate(); err != nil {
+		return nil, Billing{}, err
+	}
+
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("POST", action, nil, b)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, b)
 	if err != nil {
 		return nil, Billing{}, err
 	}
@@ -110,9 +284,15 @@ func (service BillingService) Create(accountCode string, b Billing) (*Response,
 // CreateWithToken creates an account's billing information using a token
 // generated by Recurly.js. Returns the account's created Billing Information.
 // https://docs.recurly.com/api/billing-info#create-billing-info-token
-func (service BillingService) CreateWithToken(accountCode string, token string) (*Response, Billing, error) {
+func (service billingImpl) CreateWithToken(accountCode string, token string) (*Response, Billing, error) {
+	return service.CreateWithTokenContext(context.Background(), accountCode, token)
+}
+
+// CreateWithTokenContext is the same as CreateWithToken, but uses ctx for the request.
+func (service billingImpl) CreateWithTokenContext(ctx context.Context, accountCode string, token string) (*Response, Billing, error) {
+	ctx = withOperation(ctx, "Billing.CreateWithToken", "accounts/{account_code}/billing_info")
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("POST", action, nil, Billing{Token: token})
+	req, err := service.client.newRequest(ctx, "POST", action, nil, Billing{Token: token})
 	if err != nil {
 		return nil, Billing{}, err
 	}
@@ -128,7 +308,13 @@ func (service BillingService) CreateWithToken(accountCode string, token string)
 // UpdateWithToken method instead.
 // https://dev.recurly.com/docs/update-an-accounts-billing-info-credit-card
 // https://dev.recurly.com/docs/update-an-accounts-billing-info-bank-account
-func (service BillingService) Update(accountCode string, b Billing) (*Response, Billing, error) {
+func (service billingImpl) Update(accountCode string, b Billing) (*Response, Billing, error) {
+	return service.UpdateContext(context.Background(), accountCode, b)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service billingImpl) UpdateContext(ctx context.Context, accountCode string, b Billing) (*Response, Billing, error) {
+	ctx = withOperation(ctx, "Billing.Update", "accounts/{account_code}/billing_info")
 	// Create clean billing object with write-only fields to avoid errors
 	// like sending additional/unknown/read-only fields.
 	clean := Billing{
@@ -151,10 +337,16 @@ func (service BillingService) Update(accountCode string, b Billing) (*Response,
 		RoutingNumber:     b.RoutingNumber,
 		AccountNumber:     b.AccountNumber,
 		AccountType:       b.AccountType,
+		SortCode:          b.SortCode,
+		BSBCode:           b.BSBCode,
+		IBAN:              b.IBAN,
+	}
+	if err := clean.Validate(); err != nil {
+		return nil, Billing{}, err
 	}
 
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("PUT", action, nil, clean)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, clean)
 	if err != nil {
 		return nil, Billing{}, err
 	}
@@ -168,9 +360,15 @@ func (service BillingService) Update(accountCode string, b Billing) (*Response,
 // UpdateWithToken updates an account's billing information using a token
 // generated by Recurly.js. Returns the account's created Billing Information.
 // https://docs.recurly.com/api/billing-info#update-billing-info-token
-func (service BillingService) UpdateWithToken(accountCode string, token string) (*Response, Billing, error) {
+func (service billingImpl) UpdateWithToken(accountCode string, token string) (*Response, Billing, error) {
+	return service.UpdateWithTokenContext(context.Background(), accountCode, token)
+}
+
+// UpdateWithTokenContext is the same as UpdateWithToken, but uses ctx for the request.
+func (service billingImpl) UpdateWithTokenContext(ctx context.Context, accountCode string, token string) (*Response, Billing, error) {
+	ctx = withOperation(ctx, "Billing.UpdateWithToken", "accounts/{account_code}/billing_info")
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("PUT", action, nil, Billing{Token: token})
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, Billing{Token: token})
 	if err != nil {
 		return nil, Billing{}, err
 	}
@@ -185,9 +383,15 @@ func (service BillingService) UpdateWithToken(accountCode string, token string)
 // has a subscription, the renewal will go into past due unless you update the
 // billing info before the renewal occurs.
 // https://docs.recurly.com/api/billing-info#clear-billing-info
-func (service BillingService) Clear(accountCode string) (*Response, error) {
+func (service billingImpl) Clear(accountCode string) (*Response, error) {
+	return service.ClearContext(context.Background(), accountCode)
+}
+
+// ClearContext is the same as Clear, but uses ctx for the request.
+func (service billingImpl) ClearContext(ctx context.Context, accountCode string) (*Response, error) {
+	ctx = withOperation(ctx, "Billing.Clear", "accounts/{account_code}/billing_info")
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -28,8 +28,10 @@ func TestBillingEncoding(t *testing.T) {
 		map[string]interface{}{"struct": Billing{Phone: "555-555-5555"}, "xml": "<billing_info><phone>555-555-5555</phone></billing_info>"},
 		map[string]interface{}{"struct": Billing{VATNumber: "abc"}, "xml": "<billing_info><vat_number>abc</vat_number></billing_info>"},
 		map[string]interface{}{"struct": Billing{IPAddress: net.ParseIP("127.0.0.1")}, "xml": "<billing_info><ip_address>127.0.0.1</ip_address></billing_info>"},
-		map[string]interface{}{"struct": Billing{Number: 4111111111111111, Month: 5, Year: 2020, VerificationValue: 111}, "xml": "<billing_info><number>4111111111111111</number><month>5</month><year>2020</year><verification_value>111</verification_value></billing_info>"},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111", Month: 5, Year: 2020, VerificationValue: "111"}, "xml": "<billing_info><number>4111111111111111</number><month>5</month><year>2020</year><verification_value>111</verification_value></billing_info>"},
 		map[string]interface{}{"struct": Billing{RoutingNumber: "065400137", AccountNumber: "0123456789", AccountType: "checking"}, "xml": "<billing_info><routing_number>065400137</routing_number><account_number>0123456789</account_number><account_type>checking</account_type></billing_info>"},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Ltd", SortCode: "200000", AccountNumber: "05779911"}, "xml": "<billing_info><name_on_account>Acme, Ltd</name_on_account><account_number>05779911</account_number><sort_code>200000</sort_code></billing_info>"},
+		map[string]interface{}{"struct": Billing{IBAN: "DE89370400440532013000"}, "xml": "<billing_info><iban>DE89370400440532013000</iban></billing_info>"},
 	}
 
 	for _, s := range suite {
@@ -46,31 +48,86 @@ func TestBillingEncoding(t *testing.T) {
 }
 
 func TestBillingType(t *testing.T) {
-	b := Billing{
-		FirstSix: 411111,
-		LastFour: 1111,
-		Month:    11,
-		Year:     2020,
+	suite := []map[string]interface{}{
+		map[string]interface{}{"struct": Billing{FirstSix: "411111", LastFour: "1111", Month: 11, Year: 2020}, "type": BillingTypeCard},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111"}, "type": BillingTypeCard},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Inc", RoutingNumber: "123456780", AccountNumber: "111111111"}, "type": BillingTypeBank},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Ltd", SortCode: "200000", AccountNumber: "55779911"}, "type": BillingTypeBACS},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Pty", BSBCode: "082082", AccountNumber: "012345678"}, "type": BillingTypeBECS},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, GmbH", IBAN: "DE89370400440532013000"}, "type": BillingTypeSEPA},
+		map[string]interface{}{"struct": Billing{PaypalAgreementID: "B-1234"}, "type": BillingTypePayPal},
+		map[string]interface{}{"struct": Billing{AmazonAgreementID: "C01-1234"}, "type": BillingTypeAmazon},
+		map[string]interface{}{"struct": Billing{}, "type": ""},
 	}
 
-	b2 := Billing{
-		NameOnAccount: "Acme, Inc",
-		RoutingNumber: "123456780",
-		AccountNumber: "111111111",
+	for _, s := range suite {
+		if given := s["struct"].(Billing).Type(); given != s["type"] {
+			t.Errorf("TestBillingType Error: Expected %q, given %q for %#v", s["type"], given, s["struct"])
+		}
+	}
+}
+
+func TestBillingValidate(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"struct": Billing{}, "field": ""},
+		map[string]interface{}{"struct": Billing{FirstName: "Verena", Number: "4111111111111111", Month: 10, Year: 2020, VerificationValue: "011"}, "field": ""},
+		map[string]interface{}{"struct": Billing{Number: "6011000990139424112"}, "field": ""},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Inc", RoutingNumber: "065400137", AccountNumber: "0123456789", AccountType: "checking"}, "field": ""},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, Ltd", SortCode: "200000", AccountNumber: "55779911"}, "field": ""},
+		map[string]interface{}{"struct": Billing{NameOnAccount: "Acme, GmbH", IBAN: "DE89370400440532013000"}, "field": ""},
+		map[string]interface{}{"struct": Billing{Token: "abc", FirstName: "Verena"}, "field": ""},
+		map[string]interface{}{"struct": Billing{Token: "abc", Number: "4111111111111111"}, "field": "billing_info.number"},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111", IBAN: "DE89370400440532013000"}, "field": "billing_info.iban"},
+		map[string]interface{}{"struct": Billing{PaypalAgreementID: "B-1234", Month: 10}, "field": "billing_info.paypal_billing_agreement_id"},
+		map[string]interface{}{"struct": Billing{RoutingNumber: "065400137", SortCode: "200000"}, "field": "billing_info.sort_code"},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111", AccountNumber: "0123456789"}, "field": "billing_info.account_number"},
+		map[string]interface{}{"struct": Billing{IBAN: "DE89370400440532013000", AccountNumber: "0123456789"}, "field": "billing_info.account_number"},
+		map[string]interface{}{"struct": Billing{AmazonAgreementID: "C01-1234", NameOnAccount: "Acme, Inc"}, "field": "billing_info.name_on_account"},
+		map[string]interface{}{"struct": Billing{Number: "4111 1111 1111 1111"}, "field": "billing_info.number"},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111", VerificationValue: "1a1"}, "field": "billing_info.verification_value"},
+		map[string]interface{}{"struct": Billing{Number: "4111111111111111", Month: 13}, "field": "billing_info.month"},
 	}
 
-	b3 := Billing{}
+	for _, s := range suite {
+		err := s["struct"].(Billing).Validate()
+		if s["field"] == "" {
+			if err != nil {
+				t.Errorf("TestBillingValidate Error: Expected %#v to be valid, given %s", s["struct"], err)
+			}
+			continue
+		}
+
+		verr, ok := err.(*ValidationError)
+		if !ok || len(verr.Errors) != 1 || verr.Errors[0].Field != s["field"] {
+			t.Errorf("TestBillingValidate Error: Expected error on %s for %#v, given %v", s["field"], s["struct"], err)
+		}
+	}
+}
 
-	if b.Type() != "card" {
-		t.Errorf("TestBillingType Error: Expected card billing info to return card, given %s", b.Type())
+func TestBillingDecoding(t *testing.T) {
+	given := `<billing_info type="bank_account">
+		<first_name>Verena</first_name>
+		<month nil="nil"></month>
+		<year> 2020 </year>
+		<first_six>400000</first_six>
+		<last_four>0077</last_four>
+	</billing_info>`
+
+	var b Billing
+	if err := xml.Unmarshal([]byte(given), &b); err != nil {
+		t.Fatalf("TestBillingDecoding Error: Error decoding billing info. Err: %s", err)
 	}
 
-	if b2.Type() != "bank" {
-		t.Errorf("TestBillingType Error: Expected bank billing info to return bank, given %s", b.Type())
+	expected := Billing{
+		XMLName:   xml.Name{Local: "billing_info"},
+		FirstName: "Verena",
+		Year:      2020,
+		FirstSix:  "400000",
+		LastFour:  "0077",
 	}
 
-	if b3.Type() != "" {
-		t.Errorf("TestingBillingtype Error: Expected billing info that is neither card or bank to return \"\", given %s", b3.Type())
+	if !reflect.DeepEqual(expected, b) {
+		t.Errorf("TestBillingDecoding Error: Expected %#v, given %#v", expected, b)
 	}
 }
 
@@ -131,8 +188,8 @@ func TestBillingGet(t *testing.T) {
 		CardType:         "Visa",
 		Year:             2015,
 		Month:            11,
-		FirstSix:         411111,
-		LastFour:         1111,
+		FirstSix:         "411111",
+		LastFour:         "1111",
 	}
 
 	if !reflect.DeepEqual(expected, b) {
@@ -205,8 +262,8 @@ func TestBillingCreateWithToken(t *testing.T) {
 		CardType:         "Visa",
 		Year:             2015,
 		Month:            11,
-		FirstSix:         411111,
-		LastFour:         1111,
+		FirstSix:         "411111",
+		LastFour:         "1111",
 	}
 
 	if !reflect.DeepEqual(expected, b) {
@@ -241,7 +298,7 @@ func TestBillingCreateWithCC(t *testing.T) {
 		State:     "CA",
 		Zip:       "94105",
 		Country:   "US",
-		Number:    4111111111111111,
+		Number:    "4111111111111111",
 		Month:     10,
 		Year:      2020,
 	})
@@ -255,6 +312,26 @@ func TestBillingCreateWithCC(t *testing.T) {
 	}
 }
 
+func TestBillingCreateMixedPaymentMethods(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/billing_info", func(rw http.ResponseWriter, r *http.Request) {
+		t.Error("TestBillingCreateMixedPaymentMethods Error: Expected no request to be sent")
+	})
+
+	r, _, err := client.Billing.Create("1", Billing{
+		Number:        "4111111111111111",
+		Month:         10,
+		Year:          2020,
+		RoutingNumber: "065400137",
+	})
+
+	if _, ok := err.(*ValidationError); !ok || r != nil {
+		t.Errorf("TestBillingCreateMixedPaymentMethods Error: Expected validation error without a response, given %v (%+v)", err, r)
+	}
+}
+
 func TestBillingCreateWithBankAccount(t *testing.T) {
 	setup()
 	defer teardown()
@@ -382,15 +459,15 @@ func TestBillingUpdateWithCC(t *testing.T) {
 		State:     "CA",
 		Zip:       "94105",
 		Country:   "US",
-		Number:    4111111111111111,
+		Number:    "4111111111111111",
 		Month:     10,
 		Year:      2020,
 
 		// Add additional fields that should be removed
 		Token:             "abc",
 		IPAddressCountry:  "US",
-		FirstSix:          411111,
-		LastFour:          1111,
+		FirstSix:          "411111",
+		LastFour:          "1111",
 		CardType:          "visa",
 		PaypalAgreementID: "ppl",
 		AmazonAgreementID: "asdfb",
@@ -440,8 +517,8 @@ func TestBillingUpdateWithBankAccount(t *testing.T) {
 		// Add additional fields that should be removed
 		Token:             "abc",
 		IPAddressCountry:  "US",
-		FirstSix:          111111,
-		LastFour:          1111,
+		FirstSix:          "111111",
+		LastFour:          "1111",
 		CardType:          "visa",
 		PaypalAgreementID: "ppl",
 		AmazonAgreementID: "asdfb",
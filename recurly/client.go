@@ -2,12 +2,15 @@ package recurly
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 const (
@@ -23,27 +26,85 @@ type (
 		// subdomain is your account's sub domain used for authentication.
 		subDomain string
 
-		// apiKey is your account's API key used for authentication.
-		apiKey string
+		// apiKey is your account's API key used for authentication. It is
+		// shared by pointer so it can be rotated with SetAPIKey while the
+		// client is in use.
+		apiKey *secret
 
 		// BaseURL is the base url for api requests.
 		BaseURL string
 
-		// Services used for talking with different parts of the Recurly API
-		Accounts      AccountsService
-		Adjustments   AdjustmentsService
-		Billing       BillingService
-		Coupons       CouponsService
-		Redemptions   RedemptionsService
-		Invoices      InvoicesService
-		Plans         PlansService
-		AddOns        AddOnsService
-		Subscriptions SubscriptionsService
-		Transactions  TransactionsService
+		// TypedErrors makes unsuccessful API calls return an error describing
+		// the failure, such as *ValidationError or *NotFoundError, in
+		// addition to the Response. When false, API failures are only
+		// reported through the Response and the returned error is nil.
+		TypedErrors bool
+
+		// ValidateRequests makes service methods call Validate on payloads
+		// that have it, such as NewSubscription and Plan, before sending
+		// them. Updates call ValidateUpdate instead when the payload has it,
+		// since fields required on create may be left out of an update.
+		// Invalid payloads return the *ValidationError without making a
+		// request, and a nil Response.
+		ValidateRequests bool
+
+		// Retry enables automatic retries of failed requests. When nil,
+		// each request is attempted exactly once.
+		Retry *RetryPolicy
+
+		// RateLimiter, if set, makes requests wait to stay under Recurly's
+		// rate limit. See RateLimiter for details.
+		RateLimiter *RateLimiter
+
+		// Middleware wraps every API call, in order, with the first
+		// middleware outermost. See Middleware for details.
+		Middleware []Middleware
+
+		// Services used for talking with different parts of the Recurly API.
+		// Each service is an interface so it can be replaced in tests, e.g.
+		// with the implementations in the mock package.
+		Accounts          AccountsService
+		Adjustments       AdjustmentsService
+		Billing           BillingService
+		Coupons           CouponsService
+		Redemptions       RedemptionsService
+		Invoices          InvoicesService
+		Plans             PlansService
+		AddOns            AddOnsService
+		Subscriptions     SubscriptionsService
+		Transactions      TransactionsService
+		ShippingAddresses ShippingAddressesService
+		MeasuredUnits     MeasuredUnitsService
+		Usage             UsageService
+	}
+
+	// API is the set of services of a Client. Code that depends on API
+	// rather than *Client can be given the mock package's Client in tests.
+	API interface {
+		AccountsService() AccountsService
+		AdjustmentsService() AdjustmentsService
+		BillingService() BillingService
+		CouponsService() CouponsService
+		RedemptionsService() RedemptionsService
+		InvoicesService() InvoicesService
+		PlansService() PlansService
+		AddOnsService() AddOnsService
+		SubscriptionsService() SubscriptionsService
+		TransactionsService() TransactionsService
+		ShippingAddressesService() ShippingAddressesService
+		MeasuredUnitsService() MeasuredUnitsService
+		UsageService() UsageService
 	}
 
 	// Params are used to send parameters with the request.
 	Params map[string]interface{}
+
+	// secret holds a value, such as an API key, that can be replaced while
+	// other goroutines are reading it.
+	secret struct {
+		mu    sync.RWMutex
+		value string
+	}
 )
 
 // NewClient creates a new Recurly API Client.
@@ -55,26 +116,108 @@ func NewClient(subDomain string, apiKey string, httpClient *http.Client) *Client
 	c := &Client{
 		client:    httpClient,
 		subDomain: subDomain,
-		apiKey:    apiKey,
+		apiKey:    newSecret(apiKey),
 		BaseURL:   fmt.Sprintf(defaultBaseURL, subDomain),
 	}
 
-	c.Accounts = AccountsService{client: c}
-	c.Adjustments = AdjustmentsService{client: c}
-	c.Billing = BillingService{client: c}
-	c.Coupons = CouponsService{client: c}
-	c.Redemptions = RedemptionsService{client: c}
-	c.Invoices = InvoicesService{client: c}
-	c.Plans = PlansService{client: c}
-	c.AddOns = AddOnsService{client: c}
-	c.Subscriptions = SubscriptionsService{client: c}
-	c.Transactions = TransactionsService{client: c}
+	c.Accounts = accountsImpl{client: c}
+	c.Adjustments = adjustmentsImpl{client: c}
+	c.Billing = billingImpl{client: c}
+	c.Coupons = couponsImpl{client: c}
+	c.Redemptions = redemptionsImpl{client: c}
+	c.Invoices = invoicesImpl{client: c}
+	c.Plans = plansImpl{client: c}
+	c.AddOns = addOnsImpl{client: c}
+	c.Subscriptions = subscriptionsImpl{client: c}
+	c.Transactions = transactionsImpl{client: c}
+	c.ShippingAddresses = shippingAddressesImpl{client: c}
+	c.MeasuredUnits = measuredUnitsImpl{client: c}
+	c.Usage = usageImpl{client: c}
 
 	return c
 }
 
+var _ API = (*Client)(nil)
+
+// AccountsService returns c.Accounts.
+func (c *Client) AccountsService() AccountsService {
+	return c.Accounts
+}
+
+// AdjustmentsService returns c.Adjustments.
+func (c *Client) AdjustmentsService() AdjustmentsService {
+	return c.Adjustments
+}
+
+// BillingService returns c.Billing.
+func (c *Client) BillingService() BillingService {
+	return c.Billing
+}
+
+// CouponsService returns c.Coupons.
+func (c *Client) CouponsService() CouponsService {
+	return c.Coupons
+}
+
+// RedemptionsService returns c.Redemptions.
+func (c *Client) RedemptionsService() RedemptionsService {
+	return c.Redemptions
+}
+
+// InvoicesService returns c.Invoices.
+func (c *Client) InvoicesService() InvoicesService {
+	return c.Invoices
+}
+
+// PlansService returns c.Plans.
+func (c *Client) PlansService() PlansService {
+	return c.Plans
+}
+
+// AddOnsService returns c.AddOns.
+func (c *Client) AddOnsService() AddOnsService {
+	return c.AddOns
+}
+
+// SubscriptionsService returns c.Subscriptions.
+func (c *Client) SubscriptionsService() SubscriptionsService {
+	return c.Subscriptions
+}
+
+// TransactionsService returns c.Transactions.
+func (c *Client) TransactionsService() TransactionsService {
+	return c.Transactions
+}
+
+// ShippingAddressesService returns c.ShippingAddresses.
+func (c *Client) ShippingAddressesService() ShippingAddressesService {
+	return c.ShippingAddresses
+}
+
+// MeasuredUnitsService returns c.MeasuredUnits.
+func (c *Client) MeasuredUnitsService() MeasuredUnitsService {
+	return c.MeasuredUnits
+}
+
+// UsageService returns c.Usage.
+func (c *Client) UsageService() UsageService {
+	return c.Usage
+}
+
+// SetAPIKey replaces the API key used to authenticate requests. It is safe
+// to call while requests are being made: requests that have already been
+// created keep the key they were created with.
+func (c *Client) SetAPIKey(apiKey string) {
+	if c.apiKey == nil {
+		c.apiKey = newSecret(apiKey)
+		return
+	}
+	c.apiKey.set(apiKey)
+}
+
 // newRequest creates an authenticated API request that is ready to send.
-func (c Client) newRequest(method string, action string, params Params, body interface{}) (*http.Request, error) {
+// The request is bound to ctx so it can be canceled or given a deadline.
+func (c Client) newRequest(ctx context.Context, method string, action string, params Params, body interface{}) (*http.Request, error) {
 	method = strings.ToUpper(method)
 	endpoint := fmt.Sprintf("%sv2/%s", c.BaseURL, action)
 
@@ -88,7 +231,14 @@ func (c Client) newRequest(method string, action string, params Params, body int
 		endpoint += "?" + qs.Encode()
 	}
 
-	// Request body
+	if c.ValidateRequests {
+		if err := validateBody(method, body); err != nil {
+			return nil, err
+		}
+	}
+
+	// Request body. Encoding into a bytes.Buffer lets the request body be
+	// rebuilt through req.GetBody when the request is retried.
 	var buf io.ReadWriter
 	if body != nil {
 		buf = new(bytes.Buffer)
@@ -98,15 +248,18 @@ func (c Client) newRequest(method string, action string, params Params, body int
 		}
 	}
 
-	req, err := http.NewRequest(method, endpoint, buf)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, buf)
+	if err != nil {
+		return nil, err
+	}
 
-	req.SetBasicAuth(c.apiKey, "")
+	req.SetBasicAuth(c.apiKey.get(), "")
 	req.Header.Set("Accept", "application/xml")
 	if req.Method == "POST" || req.Method == "PUT" {
 		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
 	}
 
-	return req, err
+	return req, nil
 }
 
 // do takes a prepared API request and makes the API call to Recurly.
@@ -114,65 +267,159 @@ func (c Client) newRequest(method string, action string, params Params, body int
 // as parse any validation errors that may have occurred.
 // It returns a Response object that provides a wrapper around http.Response
 // with some convenience methods.
+// The call is made through the client's Middleware, outermost first.
+// If the request's context is canceled or its deadline expires while the
+// request is in flight or the body is being decoded, the context's error is
+// returned.
 func (c Client) do(req *http.Request, v interface{}) (*Response, error) {
-	req.Close = true
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+	ctx := req.Context()
+
+	next := c.roundTrip
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		next = c.Middleware[i](next)
+	}
+
+	response, err := next(req)
+	if response != nil && response.Response != nil && response.Body != nil {
+		// Read the body to the end so the connection can be reused.
+		defer func(body io.ReadCloser) {
+			io.Copy(ioutil.Discard, body)
+			body.Close()
+		}(response.Body)
+	}
+	if err != nil || response == nil || response.Response == nil || response.IsError() {
+		return response, contextErr(ctx, err)
 	}
-	defer resp.Body.Close()
 
-	// @todo pagination support.
-	// How do you make cursor calls for additional pages?
-	// log.Println(res.Header.Get("x-records"))
-	// log.Println(res.Header.Get("link"))
+	if v != nil {
+		if w, ok := v.(io.Writer); ok {
+			_, err = io.Copy(w, response.Body)
+		} else {
+			err = xml.NewDecoder(response.Body).Decode(&v)
+		}
+	}
+
+	return response, contextErr(ctx, err)
+}
+
+// roundTrip sends req and parses any errors from an unsuccessful response.
+// It is the innermost RoundTripFunc of the middleware chain. The body of a
+// successful response is left unread so do can decode it.
+func (c Client) roundTrip(req *http.Request) (*Response, error) {
+	ctx := req.Context()
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, contextErr(ctx, err)
+	}
 
 	response := &Response{Response: resp}
 	if response.IsError() {
-		// Parse validation errors
-		if response.StatusCode == 422 {
-			var ve struct {
-				XMLName          xml.Name         `xml:"errors"`
-				Errors           []Error          `xml:"error"`
-				TransactionError TransactionError `xml:"transaction_error,omitempty"`
-			}
-			err = xml.NewDecoder(resp.Body).Decode(&ve)
-			if err != nil {
-				return response, err
-			}
-
-			response.Errors = ve.Errors
-			response.TransactionError = ve.TransactionError
-		} else if response.IsClientError() {
-			// Parse possible individual error message
-			var ve struct {
-				XMLName     xml.Name `xml:"error"`
-				Symbol      string   `xml:"symbol"`
-				Description string   `xml:"description"`
-			}
-			err = xml.NewDecoder(resp.Body).Decode(&ve)
-			if err != nil {
-				return response, err
-			}
-
-			response.Errors = []Error{
-				Error{
-					Symbol:  ve.Symbol,
-					Message: ve.Description,
-				},
-			}
+		// Buffer the error body so middleware can still read it after the
+		// errors have been parsed.
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return response, contextErr(ctx, err)
 		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-		return response, nil
+		// With typed errors, an error body that can't be decoded still
+		// results in an error for the status code.
+		err = decodeErrors(response)
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil && (ctx.Err() != nil || !c.TypedErrors) {
+			return response, contextErr(ctx, err)
+		}
+
+		if c.TypedErrors {
+			return response, newAPIError(response)
+		}
 	}
 
-	if v != nil {
-		if w, ok := v.(io.Writer); ok {
-			io.Copy(w, resp.Body)
-		} else {
-			err = xml.NewDecoder(resp.Body).Decode(&v)
+	return response, nil
+}
+
+// decodeErrors parses validation errors and transaction errors from the body
+// of an unsuccessful response into response.Errors and
+// response.TransactionError.
+func decodeErrors(response *Response) error {
+	// Parse validation errors
+	if response.StatusCode == 422 {
+		var ve struct {
+			XMLName          xml.Name         `xml:"errors"`
+			Errors           []Error          `xml:"error"`
+			TransactionError TransactionError `xml:"transaction_error,omitempty"`
+		}
+		if err := xml.NewDecoder(response.Body).Decode(&ve); err != nil {
+			return err
+		}
+
+		response.Errors = ve.Errors
+		response.TransactionError = ve.TransactionError
+	} else if response.IsClientError() {
+		// Parse possible individual error message
+		var ve struct {
+			XMLName     xml.Name `xml:"error"`
+			Symbol      string   `xml:"symbol"`
+			Description string   `xml:"description"`
+		}
+		if err := xml.NewDecoder(response.Body).Decode(&ve); err != nil {
+			return err
 		}
+
+		response.Errors = []Error{
+			Error{
+				Symbol:  ve.Symbol,
+				Message: ve.Description,
+			},
+		}
+	}
+
+	return nil
+}
+
+// contextErr returns the context's error in place of err if the context
+// was canceled or timed out. Reads from a response body that fail because
+// the context ended surface as generic I/O errors, so this gives callers a
+// consistent error to check against.
+func contextErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
 	}
+	return err
+}
+
+// validateBody checks a request body with its ValidateUpdate method for
+// updates, if it has one, and its Validate method otherwise.
+func validateBody(method string, body interface{}) error {
+	if v, ok := body.(interface{ ValidateUpdate() error }); ok && method == "PUT" {
+		return v.ValidateUpdate()
+	}
+	if v, ok := body.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// newSecret returns a secret holding value.
+func newSecret(value string) *secret {
+	return &secret{value: value}
+}
+
+// get returns the value, or an empty string if s is nil.
+func (s *secret) get() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
 
-	return response, err
+// set replaces the value.
+func (s *secret) set(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value = value
 }
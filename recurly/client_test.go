@@ -1,19 +1,22 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
 	expected := &Client{
 		client:    http.DefaultClient,
 		subDomain: "foo",
-		apiKey:    "bar",
+		apiKey:    newSecret("bar"),
 		BaseURL:   "https://foo.recurly.com/",
 	}
 
@@ -22,8 +25,8 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("TestNewClient Error: Expected subDomain of %s, given %s", expected.subDomain, given.subDomain)
 	}
 
-	if expected.apiKey != given.apiKey {
-		t.Errorf("TestNewClient Error: Expected apiKey of %s, given %s", expected.apiKey, given.apiKey)
+	if expected.apiKey.get() != given.apiKey.get() {
+		t.Errorf("TestNewClient Error: Expected apiKey of %s, given %s", expected.apiKey.get(), given.apiKey.get())
 	}
 
 	if expected.BaseURL != given.BaseURL {
@@ -31,10 +34,24 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClientAPI(t *testing.T) {
+	var api API = NewClient("foo", "bar", nil)
+	c := api.(*Client)
+
+	if api.AccountsService() != c.Accounts || api.SubscriptionsService() != c.Subscriptions || api.UsageService() != c.Usage {
+		t.Errorf("TestClientAPI Error: Expected accessors to return the client's services")
+	}
+
+	c.Plans = nil
+	if api.PlansService() != nil {
+		t.Errorf("TestClientAPI Error: Expected accessor to return a replaced service, given %#v", api.PlansService())
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	client = NewClient("test", "abc", nil)
 
-	req, err := client.newRequest("GET", "accounts/14579", Params{"foo": "bar"}, nil)
+	req, err := client.newRequest(context.Background(), "GET", "accounts/14579", Params{"foo": "bar"}, nil)
 	if err != nil {
 		t.Errorf("TestNewRequest Error: %v", err)
 	}
@@ -63,7 +80,7 @@ func TestNewRequest(t *testing.T) {
 		}
 	}
 
-	req, err = client.newRequest("PUT", "accounts/abc", nil, Account{Code: "abc"})
+	req, err = client.newRequest(context.Background(), "PUT", "accounts/abc", nil, Account{Code: "abc"})
 	if err != nil {
 		t.Errorf("TestNewRequest Error: %v", err)
 	}
@@ -216,3 +233,132 @@ func TestRequestUnmarshalsIntoStruct(t *testing.T) {
 		t.Errorf("TestRequestUnmarshalsIntoStruct Error: Expected address1 to be %s, given %s", expected, a.Address.Address)
 	}
 }
+
+func TestRequestWithCanceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	called := false
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := client.Accounts.GetContext(ctx, "1")
+	if err != context.Canceled {
+		t.Errorf("TestRequestWithCanceledContext Error: Expected error %v, given %v", context.Canceled, err)
+	}
+
+	if called {
+		t.Errorf("TestRequestWithCanceledContext Error: Expected request to not reach the server")
+	}
+}
+
+func TestRequestCanceledInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	reached := make(chan struct{})
+	mux.HandleFunc("/v2/subscriptions", func(rw http.ResponseWriter, r *http.Request) {
+		close(reached)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-reached
+		cancel()
+	}()
+
+	_, _, err := client.Subscriptions.CreateContext(ctx, NewSubscription{PlanCode: "gold", Currency: "USD"})
+	if err != context.Canceled {
+		t.Errorf("TestRequestCanceledInFlight Error: Expected error %v, given %v", context.Canceled, err)
+	}
+}
+
+func TestRequestCanceledDuringDecode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mux.HandleFunc("/v2/invoices/1001", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><invoice><uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>`)
+		rw.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	// Cancel the context as soon as decoding starts reading the body.
+	ctx, cancel := context.WithCancel(context.Background())
+	client.client = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err == nil {
+			resp.Body = &cancelOnReadBody{ReadCloser: resp.Body, cancel: cancel}
+		}
+		return resp, err
+	})}
+
+	r, _, err := client.Invoices.GetContext(ctx, 1001)
+	if err != context.Canceled {
+		t.Errorf("TestRequestCanceledDuringDecode Error: Expected error %v, given %v", context.Canceled, err)
+	}
+
+	if r == nil || r.StatusCode != http.StatusOK {
+		t.Errorf("TestRequestCanceledDuringDecode Error: Expected response with 200 status code, given %#v", r)
+	}
+}
+
+func TestRequestContextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mux.HandleFunc("/v2/invoices/1001", func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := client.Invoices.GetPDFContext(ctx, 1001, "")
+	if err != context.DeadlineExceeded {
+		t.Errorf("TestRequestContextDeadline Error: Expected error %v, given %v", context.DeadlineExceeded, err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// cancelOnReadBody calls cancel after the first read from the body.
+type cancelOnReadBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnReadBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.cancel()
+	return n, err
+}
@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -8,7 +9,20 @@ import (
 type (
 	// CouponsService handles communication with the coupons related methods
 	// of the recurly API.
-	CouponsService struct {
+	CouponsService interface {
+		List(params Params) (*Response, []Coupon, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Coupon, error)
+		ListPager(params Params) *Pager[Coupon]
+		Get(code string) (*Response, Coupon, error)
+		GetContext(ctx context.Context, code string) (*Response, Coupon, error)
+		Create(c Coupon) (*Response, Coupon, error)
+		CreateContext(ctx context.Context, c Coupon) (*Response, Coupon, error)
+		Delete(code string) (*Response, error)
+		DeleteContext(ctx context.Context, code string) (*Response, error)
+	}
+
+	// couponsImpl implements CouponsService.
+	couponsImpl struct {
 		client *Client
 	}
 
@@ -39,10 +53,70 @@ type (
 	}
 )
 
+// Coupon discount types.
+const (
+	CouponDiscountTypePercent   = "percent"
+	CouponDiscountTypeDollars   = "dollars"
+	CouponDiscountTypeFreeTrial = "free_trial"
+)
+
+// Validate checks the coupon for mistakes Recurly would reject, such as a
+// missing code or name, or a discount that doesn't match the discount type:
+// percent coupons need a DiscountPercent from 1 to 100, dollars coupons a
+// positive DiscountInCents, and free trial coupons neither. It returns a
+// *ValidationError listing every problem found, or nil. See
+// Client.ValidateRequests.
+func (c Coupon) Validate() error {
+	var v validation
+	v.required("coupon.coupon_code", c.Code != "")
+	v.required("coupon.name", c.Name != "")
+	v.required("coupon.discount_type", c.DiscountType != "")
+	v.oneOf("coupon.discount_type", c.DiscountType, CouponDiscountTypePercent, CouponDiscountTypeDollars, CouponDiscountTypeFreeTrial)
+
+	switch c.DiscountType {
+	case CouponDiscountTypePercent:
+		if c.DiscountPercent < 1 || c.DiscountPercent > 100 {
+			v.add("coupon.discount_percent", "inclusion", "must be between 1 and 100")
+		}
+		if c.DiscountInCents != 0 {
+			v.add("coupon.discount_in_cents", "present", "must be blank for percent coupons")
+		}
+	case CouponDiscountTypeDollars:
+		if c.DiscountInCents <= 0 {
+			v.add("coupon.discount_in_cents", "greater_than", "must be greater than 0")
+		}
+		if c.DiscountPercent != 0 {
+			v.add("coupon.discount_percent", "present", "must be blank for dollars coupons")
+		}
+	case CouponDiscountTypeFreeTrial:
+		if c.DiscountPercent != 0 {
+			v.add("coupon.discount_percent", "present", "must be blank for free trial coupons")
+		}
+		if c.DiscountInCents != 0 {
+			v.add("coupon.discount_in_cents", "present", "must be blank for free trial coupons")
+		}
+	}
+
+	if c.AppliesForMonths.Valid {
+		v.nonNegative("coupon.applies_for_months", c.AppliesForMonths.Int)
+	}
+	if c.MaxRedemptions.Valid {
+		v.nonNegative("coupon.max_redemptions", c.MaxRedemptions.Int)
+	}
+
+	return v.err()
+}
+
 // List returns a list of all the coupons on your site.
 // https://dev.recurly.com/docs/list-active-coupons
-func (service CouponsService) List(params Params) (*Response, []Coupon, error) {
-	req, err := service.client.newRequest("GET", "coupons", params, nil)
+func (service couponsImpl) List(params Params) (*Response, []Coupon, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service couponsImpl) ListContext(ctx context.Context, params Params) (*Response, []Coupon, error) {
+	ctx = withOperation(ctx, "Coupons.List", "coupons")
+	req, err := service.client.newRequest(ctx, "GET", "coupons", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -56,11 +130,24 @@ func (service CouponsService) List(params Params) (*Response, []Coupon, error) {
 	return res, c.Coupons, err
 }
 
+// ListPager returns a Pager that walks every page of coupons on your site.
+func (service couponsImpl) ListPager(params Params) *Pager[Coupon] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Coupon, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // Get returns information about an active coupon.
 // https://dev.recurly.com/docs/lookup-a-coupon
-func (service CouponsService) Get(code string) (*Response, Coupon, error) {
+func (service couponsImpl) Get(code string) (*Response, Coupon, error) {
+	return service.GetContext(context.Background(), code)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service couponsImpl) GetContext(ctx context.Context, code string) (*Response, Coupon, error) {
+	ctx = withOperation(ctx, "Coupons.Get", "coupons/{coupon_code}")
 	action := fmt.Sprintf("coupons/%s", code)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Coupon{}, err
 	}
@@ -73,8 +160,14 @@ func (service CouponsService) Get(code string) (*Response, Coupon, error) {
 
 // Create a new coupon. Coupons cannot be updated after being created.
 // https://dev.recurly.com/docs/create-coupon
-func (service CouponsService) Create(c Coupon) (*Response, Coupon, error) {
-	req, err := service.client.newRequest("POST", "coupons", nil, c)
+func (service couponsImpl) Create(c Coupon) (*Response, Coupon, error) {
+	return service.CreateContext(context.Background(), c)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service couponsImpl) CreateContext(ctx context.Context, c Coupon) (*Response, Coupon, error) {
+	ctx = withOperation(ctx, "Coupons.Create", "coupons")
+	req, err := service.client.newRequest(ctx, "POST", "coupons", nil, c)
 	if err != nil {
 		return nil, Coupon{}, err
 	}
@@ -87,9 +180,15 @@ func (service CouponsService) Create(c Coupon) (*Response, Coupon, error) {
 
 // Delete deactivates the coupon so it can no longer be redeemed.
 // https://docs.recurly.com/api/plans/add-ons#delete-addon
-func (service CouponsService) Delete(code string) (*Response, error) {
+func (service couponsImpl) Delete(code string) (*Response, error) {
+	return service.DeleteContext(context.Background(), code)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service couponsImpl) DeleteContext(ctx context.Context, code string) (*Response, error) {
+	ctx = withOperation(ctx, "Coupons.Delete", "coupons/{coupon_code}")
 	action := fmt.Sprintf("coupons/%s", code)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,189 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// TransactionErrorCategorySoft is for declines that may succeed if
+	// retried later, such as insufficient funds.
+	TransactionErrorCategorySoft = "soft"
+
+	// TransactionErrorCategoryHard is for declines that will not succeed if
+	// retried, such as an invalid card number.
+	TransactionErrorCategoryHard = "hard"
+
+	// TransactionErrorCategoryFraud is for transactions declined by fraud
+	// screening.
+	TransactionErrorCategoryFraud = "fraud"
+
+	// TransactionErrorCategoryCommunication is for errors communicating with
+	// the payment gateway.
+	TransactionErrorCategoryCommunication = "communication"
+
+	// TransactionErrorCategoryConfiguration is for errors caused by the
+	// payment gateway configuration on your site.
+	TransactionErrorCategoryConfiguration = "configuration"
+)
+
+type (
+	// ValidationError is returned when TypedErrors is enabled and Recurly
+	// rejects a request with validation errors (422).
+	ValidationError struct {
+		Response *Response
+		Errors   []Error
+	}
+
+	// TransactionFailedError is returned when TypedErrors is enabled and the
+	// payment gateway declines or fails to process a transaction. The embedded
+	// TransactionError provides helpers to check the error category.
+	TransactionFailedError struct {
+		Response *Response
+		TransactionError
+
+		// Errors holds any validation errors returned with the transaction
+		// error.
+		Errors []Error
+	}
+
+	// NotFoundError is returned when TypedErrors is enabled and the requested
+	// resource does not exist (404).
+	NotFoundError struct {
+		Response *Response
+		Symbol   string
+		Message  string
+	}
+
+	// RateLimitedError is returned when TypedErrors is enabled and the
+	// request was rejected because the API rate limit was exceeded (429).
+	RateLimitedError struct {
+		Response *Response
+
+		// RetryAfter is how long to wait before trying again, as given by
+		// the Retry-After header. It is zero if the header was not sent.
+		RetryAfter time.Duration
+	}
+
+	// ServerError is returned when TypedErrors is enabled and Recurly responds
+	// with a 5xx status code.
+	ServerError struct {
+		Response *Response
+	}
+
+	// ClientError is returned when TypedErrors is enabled for any other 4xx
+	// status code, such as 401 or 403.
+	ClientError struct {
+		Response *Response
+		Symbol   string
+		Message  string
+	}
+)
+
+// IsDeclined returns true if the transaction was declined, whether or not it
+// may succeed when retried.
+func (e TransactionError) IsDeclined() bool {
+	return e.IsSoftDecline() || e.IsHardDecline()
+}
+
+// IsSoftDecline returns true if the transaction was declined but may succeed
+// if retried later.
+func (e TransactionError) IsSoftDecline() bool {
+	return e.ErrorCategory == TransactionErrorCategorySoft
+}
+
+// IsHardDecline returns true if the transaction was declined and will not
+// succeed if retried.
+func (e TransactionError) IsHardDecline() bool {
+	return e.ErrorCategory == TransactionErrorCategoryHard
+}
+
+// IsFraud returns true if the transaction was declined by fraud screening.
+func (e TransactionError) IsFraud() bool {
+	return e.ErrorCategory == TransactionErrorCategoryFraud
+}
+
+// IsCommunication returns true if the transaction failed because of a
+// communication error with the payment gateway.
+func (e TransactionError) IsCommunication() bool {
+	return e.ErrorCategory == TransactionErrorCategoryCommunication
+}
+
+// IsConfiguration returns true if the transaction failed because of the
+// payment gateway configuration.
+func (e TransactionError) IsConfiguration() bool {
+	return e.ErrorCategory == TransactionErrorCategoryConfiguration
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, v := range e.Errors {
+		if v.Field != "" {
+			msgs = append(msgs, fmt.Sprintf("%s %s", v.Field, v.Message))
+		} else {
+			msgs = append(msgs, v.Message)
+		}
+	}
+
+	return "recurly: validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *TransactionFailedError) Error() string {
+	return fmt.Sprintf("recurly: transaction failed (%s): %s", e.ErrorCode, e.MerchantMessage)
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Message == "" {
+		return "recurly: not found"
+	}
+	return "recurly: not found: " + e.Message
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("recurly: rate limit exceeded, retry after %s", e.RetryAfter)
+	}
+	return "recurly: rate limit exceeded"
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("recurly: server error (%d %s)", e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
+}
+
+func (e *ClientError) Error() string {
+	msg := fmt.Sprintf("recurly: request failed (%d %s)", e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// newAPIError builds the typed error for an unsuccessful response.
+func newAPIError(r *Response) error {
+	var symbol, message string
+	if len(r.Errors) > 0 {
+		symbol, message = r.Errors[0].Symbol, r.Errors[0].Message
+	}
+
+	switch {
+	case r.StatusCode == 422 && r.TransactionError.ErrorCode != "":
+		return &TransactionFailedError{
+			Response:         r,
+			TransactionError: r.TransactionError,
+			Errors:           r.Errors,
+		}
+	case r.StatusCode == 422:
+		return &ValidationError{Response: r, Errors: r.Errors}
+	case r.StatusCode == http.StatusNotFound:
+		return &NotFoundError{Response: r, Symbol: symbol, Message: message}
+	case r.StatusCode == http.StatusTooManyRequests:
+		d, _ := retryAfter(r.Header.Get("Retry-After"))
+		return &RateLimitedError{Response: r, RetryAfter: d}
+	case r.IsServerError():
+		return &ServerError{Response: r}
+	}
+
+	return &ClientError{Response: r, Symbol: symbol, Message: message}
+}
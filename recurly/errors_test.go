@@ -0,0 +1,215 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypedErrorsDisabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description lang="en-US">Couldn't find Account with account_code = 1</description></error>`)
+	})
+
+	r, _, err := client.Accounts.Get("1")
+	if err != nil {
+		t.Errorf("TestTypedErrorsDisabled Error: Expected nil error, given %v", err)
+	}
+
+	if r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestTypedErrorsDisabled Error: Expected status code %d, given %d", http.StatusNotFound, r.StatusCode)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.TypedErrors = true
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+				<error field="account.account_code" symbol="blank" lang="en-US">can't be blank</error>
+				<error field="account.email" symbol="invalid_email" lang="en-US">is not a valid email address</error>
+			</errors>`)
+	})
+
+	r, _, err := client.Accounts.Create(Account{})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("TestValidationError Error: Expected *ValidationError, given %#v", err)
+	}
+
+	if ve.Response != r {
+		t.Errorf("TestValidationError Error: Expected error to hold the returned response")
+	}
+
+	expected := []Error{
+		Error{XMLName: xml.Name{Local: "error"}, Message: "can't be blank", Field: "account.account_code", Symbol: "blank"},
+		Error{XMLName: xml.Name{Local: "error"}, Message: "is not a valid email address", Field: "account.email", Symbol: "invalid_email"},
+	}
+	if !reflect.DeepEqual(expected, ve.Errors) {
+		t.Errorf("TestValidationError Error: Expected errors %#v, given %#v", expected, ve.Errors)
+	}
+
+	if !reflect.DeepEqual(expected, r.Errors) {
+		t.Errorf("TestValidationError Error: Expected response errors %#v, given %#v", expected, r.Errors)
+	}
+
+	msg := "recurly: validation failed: account.account_code can't be blank; account.email is not a valid email address"
+	if ve.Error() != msg {
+		t.Errorf("TestValidationError Error: Expected message %q, given %q", msg, ve.Error())
+	}
+}
+
+func TestTransactionFailedError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.TypedErrors = true
+	mux.HandleFunc("/v2/transactions", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+				<transaction_error>
+					<error_code>insufficient_funds</error_code>
+					<error_category>soft</error_category>
+					<merchant_message>The card has insufficient funds to cover the cost of the transaction.</merchant_message>
+					<customer_message>The card has insufficient funds to cover the cost of the transaction.</customer_message>
+					<gateway_error_code>51</gateway_error_code>
+				</transaction_error>
+				<error field="transaction.account.base" symbol="insufficient_funds">The card has insufficient funds to cover the cost of the transaction.</error>
+			</errors>`)
+	})
+
+	_, _, err := client.Transactions.Create(NewTransaction{AmountInCents: 100, Currency: "USD"})
+	var te *TransactionFailedError
+	if !errors.As(err, &te) {
+		t.Fatalf("TestTransactionFailedError Error: Expected *TransactionFailedError, given %#v", err)
+	}
+
+	if te.ErrorCode != "insufficient_funds" || te.GatewayErrorCode != "51" {
+		t.Errorf("TestTransactionFailedError Error: Expected insufficient_funds with gateway code 51, given %#v", te.TransactionError)
+	}
+
+	if !te.IsDeclined() || !te.IsSoftDecline() || te.IsHardDecline() || te.IsFraud() || te.IsCommunication() || te.IsConfiguration() {
+		t.Errorf("TestTransactionFailedError Error: Expected only soft decline helpers to return true for category %s", te.ErrorCategory)
+	}
+
+	if len(te.Errors) != 1 || te.Errors[0].Symbol != "insufficient_funds" {
+		t.Errorf("TestTransactionFailedError Error: Expected one insufficient_funds error, given %#v", te.Errors)
+	}
+}
+
+func TestTransactionErrorCategories(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"category": "hard", "declined": true, "fraud": false, "communication": false, "configuration": false},
+		map[string]interface{}{"category": "fraud", "declined": false, "fraud": true, "communication": false, "configuration": false},
+		map[string]interface{}{"category": "communication", "declined": false, "fraud": false, "communication": true, "configuration": false},
+		map[string]interface{}{"category": "configuration", "declined": false, "fraud": false, "communication": false, "configuration": true},
+	}
+
+	for _, s := range suite {
+		e := TransactionError{ErrorCategory: s["category"].(string)}
+		given := map[string]bool{
+			"declined":      e.IsDeclined(),
+			"fraud":         e.IsFraud(),
+			"communication": e.IsCommunication(),
+			"configuration": e.IsConfiguration(),
+		}
+
+		for name, v := range given {
+			if v != s[name].(bool) {
+				t.Errorf("TestTransactionErrorCategories Error (%s): Expected %s helper to return %t, given %t", s["category"], name, s[name], v)
+			}
+		}
+	}
+}
+
+func TestStatusCodeErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.TypedErrors = true
+	mux.HandleFunc("/v2/accounts/missing", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description lang="en-US">Couldn't find Account with account_code = missing</description></error>`)
+	})
+
+	mux.HandleFunc("/v2/accounts/limited", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Retry-After", "30")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	mux.HandleFunc("/v2/accounts/broken", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	mux.HandleFunc("/v2/accounts/forbidden", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>forbidden</symbol><description lang="en-US">The API key is not authorized for this resource</description></error>`)
+	})
+
+	_, _, err := client.Accounts.Get("missing")
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Fatalf("TestStatusCodeErrors Error: Expected *NotFoundError, given %#v", err)
+	}
+
+	if nf.Symbol != "not_found" || nf.Message != "Couldn't find Account with account_code = missing" {
+		t.Errorf("TestStatusCodeErrors Error: Expected not_found symbol and message, given %#v", nf)
+	}
+
+	_, _, err = client.Accounts.Get("limited")
+	var rl *RateLimitedError
+	if !errors.As(err, &rl) {
+		t.Fatalf("TestStatusCodeErrors Error: Expected *RateLimitedError, given %#v", err)
+	}
+
+	if rl.RetryAfter != 30*time.Second {
+		t.Errorf("TestStatusCodeErrors Error: Expected RetryAfter of %s, given %s", 30*time.Second, rl.RetryAfter)
+	}
+
+	_, _, err = client.Accounts.Get("broken")
+	var se *ServerError
+	if !errors.As(err, &se) {
+		t.Fatalf("TestStatusCodeErrors Error: Expected *ServerError, given %#v", err)
+	}
+
+	if se.Response.StatusCode != http.StatusBadGateway {
+		t.Errorf("TestStatusCodeErrors Error: Expected status code %d, given %d", http.StatusBadGateway, se.Response.StatusCode)
+	}
+
+	_, _, err = client.Accounts.Get("forbidden")
+	var ce *ClientError
+	if !errors.As(err, &ce) {
+		t.Fatalf("TestStatusCodeErrors Error: Expected *ClientError, given %#v", err)
+	}
+
+	if ce.Symbol != "forbidden" {
+		t.Errorf("TestStatusCodeErrors Error: Expected symbol %s, given %s", "forbidden", ce.Symbol)
+	}
+}
+
+func TestTypedErrorsSuccess(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.TypedErrors = true
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	if _, _, err := client.Accounts.Get("1"); err != nil {
+		t.Errorf("TestTypedErrorsSuccess Error: Expected nil error, given %v", err)
+	}
+}
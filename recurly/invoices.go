@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -9,7 +10,35 @@ import (
 type (
 	// InvoicesService handles communication with theinvoices related methods
 	// of the recurly API.
-	InvoicesService struct {
+	InvoicesService interface {
+		List(params Params) (*Response, []Invoice, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Invoice, error)
+		ListPager(params Params) *Pager[Invoice]
+		ListAccount(accountCode string, params Params) (*Response, []Invoice, error)
+		ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Invoice, error)
+		ListAccountPager(accountCode string, params Params) *Pager[Invoice]
+		Get(invoiceNumber int) (*Response, Invoice, error)
+		GetContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error)
+		GetPDF(invoiceNumber int, language string) (*Response, *bytes.Buffer, error)
+		GetPDFContext(ctx context.Context, invoiceNumber int, language string) (*Response, *bytes.Buffer, error)
+		Preview(accountCode string) (*Response, Invoice, error)
+		PreviewContext(ctx context.Context, accountCode string) (*Response, Invoice, error)
+		Create(accountCode string, invoice Invoice) (*Response, Invoice, error)
+		CreateContext(ctx context.Context, accountCode string, invoice Invoice) (*Response, Invoice, error)
+		MarkAsPaid(invoiceNumber int) (*Response, Invoice, error)
+		MarkAsPaidContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error)
+		MarkAsFailed(invoiceNumber int) (*Response, Invoice, error)
+		MarkAsFailedContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error)
+		RefundAmount(invoiceNumber int, amountInCents int, refundMethod string) (*Response, Invoice, error)
+		RefundAmountContext(ctx context.Context, invoiceNumber int, amountInCents int, refundMethod string) (*Response, Invoice, error)
+		RefundLineItems(invoiceNumber int, items []RefundLineItem, refundMethod string) (*Response, Invoice, error)
+		RefundLineItemsContext(ctx context.Context, invoiceNumber int, items []RefundLineItem, refundMethod string) (*Response, Invoice, error)
+		Void(invoiceNumber int) (*Response, Invoice, error)
+		VoidContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error)
+	}
+
+	// invoicesImpl implements InvoicesService.
+	invoicesImpl struct {
 		client *Client
 	}
 
@@ -41,6 +70,29 @@ type (
 		LineItems    []Adjustment  `xml:"line_items>adjustment,omitempty"`
 		Transactions []Transaction `xml:"transactions>transaction,omitempty"`
 	}
+
+	// RefundLineItem selects a line item of an invoice to refund.
+	RefundLineItem struct {
+		UUID     string `xml:"uuid"`
+		Quantity int    `xml:"quantity"`
+
+		// Prorate refunds only the unused portion of the service period of
+		// subscription charges.
+		Prorate NullBool `xml:"prorate,omitempty"`
+	}
+
+	// invoiceRefund is the request body for refunding an invoice. LineItems
+	// is a pointer so it is omitted when refunding by amount.
+	invoiceRefund struct {
+		XMLName       xml.Name         `xml:"invoice"`
+		AmountInCents int              `xml:"amount_in_cents,omitempty"`
+		LineItems     *refundLineItems `xml:"line_items,omitempty"`
+		RefundMethod  string           `xml:"refund_method,omitempty"`
+	}
+
+	refundLineItems struct {
+		Adjustments []RefundLineItem `xml:"adjustment"`
+	}
 )
 
 const (
@@ -58,12 +110,37 @@ const (
 	// InvoiceStatePastDue is an invoice state for invoices where initial collection
 	// failed, but Recurly is still attempting collection.
 	InvoiceStatePastDue = "past_due"
+
+	// InvoiceStateVoid is an invoice state for invoices that have been voided.
+	InvoiceStateVoid = "void"
+)
+
+const (
+	// RefundMethodCreditFirst refunds payments made with account credit
+	// before refunding transactions. This is Recurly's default.
+	RefundMethodCreditFirst = "credit_first"
+
+	// RefundMethodTransactionFirst refunds transactions before payments made
+	// with account credit.
+	RefundMethodTransactionFirst = "transaction_first"
+
+	// RefundMethodAllCredit refunds the full amount to the account as credit.
+	RefundMethodAllCredit = "all_credit"
+
+	// RefundMethodAllTransaction refunds the full amount through transactions.
+	RefundMethodAllTransaction = "all_transaction"
 )
 
 // List returns a list of all invoices.
 // https://dev.recurly.com/docs/list-invoices
-func (service InvoicesService) List(params Params) (*Response, []Invoice, error) {
-	req, err := service.client.newRequest("GET", "invoices", params, nil)
+func (service invoicesImpl) List(params Params) (*Response, []Invoice, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service invoicesImpl) ListContext(ctx context.Context, params Params) (*Response, []Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.List", "invoices")
+	req, err := service.client.newRequest(ctx, "GET", "invoices", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -77,11 +154,24 @@ func (service InvoicesService) List(params Params) (*Response, []Invoice, error)
 	return res, p.Invoices, err
 }
 
+// ListPager returns a Pager that walks every page of invoices.
+func (service invoicesImpl) ListPager(params Params) *Pager[Invoice] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Invoice, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // ListAccount returns a list of all invoices for an account.
 // https://dev.recurly.com/docs/list-an-accounts-invoices
-func (service InvoicesService) ListAccount(accountCode string, params Params) (*Response, []Invoice, error) {
+func (service invoicesImpl) ListAccount(accountCode string, params Params) (*Response, []Invoice, error) {
+	return service.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext is the same as ListAccount, but uses ctx for the request.
+func (service invoicesImpl) ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.ListAccount", "accounts/{account_code}/invoices")
 	action := fmt.Sprintf("accounts/%s/invoices", accountCode)
-	req, err := service.client.newRequest("GET", action, params, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -95,12 +185,25 @@ func (service InvoicesService) ListAccount(accountCode string, params Params) (*
 	return res, p.Invoices, err
 }
 
+// ListAccountPager returns a Pager that walks every page of invoices for an account.
+func (service invoicesImpl) ListAccountPager(accountCode string, params Params) *Pager[Invoice] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Invoice, error) {
+		return service.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
 // Get returns detailed information about an invoice including line items and
 // payments.
 // https://dev.recurly.com/docs/lookup-invoice-details
-func (service InvoicesService) Get(invoiceNumber int) (*Response, Invoice, error) {
+func (service invoicesImpl) Get(invoiceNumber int) (*Response, Invoice, error) {
+	return service.GetContext(context.Background(), invoiceNumber)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service invoicesImpl) GetContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.Get", "invoices/{invoice_number}")
 	action := fmt.Sprintf("invoices/%d", invoiceNumber)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Invoice{}, err
 	}
@@ -116,9 +219,15 @@ func (service InvoicesService) Get(invoiceNumber int) (*Response, Invoice, error
 // invoice into. If empty, English will be used. Options: Danish, German,
 // Spanish, French, Hindi, Japanese, Dutch, Portuguese, Russian, Turkish, Chinese.
 // https://dev.recurly.com/docs/retrieve-a-pdf-invoice
-func (service InvoicesService) GetPDF(invoiceNumber int, language string) (*Response, *bytes.Buffer, error) {
+func (service invoicesImpl) GetPDF(invoiceNumber int, language string) (*Response, *bytes.Buffer, error) {
+	return service.GetPDFContext(context.Background(), invoiceNumber, language)
+}
+
+// GetPDFContext is the same as GetPDF, but uses ctx for the request.
+func (service invoicesImpl) GetPDFContext(ctx context.Context, invoiceNumber int, language string) (*Response, *bytes.Buffer, error) {
+	ctx = withOperation(ctx, "Invoices.GetPDF", "invoices/{invoice_number}")
 	action := fmt.Sprintf("invoices/%d", invoiceNumber)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,9 +248,15 @@ func (service InvoicesService) GetPDF(invoiceNumber int, language string) (*Resp
 // Preview allows you to display the invoice details, including estimated tax,
 // before you post it.
 // https://dev.recurly.com/docs/post-an-invoice-invoice-pending-charges-on-an-acco
-func (service InvoicesService) Preview(accountCode string) (*Response, Invoice, error) {
+func (service invoicesImpl) Preview(accountCode string) (*Response, Invoice, error) {
+	return service.PreviewContext(context.Background(), accountCode)
+}
+
+// PreviewContext is the same as Preview, but uses ctx for the request.
+func (service invoicesImpl) PreviewContext(ctx context.Context, accountCode string) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.Preview", "accounts/{account_code}/invoices/preview")
 	action := fmt.Sprintf("accounts/%s/invoices/preview", accountCode)
-	req, err := service.client.newRequest("POST", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, nil)
 	if err != nil {
 		return nil, Invoice{}, err
 	}
@@ -159,9 +274,15 @@ func (service InvoicesService) Preview(accountCode string) (*Response, Invoice,
 // invoice an account before the renewal. If the subscriber has a yearly
 // subscription, you might want to collect the one-time charges well before the renewal.
 // https://dev.recurly.com/docs/post-an-invoice-invoice-pending-charges-on-an-acco
-func (service InvoicesService) Create(accountCode string, invoice Invoice) (*Response, Invoice, error) {
+func (service invoicesImpl) Create(accountCode string, invoice Invoice) (*Response, Invoice, error) {
+	return service.CreateContext(context.Background(), accountCode, invoice)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service invoicesImpl) CreateContext(ctx context.Context, accountCode string, invoice Invoice) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.Create", "accounts/{account_code}/invoices")
 	action := fmt.Sprintf("accounts/%s/invoices", accountCode)
-	req, err := service.client.newRequest("POST", action, nil, invoice)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, invoice)
 	if err != nil {
 		return nil, Invoice{}, err
 	}
@@ -174,9 +295,15 @@ func (service InvoicesService) Create(accountCode string, invoice Invoice) (*Res
 
 // MarkAsPaid marks an invoice as paid successfully.
 // https://dev.recurly.com/docs/mark-an-invoice-as-paid-successfully
-func (service InvoicesService) MarkAsPaid(invoiceNumber int) (*Response, Invoice, error) {
+func (service invoicesImpl) MarkAsPaid(invoiceNumber int) (*Response, Invoice, error) {
+	return service.MarkAsPaidContext(context.Background(), invoiceNumber)
+}
+
+// MarkAsPaidContext is the same as MarkAsPaid, but uses ctx for the request.
+func (service invoicesImpl) MarkAsPaidContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.MarkAsPaid", "invoices/{invoice_number}/mark_successful")
 	action := fmt.Sprintf("invoices/%d/mark_successful", invoiceNumber)
-	req, err := service.client.newRequest("PUT", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
 	if err != nil {
 		return nil, Invoice{}, err
 	}
@@ -189,9 +316,96 @@ func (service InvoicesService) MarkAsPaid(invoiceNumber int) (*Response, Invoice
 
 // MarkAsFailed marks an invoice as failed.
 // https://dev.recurly.com/docs/mark-an-invoice-as-failed-collection
-func (service InvoicesService) MarkAsFailed(invoiceNumber int) (*Response, Invoice, error) {
+func (service invoicesImpl) MarkAsFailed(invoiceNumber int) (*Response, Invoice, error) {
+	return service.MarkAsFailedContext(context.Background(), invoiceNumber)
+}
+
+// MarkAsFailedContext is the same as MarkAsFailed, but uses ctx for the request.
+func (service invoicesImpl) MarkAsFailedContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.MarkAsFailed", "invoices/{invoice_number}/mark_failed")
 	action := fmt.Sprintf("invoices/%d/mark_failed", invoiceNumber)
-	req, err := service.client.newRequest("PUT", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
+	if err != nil {
+		return nil, Invoice{}, err
+	}
+
+	var dest Invoice
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// RefundAmount refunds amountInCents of a collected invoice. refundMethod is
+// one of the RefundMethod constants; if empty, Recurly's default of
+// RefundMethodCreditFirst is used. The refund invoice is returned. An amount
+// less than 1 returns a *ValidationError without making a request.
+// https://dev.recurly.com/docs/line-item-refunds
+func (service invoicesImpl) RefundAmount(invoiceNumber int, amountInCents int, refundMethod string) (*Response, Invoice, error) {
+	return service.RefundAmountContext(context.Background(), invoiceNumber, amountInCents, refundMethod)
+}
+
+// RefundAmountContext is the same as RefundAmount, but uses ctx for the request.
+func (service invoicesImpl) RefundAmountContext(ctx context.Context, invoiceNumber int, amountInCents int, refundMethod string) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.RefundAmount", "invoices/{invoice_number}/refund")
+
+	// Without an amount the body is empty, which Recurly takes as a refund of
+	// the whole invoice.
+	if amountInCents < 1 {
+		var v validation
+		v.add("invoice.amount_in_cents", "greater_than", "must be greater than 0")
+		return nil, Invoice{}, v.err()
+	}
+
+	return service.refund(ctx, invoiceNumber, invoiceRefund{
+		AmountInCents: amountInCents,
+		RefundMethod:  refundMethod,
+	})
+}
+
+// RefundLineItems refunds the given line items of a collected invoice. Use
+// Adjustment.RefundLineItem to select items from Invoice.LineItems.
+// refundMethod is one of the RefundMethod constants; if empty, Recurly's
+// default of RefundMethodCreditFirst is used. The refund invoice is returned.
+// https://dev.recurly.com/docs/line-item-refunds
+func (service invoicesImpl) RefundLineItems(invoiceNumber int, items []RefundLineItem, refundMethod string) (*Response, Invoice, error) {
+	return service.RefundLineItemsContext(context.Background(), invoiceNumber, items, refundMethod)
+}
+
+// RefundLineItemsContext is the same as RefundLineItems, but uses ctx for the request.
+func (service invoicesImpl) RefundLineItemsContext(ctx context.Context, invoiceNumber int, items []RefundLineItem, refundMethod string) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.RefundLineItems", "invoices/{invoice_number}/refund")
+	return service.refund(ctx, invoiceNumber, invoiceRefund{
+		LineItems:    &refundLineItems{Adjustments: items},
+		RefundMethod: refundMethod,
+	})
+}
+
+// refund sends the request shared by both kinds of invoice refunds.
+func (service invoicesImpl) refund(ctx context.Context, invoiceNumber int, body invoiceRefund) (*Response, Invoice, error) {
+	action := fmt.Sprintf("invoices/%d/refund", invoiceNumber)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, body)
+	if err != nil {
+		return nil, Invoice{}, err
+	}
+
+	var dest Invoice
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Void voids an open invoice. Its line items are not charged and the
+// invoice can no longer be collected.
+// https://dev.recurly.com/docs/void-invoice
+func (service invoicesImpl) Void(invoiceNumber int) (*Response, Invoice, error) {
+	return service.VoidContext(context.Background(), invoiceNumber)
+}
+
+// VoidContext is the same as Void, but uses ctx for the request.
+func (service invoicesImpl) VoidContext(ctx context.Context, invoiceNumber int) (*Response, Invoice, error) {
+	ctx = withOperation(ctx, "Invoices.Void", "invoices/{invoice_number}/void")
+	action := fmt.Sprintf("invoices/%d/void", invoiceNumber)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
 	if err != nil {
 		return nil, Invoice{}, err
 	}
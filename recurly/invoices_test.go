@@ -578,8 +578,8 @@ func TestGetInvoice(t *testing.T) {
 						CardType:  "Visa",
 						Year:      2017,
 						Month:     11,
-						FirstSix:  411111,
-						LastFour:  1111,
+						FirstSix:  "411111",
+						LastFour:  "1111",
 					},
 				},
 			},
@@ -748,3 +748,125 @@ func TestMarkInvoiceAsFailed(t *testing.T) {
 		t.Fatal("TestMarkInvoiceAsFailed Error: Expected create invoice to return OK")
 	}
 }
+
+func TestRefundInvoiceAmount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/refund", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestRefundInvoiceAmount Error: Expected %s request, given %s", "POST", r.Method)
+		}
+
+		expected := "<invoice><amount_in_cents>500</amount_in_cents><refund_method>transaction_first</refund_method></invoice>"
+		given := new(bytes.Buffer)
+		given.ReadFrom(r.Body)
+		if expected != given.String() {
+			t.Errorf("TestRefundInvoiceAmount Error: Expected request body of %s, given %s", expected, given.String())
+		}
+
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoice href="https://your-subdomain.recurly.com/v2/invoices/1403">
+				<original_invoice href="https://your-subdomain.recurly.com/v2/invoices/1402"/>
+				<state>collected</state>
+				<invoice_number type="integer">1403</invoice_number>
+				<total_in_cents type="integer">-500</total_in_cents>
+				<currency>USD</currency>
+			</invoice>`)
+	})
+
+	r, inv, err := client.Invoices.RefundAmount(1402, 500, RefundMethodTransactionFirst)
+	if err != nil {
+		t.Errorf("TestRefundInvoiceAmount Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestRefundInvoiceAmount Error: Expected refund invoice to return OK")
+	}
+
+	if inv.InvoiceNumber != 1403 || inv.TotalInCents != -500 || inv.OriginalInvoice.Code != "1402" {
+		t.Errorf("TestRefundInvoiceAmount Error: Expected refund invoice 1403 for -500 cents, given %#v", inv)
+	}
+}
+
+func TestRefundInvoiceAmountInvalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/refund", func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("TestRefundInvoiceAmountInvalid Error: Expected no request for an amount less than 1")
+	})
+
+	for _, amount := range []int{0, -500} {
+		r, _, err := client.Invoices.RefundAmount(1402, amount, "")
+		if r != nil {
+			t.Errorf("TestRefundInvoiceAmountInvalid Error: Expected nil response for %d, given %+v", amount, r)
+		}
+
+		ve, ok := err.(*ValidationError)
+		if !ok || len(ve.Errors) != 1 || ve.Errors[0].Field != "invoice.amount_in_cents" {
+			t.Errorf("TestRefundInvoiceAmountInvalid Error: Expected amount validation error for %d, given %v", amount, err)
+		}
+	}
+}
+
+func TestRefundInvoiceLineItems(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/refund", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestRefundInvoiceLineItems Error: Expected %s request, given %s", "POST", r.Method)
+		}
+
+		expected := "<invoice><line_items><adjustment><uuid>2cc95aa62517e56d5bec3a48afa1b3b9</uuid><quantity>2</quantity><prorate>false</prorate></adjustment><adjustment><uuid>5bec3a48afa1b3b92cc95aa62517e56d</uuid><quantity>1</quantity><prorate>true</prorate></adjustment></line_items></invoice>"
+		given := new(bytes.Buffer)
+		given.ReadFrom(r.Body)
+		if expected != given.String() {
+			t.Errorf("TestRefundInvoiceLineItems Error: Expected request body of %s, given %s", expected, given.String())
+		}
+
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><invoice><invoice_number type="integer">1403</invoice_number></invoice>`)
+	})
+
+	items := []Adjustment{
+		Adjustment{UUID: "2cc95aa62517e56d5bec3a48afa1b3b9", Quantity: 2},
+		Adjustment{UUID: "5bec3a48afa1b3b92cc95aa62517e56d", Quantity: 3},
+	}
+
+	r, inv, err := client.Invoices.RefundLineItems(1402, []RefundLineItem{
+		items[0].RefundLineItem(0, false),
+		items[1].RefundLineItem(1, true),
+	}, "")
+	if err != nil {
+		t.Errorf("TestRefundInvoiceLineItems Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() || inv.InvoiceNumber != 1403 {
+		t.Fatalf("TestRefundInvoiceLineItems Error: Expected refund invoice 1403, given %d %#v", r.StatusCode, inv)
+	}
+}
+
+func TestVoidInvoice(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/void", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestVoidInvoice Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><invoice><state>void</state></invoice>`)
+	})
+
+	r, inv, err := client.Invoices.Void(1402)
+	if err != nil {
+		t.Errorf("TestVoidInvoice Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() || inv.State != InvoiceStateVoid {
+		t.Fatalf("TestVoidInvoice Error: Expected voided invoice, given %d %#v", r.StatusCode, inv)
+	}
+}
@@ -0,0 +1,198 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+type (
+	// LogOptions configures the middleware returned by LoggingMiddleware.
+	LogOptions struct {
+		// Level is the level successful calls and API errors are logged
+		// at. Calls that fail without a response, such as network errors,
+		// are always logged at slog.LevelError. Defaults to slog.LevelInfo.
+		Level slog.Level
+
+		// Bodies logs the request and response bodies, with sensitive
+		// elements masked according to Redaction.
+		Bodies bool
+
+		// Redaction is the policy used to mask bodies. Defaults to
+		// DefaultRedactionPolicy if nil.
+		Redaction *RedactionPolicy
+	}
+
+	// RedactionPolicy describes which XML elements have their contents
+	// masked before a body is logged.
+	RedactionPolicy struct {
+		// Elements holds the local names of the elements to mask, such as
+		// "number" or "email". Everything inside a masked element, including
+		// child elements, is replaced with Mask.
+		Elements []string
+
+		// Mask replaces the contents of masked elements. Defaults to
+		// "[REDACTED]" if empty.
+		Mask string
+	}
+)
+
+// DefaultRedactionPolicy returns a policy that masks card and bank account
+// numbers, verification values, recurly.js tokens, email addresses, street
+// addresses and phone numbers.
+func DefaultRedactionPolicy() *RedactionPolicy {
+	return &RedactionPolicy{
+		Elements: []string{
+			"number",
+			"verification_value",
+			"account_number",
+			"routing_number",
+			"sort_code",
+			"bsb_code",
+			"iban",
+			"token_id",
+			"email",
+			"cc_emails",
+			"address1",
+			"address2",
+			"phone",
+		},
+	}
+}
+
+// LoggingMiddleware returns middleware that logs every API call to logger
+// with its operation, method, path, status, latency and Recurly's
+// X-Request-Id. When opts.Bodies is set, the request and response bodies are
+// logged with sensitive elements masked. If opts is nil, bodies are not
+// logged.
+func LoggingMiddleware(logger *slog.Logger, opts *LogOptions) Middleware {
+	if opts == nil {
+		opts = &LogOptions{}
+	}
+	policy := opts.Redaction
+	if policy == nil {
+		policy = DefaultRedactionPolicy()
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			ctx := req.Context()
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			}
+			if op, ok := RequestOperation(req); ok {
+				attrs = append(attrs, slog.String("operation", op.Name))
+			}
+			if opts.Bodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					b, _ := ioutil.ReadAll(body)
+					body.Close()
+					attrs = append(attrs, slog.String("request_body", policy.Redact(b)))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+			if resp == nil || resp.Response == nil {
+				attrs = append(attrs, slog.Any("error", err))
+				logger.LogAttrs(ctx, slog.LevelError, "recurly: request failed", attrs...)
+				return resp, err
+			}
+
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.String("request_id", resp.Header.Get("X-Request-Id")),
+			)
+			if opts.Bodies && resp.Body != nil {
+				// Buffer the body and put it back so it can still be decoded.
+				b, rerr := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+				if rerr == nil {
+					attrs = append(attrs, slog.String("response_body", policy.Redact(b)))
+				}
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+
+			logger.LogAttrs(ctx, opts.Level, "recurly: request", attrs...)
+			return resp, err
+		}
+	}
+}
+
+// Redact returns body with the contents of the policy's elements masked.
+// Bodies that are not valid XML are masked entirely, so nothing sensitive
+// is logged by mistake.
+func (p *RedactionPolicy) Redact(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	mask := p.Mask
+	if mask == "" {
+		mask = defaultRedactionMask
+	}
+	elements := make(map[string]bool, len(p.Elements))
+	for _, name := range p.Elements {
+		elements[name] = true
+	}
+
+	var buf bytes.Buffer
+	d := xml.NewDecoder(bytes.NewReader(body))
+	e := xml.NewEncoder(&buf)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return mask
+		}
+
+		switch t := tok.(type) {
+		case xml.ProcInst:
+			// The encoder only accepts the XML declaration at the start.
+			if buf.Len() > 0 {
+				continue
+			}
+		case xml.StartElement:
+			// Drop the namespace so the encoder doesn't add xmlns
+			// attributes that weren't in the body.
+			t.Name.Space = ""
+			tok = t
+			if elements[t.Name.Local] {
+				if err := d.Skip(); err != nil {
+					return mask
+				}
+				t.Attr = nil
+				e.EncodeToken(t)
+				e.EncodeToken(xml.CharData(mask))
+				e.EncodeToken(t.End())
+				continue
+			}
+		case xml.EndElement:
+			t.Name.Space = ""
+			tok = t
+		}
+
+		if err := e.EncodeToken(tok); err != nil {
+			return mask
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return mask
+	}
+
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,178 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/billing_info", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Request-Id", "req-123")
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><billing_info type="credit_card"><first_name>Verena</first_name><address1>123 Main St.</address1><phone>555-1212</phone><first_six>411111</first_six><last_four>1111</last_four><month>11</month><year>2030</year></billing_info>`)
+	})
+	mux.HandleFunc("/v2/accounts/2/billing_info", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><billing_info type="bank_account"><account_type>checking</account_type></billing_info>`)
+	})
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>3</account_code><email>verena@example.com</email></account>`)
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	client.Middleware = []Middleware{LoggingMiddleware(logger, &LogOptions{Bodies: true})}
+
+	_, b, err := client.Billing.Create("1", Billing{
+		FirstName:         "Verena",
+		Address:           "123 Main St.",
+		Number:            "4111111111111111",
+		Month:             11,
+		Year:              2030,
+		VerificationValue: "987",
+	})
+	if err != nil {
+		t.Fatalf("TestLoggingMiddleware Error: Error occurred making API call. Err: %s", err)
+	} else if b.FirstSix != "411111" || b.LastFour != "1111" {
+		t.Errorf("TestLoggingMiddleware Error: Expected response to decode after logging, given %#v", b)
+	}
+
+	if _, _, err := client.Billing.Create("2", Billing{
+		NameOnAccount: "Acme, Inc",
+		RoutingNumber: "065400137",
+		AccountNumber: "4003020100",
+		AccountType:   "checking",
+	}); err != nil {
+		t.Fatalf("TestLoggingMiddleware Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if _, _, err := client.Billing.CreateWithToken("1", "tok-SECRET"); err != nil {
+		t.Fatalf("TestLoggingMiddleware Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if _, _, err := client.Accounts.Create(Account{Code: "3", Email: "verena@example.com"}); err != nil {
+		t.Fatalf("TestLoggingMiddleware Error: Error occurred making API call. Err: %s", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"4111111111111111", "987", "065400137", "4003020100", "tok-SECRET", "verena@example.com", "123 Main St.", "555-1212"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("TestLoggingMiddleware Error: Expected %q to be redacted, given %s", secret, out)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("TestLoggingMiddleware Error: Expected 4 log lines, given %d", len(lines))
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("TestLoggingMiddleware Error: Error decoding log entry. Err: %s", err)
+	}
+
+	for k, v := range map[string]interface{}{
+		"level":      "INFO",
+		"method":     "POST",
+		"path":       "/v2/accounts/1/billing_info",
+		"status":     float64(201),
+		"request_id": "req-123",
+	} {
+		if entry[k] != v {
+			t.Errorf("TestLoggingMiddleware Error: Expected %s to be %v, given %v", k, v, entry[k])
+		}
+	}
+
+	if _, ok := entry["latency"]; !ok {
+		t.Error("TestLoggingMiddleware Error: Expected latency to be logged")
+	}
+
+	if body, _ := entry["request_body"].(string); !strings.Contains(body, "<number>[REDACTED]</number>") || !strings.Contains(body, "<verification_value>[REDACTED]</verification_value>") || !strings.Contains(body, "<first_name>Verena</first_name>") {
+		t.Errorf("TestLoggingMiddleware Error: Expected masked request body, given %s", body)
+	}
+
+	if body, _ := entry["response_body"].(string); !strings.Contains(body, "<last_four>1111</last_four>") || !strings.Contains(body, "<address1>[REDACTED]</address1>") {
+		t.Errorf("TestLoggingMiddleware Error: Expected masked response body, given %s", body)
+	}
+}
+
+func TestLoggingMiddlewareNoBodies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(404)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description>Couldn't find Account with account_code = 1</description></error>`)
+	})
+
+	var buf bytes.Buffer
+	client.TypedErrors = true
+	client.Middleware = []Middleware{LoggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil)), &LogOptions{Level: slog.LevelWarn})}
+
+	resp, _, err := client.Accounts.Get("1")
+	if err == nil || len(resp.Errors) != 1 || resp.Errors[0].Symbol != "not_found" {
+		t.Fatalf("TestLoggingMiddlewareNoBodies Error: Expected not found error, given %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("TestLoggingMiddlewareNoBodies Error: Error decoding log entry. Err: %s", err)
+	}
+
+	if entry["level"] != "WARN" || entry["status"] != float64(404) || entry["error"] == nil {
+		t.Errorf("TestLoggingMiddlewareNoBodies Error: Unexpected log entry: %v", entry)
+	}
+
+	if _, ok := entry["response_body"]; ok {
+		t.Errorf("TestLoggingMiddlewareNoBodies Error: Expected bodies not to be logged, given %v", entry)
+	}
+}
+
+func TestLoggingMiddlewareErrorBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="account.email" symbol="invalid_email">is not a valid email</error></errors>`)
+	})
+
+	var buf bytes.Buffer
+	client.Middleware = []Middleware{LoggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil)), &LogOptions{Bodies: true})}
+
+	resp, _, _ := client.Accounts.Create(Account{Code: "1", Email: "not-an-email"})
+	if len(resp.Errors) != 1 || resp.Errors[0].Symbol != "invalid_email" {
+		t.Fatalf("TestLoggingMiddlewareErrorBody Error: Expected errors to be parsed, given %#v", resp.Errors)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "invalid_email") || strings.Contains(out, "not-an-email") {
+		t.Errorf("TestLoggingMiddlewareErrorBody Error: Expected error body with masked email, given %s", out)
+	}
+}
+
+func TestRedactionPolicy(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"policy": DefaultRedactionPolicy(), "body": ``, "expected": ``},
+		map[string]interface{}{"policy": DefaultRedactionPolicy(), "body": `<?xml version="1.0" encoding="UTF-8"?><billing_info><number>4111111111111111</number><month>1</month></billing_info>`, "expected": `<?xml version="1.0" encoding="UTF-8"?><billing_info><number>[REDACTED]</number><month>1</month></billing_info>`},
+		map[string]interface{}{"policy": DefaultRedactionPolicy(), "body": `<account><email nil="nil"></email><address><address1>1 Main</address1><city>SF</city></address></account>`, "expected": `<account><email>[REDACTED]</email><address><address1>[REDACTED]</address1><city>SF</city></address></account>`},
+		map[string]interface{}{"policy": &RedactionPolicy{Elements: []string{"address"}, Mask: "***"}, "body": `<account><address><address1>1 Main</address1></address><email>a@b.c</email></account>`, "expected": `<account><address>***</address><email>a@b.c</email></account>`},
+		map[string]interface{}{"policy": DefaultRedactionPolicy(), "body": `not xml <number>4111111111111111`, "expected": `[REDACTED]`},
+	}
+
+	for i, s := range suite {
+		policy := s["policy"].(*RedactionPolicy)
+		given := policy.Redact([]byte(s["body"].(string)))
+		if given != s["expected"].(string) {
+			t.Errorf("TestRedactionPolicy Error (%d): Expected %s, given %s", i, s["expected"], given)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package recurly
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+type (
+	// MeasuredUnitsService handles communication with the measured units
+	// related methods of the recurly API.
+	MeasuredUnitsService interface {
+		List(params Params) (*Response, []MeasuredUnit, error)
+		ListContext(ctx context.Context, params Params) (*Response, []MeasuredUnit, error)
+		ListPager(params Params) *Pager[MeasuredUnit]
+		Get(id int) (*Response, MeasuredUnit, error)
+		GetContext(ctx context.Context, id int) (*Response, MeasuredUnit, error)
+		Create(u MeasuredUnit) (*Response, MeasuredUnit, error)
+		CreateContext(ctx context.Context, u MeasuredUnit) (*Response, MeasuredUnit, error)
+		Update(id int, u MeasuredUnit) (*Response, MeasuredUnit, error)
+		UpdateContext(ctx context.Context, id int, u MeasuredUnit) (*Response, MeasuredUnit, error)
+		Delete(id int) (*Response, error)
+		DeleteContext(ctx context.Context, id int) (*Response, error)
+	}
+
+	// measuredUnitsImpl implements MeasuredUnitsService.
+	measuredUnitsImpl struct {
+		client *Client
+	}
+
+	// MeasuredUnit is the unit the usage of usage add ons is measured in,
+	// such as API calls or gigabytes.
+	MeasuredUnit struct {
+		XMLName     xml.Name `xml:"measured_unit"`
+		ID          int      `xml:"id,omitempty"`
+		Name        string   `xml:"name,omitempty"`
+		DisplayName string   `xml:"display_name,omitempty"`
+		Description string   `xml:"description,omitempty"`
+		CreatedAt   NullTime `xml:"created_at,omitempty"`
+		UpdatedAt   NullTime `xml:"updated_at,omitempty"`
+	}
+
+	measuredUnitMarshaler struct {
+		XMLName     xml.Name `xml:"measured_unit"`
+		Name        string   `xml:"name,omitempty"`
+		DisplayName string   `xml:"display_name,omitempty"`
+		Description string   `xml:"description,omitempty"`
+	}
+)
+
+// MarshalXML marshals only the fields needed for creating/updating measured
+// units with the recurly API.
+func (u MeasuredUnit) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	um := measuredUnitMarshaler{
+		Name:        u.Name,
+		DisplayName: u.DisplayName,
+		Description: u.Description,
+	}
+
+	return e.Encode(um)
+}
+
+// List returns the measured units on your site.
+// https://dev.recurly.com/docs/list-measured-units
+func (service measuredUnitsImpl) List(params Params) (*Response, []MeasuredUnit, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service measuredUnitsImpl) ListContext(ctx context.Context, params Params) (*Response, []MeasuredUnit, error) {
+	ctx = withOperation(ctx, "MeasuredUnits.List", "measured_units")
+	req, err := service.client.newRequest(ctx, "GET", "measured_units", params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var u struct {
+		XMLName       xml.Name       `xml:"measured_units"`
+		MeasuredUnits []MeasuredUnit `xml:"measured_unit"`
+	}
+	res, err := service.client.do(req, &u)
+
+	return res, u.MeasuredUnits, err
+}
+
+// ListPager returns a Pager that walks every page of measured units.
+func (service measuredUnitsImpl) ListPager(params Params) *Pager[MeasuredUnit] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []MeasuredUnit, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
+// Get returns a single measured unit.
+// https://dev.recurly.com/docs/lookup-a-measured-unit
+func (service measuredUnitsImpl) Get(id int) (*Response, MeasuredUnit, error) {
+	return service.GetContext(context.Background(), id)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service measuredUnitsImpl) GetContext(ctx context.Context, id int) (*Response, MeasuredUnit, error) {
+	ctx = withOperation(ctx, "MeasuredUnits.Get", "measured_units/{measured_unit_id}")
+	action := fmt.Sprintf("measured_units/%d", id)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
+	if err != nil {
+		return nil, MeasuredUnit{}, err
+	}
+
+	var dest MeasuredUnit
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Create adds a measured unit for usage add ons to use.
+// https://dev.recurly.com/docs/create-a-measured-unit
+func (service measuredUnitsImpl) Create(u MeasuredUnit) (*Response, MeasuredUnit, error) {
+	return service.CreateContext(context.Background(), u)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service measuredUnitsImpl) CreateContext(ctx context.Context, u MeasuredUnit) (*Response, MeasuredUnit, error) {
+	ctx = withOperation(ctx, "MeasuredUnits.Create", "measured_units")
+	req, err := service.client.newRequest(ctx, "POST", "measured_units", nil, u)
+	if err != nil {
+		return nil, MeasuredUnit{}, err
+	}
+
+	var dest MeasuredUnit
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Update changes a measured unit. Only the fields that are set are updated.
+// https://dev.recurly.com/docs/update-a-measured-unit
+func (service measuredUnitsImpl) Update(id int, u MeasuredUnit) (*Response, MeasuredUnit, error) {
+	return service.UpdateContext(context.Background(), id, u)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service measuredUnitsImpl) UpdateContext(ctx context.Context, id int, u MeasuredUnit) (*Response, MeasuredUnit, error) {
+	ctx = withOperation(ctx, "MeasuredUnits.Update", "measured_units/{measured_unit_id}")
+	action := fmt.Sprintf("measured_units/%d", id)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, u)
+	if err != nil {
+		return nil, MeasuredUnit{}, err
+	}
+
+	var dest MeasuredUnit
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Delete removes a measured unit. Units used by add ons can't be deleted.
+// https://dev.recurly.com/docs/delete-a-measured-unit
+func (service measuredUnitsImpl) Delete(id int) (*Response, error) {
+	return service.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service measuredUnitsImpl) DeleteContext(ctx context.Context, id int) (*Response, error) {
+	ctx = withOperation(ctx, "MeasuredUnits.Delete", "measured_units/{measured_unit_id}")
+	action := fmt.Sprintf("measured_units/%d", id)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.do(req, nil)
+}
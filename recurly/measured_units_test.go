@@ -0,0 +1,178 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMeasuredUnitsEncoding ensures structs are encoded to XML properly.
+// Read only fields should never be sent to Recurly.
+func TestMeasuredUnitsEncoding(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"struct": MeasuredUnit{}, "xml": "<measured_unit></measured_unit>"},
+		map[string]interface{}{"struct": MeasuredUnit{ID: 1, Name: "calls", CreatedAt: NewTime(time.Now())}, "xml": "<measured_unit><name>calls</name></measured_unit>"},
+		map[string]interface{}{"struct": MeasuredUnit{Name: "calls", DisplayName: "API Calls", Description: "Calls to the API"}, "xml": "<measured_unit><name>calls</name><display_name>API Calls</display_name><description>Calls to the API</description></measured_unit>"},
+	}
+
+	for _, s := range suite {
+		buf := new(bytes.Buffer)
+		err := xml.NewEncoder(buf).Encode(s["struct"])
+		if err != nil {
+			t.Errorf("TestMeasuredUnitsEncoding Error: %s", err)
+		}
+
+		if buf.String() != s["xml"] {
+			t.Errorf("TestMeasuredUnitsEncoding Error: Expected %s, given %s", s["xml"], buf.String())
+		}
+	}
+}
+
+func TestMeasuredUnitsList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestMeasuredUnitsList Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<measured_units type="array">
+				<measured_unit href="https://your-subdomain.recurly.com/v2/measured_units/3473591245469944008">
+					<id type="integer">3473591245469944008</id>
+					<name>calls</name>
+					<display_name>API Calls</display_name>
+					<description>Calls to the API</description>
+					<created_at type="datetime">2017-01-26T21:15:38Z</created_at>
+					<updated_at type="datetime">2017-01-26T21:15:38Z</updated_at>
+				</measured_unit>
+			</measured_units>`)
+	})
+
+	r, units, err := client.MeasuredUnits.List(nil)
+	if err != nil {
+		t.Errorf("TestMeasuredUnitsList Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestMeasuredUnitsList Error: Expected list measured units to return OK")
+	}
+
+	ts, _ := time.Parse(datetimeFormat, "2017-01-26T21:15:38Z")
+	expected := []MeasuredUnit{
+		MeasuredUnit{
+			XMLName:     xml.Name{Local: "measured_unit"},
+			ID:          3473591245469944008,
+			Name:        "calls",
+			DisplayName: "API Calls",
+			Description: "Calls to the API",
+			CreatedAt:   NewTime(ts),
+			UpdatedAt:   NewTime(ts),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, units) {
+		t.Errorf("TestMeasuredUnitsList Error: expected measured units to equal %#v, given %#v", expected, units)
+	}
+}
+
+func TestGetMeasuredUnit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestGetMeasuredUnit Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<measured_unit href="https://your-subdomain.recurly.com/v2/measured_units/5">
+				<id type="integer">5</id>
+				<name>calls</name>
+				<display_name>API Calls</display_name>
+			</measured_unit>`)
+	})
+
+	r, unit, err := client.MeasuredUnits.Get(5)
+	if err != nil {
+		t.Errorf("TestGetMeasuredUnit Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestGetMeasuredUnit Error: Expected get measured unit to return OK")
+	}
+
+	if unit.ID != 5 || unit.Name != "calls" || unit.DisplayName != "API Calls" {
+		t.Errorf("TestGetMeasuredUnit Error: Unexpected measured unit, given %#v", unit)
+	}
+}
+
+func TestCreateMeasuredUnit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestCreateMeasuredUnit Error: Expected %s request, given %s", "POST", r.Method)
+		}
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><measured_unit></measured_unit>`)
+	})
+
+	r, _, err := client.MeasuredUnits.Create(MeasuredUnit{Name: "calls", DisplayName: "API Calls"})
+	if err != nil {
+		t.Errorf("TestCreateMeasuredUnit Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestCreateMeasuredUnit Error: Expected create measured unit to return OK")
+	}
+}
+
+func TestUpdateMeasuredUnit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestUpdateMeasuredUnit Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><measured_unit></measured_unit>`)
+	})
+
+	r, _, err := client.MeasuredUnits.Update(5, MeasuredUnit{DisplayName: "Calls"})
+	if err != nil {
+		t.Errorf("TestUpdateMeasuredUnit Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestUpdateMeasuredUnit Error: Expected update measured unit to return OK")
+	}
+}
+
+func TestDeleteMeasuredUnit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestDeleteMeasuredUnit Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+		rw.WriteHeader(204)
+	})
+
+	r, err := client.MeasuredUnits.Delete(5)
+	if err != nil {
+		t.Errorf("TestDeleteMeasuredUnit Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestDeleteMeasuredUnit Error: Expected delete measured unit to return OK")
+	}
+}
@@ -0,0 +1,67 @@
+package recurly
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// RoundTripFunc makes an API call for a prepared request. It returns the
+	// Response with any validation or transaction errors already parsed into
+	// Errors and TransactionError. When the client has TypedErrors enabled,
+	// unsuccessful calls also return the typed error.
+	//
+	// The body of a successful response is left unread: it is decoded by the
+	// client after the middleware chain returns, so middleware must not
+	// consume it unless it replaces it.
+	RoundTripFunc func(req *http.Request) (*Response, error)
+
+	// Middleware wraps a RoundTripFunc to run code around every API call,
+	// such as logging, metrics, tracing, adding headers or injecting faults.
+	// Middleware can change the request before calling next, inspect or
+	// replace the Response and error it returns, or return without calling
+	// next at all. A Response returned without an error must have a non-nil
+	// http.Response.
+	//
+	// Middleware runs once per API call. When the client has a RetryPolicy,
+	// next only returns after the final attempt.
+	Middleware func(next RoundTripFunc) RoundTripFunc
+
+	// Operation identifies the service method that made an API call, for
+	// naming logs, traces and metrics.
+	Operation struct {
+		// Name is the service and method, e.g. "Subscriptions.Create".
+		Name string
+
+		// Route is the endpoint's path relative to /v2/ with identifiers
+		// replaced by placeholders, e.g. "subscriptions/{uuid}/cancel".
+		Route string
+	}
+
+	// operationKey is the context key for the Operation of a request.
+	operationKey struct{}
+)
+
+// RequestOperation returns the Operation that made req. It returns false
+// for requests that weren't made by a service method.
+func RequestOperation(req *http.Request) (Operation, bool) {
+	op, ok := req.Context().Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+// withOperation returns a copy of ctx that carries the Operation with the
+// given name and route.
+func withOperation(ctx context.Context, name string, route string) context.Context {
+	return context.WithValue(ctx, operationKey{}, Operation{Name: name, Route: route})
+}
+
+// HeaderMiddleware returns middleware that sets the given header on every
+// request.
+func HeaderMiddleware(key string, value string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			req.Header.Set(key, value)
+			return next(req)
+		}
+	}
+}
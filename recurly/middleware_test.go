@@ -0,0 +1,175 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprintf(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code><company_name>%s</company_name></account>`, r.Header.Get("X-Trace"))
+	})
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				req.Header.Set("X-Trace", req.Header.Get("X-Trace")+name)
+				resp, err := next(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+	client.Middleware = []Middleware{trace("a"), trace("b")}
+
+	_, a, err := client.Accounts.Get("1")
+	if err != nil {
+		t.Fatalf("TestMiddlewareOrder Error: Error occurred making API call. Err: %s", err)
+	}
+
+	expected := []string{"a before", "b before", "b after", "a after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("TestMiddlewareOrder Error: Expected calls %v, given %v", expected, calls)
+	}
+
+	if a.CompanyName != "ab" {
+		t.Errorf("TestMiddlewareOrder Error: Expected request header ab, given %s", a.CompanyName)
+	}
+}
+
+func TestMiddlewareHeader(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		if given := r.Header.Get("X-Api-Version"); given != "2.29" {
+			t.Errorf("TestMiddlewareHeader Error: Expected header 2.29, given %s", given)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	client.Middleware = []Middleware{HeaderMiddleware("X-Api-Version", "2.29")}
+	if _, _, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("TestMiddlewareHeader Error: Error occurred making API call. Err: %s", err)
+	}
+}
+
+func TestMiddlewareErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="account.account_code" symbol="taken">has already been taken</error></errors>`)
+	})
+
+	var errs []Error
+	var typed error
+	client.TypedErrors = true
+	client.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			resp, err := next(req)
+			errs, typed = resp.Errors, err
+			return resp, err
+		}
+	}}
+
+	_, _, err := client.Accounts.Create(Account{Code: "1"})
+	if len(errs) != 1 || errs[0].Symbol != "taken" {
+		t.Errorf("TestMiddlewareErrors Error: Expected middleware to see parsed errors, given %#v", errs)
+	}
+
+	var ve *ValidationError
+	if !errors.As(typed, &ve) || typed != err {
+		t.Errorf("TestMiddlewareErrors Error: Expected middleware to see the typed error, given %v", typed)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var called bool
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	injected := errors.New("injected")
+	client.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			return nil, injected
+		}
+	}}
+
+	if resp, _, err := client.Accounts.Get("1"); err != injected || resp != nil {
+		t.Errorf("TestMiddlewareShortCircuit Error: Expected injected error, given %v", err)
+	} else if called {
+		t.Error("TestMiddlewareShortCircuit Error: Expected request not to be sent")
+	}
+
+	// Middleware can also answer with a response of its own.
+	client.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			return &Response{Response: &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`<account><account_code>stub</account_code></account>`)),
+			}}, nil
+		}
+	}}
+
+	if _, a, err := client.Accounts.Get("1"); err != nil || a.Code != "stub" {
+		t.Errorf("TestMiddlewareShortCircuit Error: Expected stubbed account, given %#v (%v)", a, err)
+	} else if called {
+		t.Error("TestMiddlewareShortCircuit Error: Expected request not to be sent")
+	}
+}
+
+func TestRequestOperation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><transaction><uuid>a13acd8fe4294916b79aec87b7ea441f</uuid></transaction>`)
+	})
+
+	var given []Operation
+	client.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			op, ok := RequestOperation(req)
+			if !ok {
+				t.Errorf("TestRequestOperation Error: Expected operation for %s", req.URL.Path)
+			}
+			given = append(given, op)
+			return next(req)
+		}
+	}}
+
+	client.Transactions.Get("a13acd8fe4294916b79aec87b7ea441f")
+	client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", 250)
+
+	expected := []Operation{
+		{Name: "Transactions.Get", Route: "transactions/{uuid}"},
+		{Name: "Transactions.Refund", Route: "transactions/{uuid}"},
+	}
+	if !reflect.DeepEqual(given, expected) {
+		t.Errorf("TestRequestOperation Error: Expected %v, given %v", expected, given)
+	}
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	if _, ok := RequestOperation(req); ok {
+		t.Error("TestRequestOperation Error: Expected no operation for a plain request")
+	}
+}
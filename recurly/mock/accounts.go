@@ -0,0 +1,226 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.AccountsService = &AccountsService{}
+
+// AccountsService is a mock recurly.AccountsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type AccountsService struct {
+	Recorder
+
+	OnList              func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Account, error)
+	OnGet               func(ctx context.Context, code string) (*recurly.Response, recurly.Account, error)
+	OnCreate            func(ctx context.Context, a recurly.Account) (*recurly.Response, recurly.Account, error)
+	OnUpdate            func(ctx context.Context, code string, a recurly.Account) (*recurly.Response, recurly.Account, error)
+	OnClose             func(ctx context.Context, code string) (*recurly.Response, error)
+	OnReopen            func(ctx context.Context, code string) (*recurly.Response, error)
+	OnListNotes         func(ctx context.Context, code string) (*recurly.Response, []recurly.Note, error)
+	OnListChildAccounts func(ctx context.Context, code string, params recurly.Params) (*recurly.Response, []recurly.Account, error)
+	OnGetBalance        func(ctx context.Context, code string) (*recurly.Response, recurly.AccountBalance, error)
+	OnGetAcquisition    func(ctx context.Context, code string) (*recurly.Response, recurly.AccountAcquisition, error)
+	OnCreateAcquisition func(ctx context.Context, code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error)
+	OnUpdateAcquisition func(ctx context.Context, code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error)
+	OnDeleteAcquisition func(ctx context.Context, code string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *AccountsService) List(params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *AccountsService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("AccountsService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *AccountsService) ListPager(params recurly.Params) *recurly.Pager[recurly.Account] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *AccountsService) Get(code string) (*recurly.Response, recurly.Account, error) {
+	return m.GetContext(context.Background(), code)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *AccountsService) GetContext(ctx context.Context, code string) (*recurly.Response, recurly.Account, error) {
+	m.record("Get", code)
+	if m.OnGet == nil {
+		return nil, recurly.Account{}, notSet("AccountsService", "Get")
+	}
+	return m.OnGet(ctx, code)
+}
+
+// Create calls CreateContext with a background context.
+func (m *AccountsService) Create(a recurly.Account) (*recurly.Response, recurly.Account, error) {
+	return m.CreateContext(context.Background(), a)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *AccountsService) CreateContext(ctx context.Context, a recurly.Account) (*recurly.Response, recurly.Account, error) {
+	m.record("Create", a)
+	if m.OnCreate == nil {
+		return nil, recurly.Account{}, notSet("AccountsService", "Create")
+	}
+	return m.OnCreate(ctx, a)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *AccountsService) Update(code string, a recurly.Account) (*recurly.Response, recurly.Account, error) {
+	return m.UpdateContext(context.Background(), code, a)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *AccountsService) UpdateContext(ctx context.Context, code string, a recurly.Account) (*recurly.Response, recurly.Account, error) {
+	m.record("Update", code, a)
+	if m.OnUpdate == nil {
+		return nil, recurly.Account{}, notSet("AccountsService", "Update")
+	}
+	return m.OnUpdate(ctx, code, a)
+}
+
+// Close calls CloseContext with a background context.
+func (m *AccountsService) Close(code string) (*recurly.Response, error) {
+	return m.CloseContext(context.Background(), code)
+}
+
+// CloseContext records the call and returns the result of OnClose.
+func (m *AccountsService) CloseContext(ctx context.Context, code string) (*recurly.Response, error) {
+	m.record("Close", code)
+	if m.OnClose == nil {
+		return nil, notSet("AccountsService", "Close")
+	}
+	return m.OnClose(ctx, code)
+}
+
+// Reopen calls ReopenContext with a background context.
+func (m *AccountsService) Reopen(code string) (*recurly.Response, error) {
+	return m.ReopenContext(context.Background(), code)
+}
+
+// ReopenContext records the call and returns the result of OnReopen.
+func (m *AccountsService) ReopenContext(ctx context.Context, code string) (*recurly.Response, error) {
+	m.record("Reopen", code)
+	if m.OnReopen == nil {
+		return nil, notSet("AccountsService", "Reopen")
+	}
+	return m.OnReopen(ctx, code)
+}
+
+// ListNotes calls ListNotesContext with a background context.
+func (m *AccountsService) ListNotes(code string) (*recurly.Response, []recurly.Note, error) {
+	return m.ListNotesContext(context.Background(), code)
+}
+
+// ListNotesContext records the call and returns the result of OnListNotes.
+func (m *AccountsService) ListNotesContext(ctx context.Context, code string) (*recurly.Response, []recurly.Note, error) {
+	m.record("ListNotes", code)
+	if m.OnListNotes == nil {
+		return nil, nil, notSet("AccountsService", "ListNotes")
+	}
+	return m.OnListNotes(ctx, code)
+}
+
+// ListChildAccounts calls ListChildAccountsContext with a background context.
+func (m *AccountsService) ListChildAccounts(code string, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	return m.ListChildAccountsContext(context.Background(), code, params)
+}
+
+// ListChildAccountsContext records the call and returns the result of OnListChildAccounts.
+func (m *AccountsService) ListChildAccountsContext(ctx context.Context, code string, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	m.record("ListChildAccounts", code, params)
+	if m.OnListChildAccounts == nil {
+		return nil, nil, notSet("AccountsService", "ListChildAccounts")
+	}
+	return m.OnListChildAccounts(ctx, code, params)
+}
+
+// ListChildAccountsPager returns a Pager that fetches each page with ListChildAccountsContext.
+func (m *AccountsService) ListChildAccountsPager(code string, params recurly.Params) *recurly.Pager[recurly.Account] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+		return m.ListChildAccountsContext(ctx, code, params)
+	})
+}
+
+// GetBalance calls GetBalanceContext with a background context.
+func (m *AccountsService) GetBalance(code string) (*recurly.Response, recurly.AccountBalance, error) {
+	return m.GetBalanceContext(context.Background(), code)
+}
+
+// GetBalanceContext records the call and returns the result of OnGetBalance.
+func (m *AccountsService) GetBalanceContext(ctx context.Context, code string) (*recurly.Response, recurly.AccountBalance, error) {
+	m.record("GetBalance", code)
+	if m.OnGetBalance == nil {
+		return nil, recurly.AccountBalance{}, notSet("AccountsService", "GetBalance")
+	}
+	return m.OnGetBalance(ctx, code)
+}
+
+// GetAcquisition calls GetAcquisitionContext with a background context.
+func (m *AccountsService) GetAcquisition(code string) (*recurly.Response, recurly.AccountAcquisition, error) {
+	return m.GetAcquisitionContext(context.Background(), code)
+}
+
+// GetAcquisitionContext records the call and returns the result of OnGetAcquisition.
+func (m *AccountsService) GetAcquisitionContext(ctx context.Context, code string) (*recurly.Response, recurly.AccountAcquisition, error) {
+	m.record("GetAcquisition", code)
+	if m.OnGetAcquisition == nil {
+		return nil, recurly.AccountAcquisition{}, notSet("AccountsService", "GetAcquisition")
+	}
+	return m.OnGetAcquisition(ctx, code)
+}
+
+// CreateAcquisition calls CreateAcquisitionContext with a background context.
+func (m *AccountsService) CreateAcquisition(code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error) {
+	return m.CreateAcquisitionContext(context.Background(), code, a)
+}
+
+// CreateAcquisitionContext records the call and returns the result of OnCreateAcquisition.
+func (m *AccountsService) CreateAcquisitionContext(ctx context.Context, code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error) {
+	m.record("CreateAcquisition", code, a)
+	if m.OnCreateAcquisition == nil {
+		return nil, recurly.AccountAcquisition{}, notSet("AccountsService", "CreateAcquisition")
+	}
+	return m.OnCreateAcquisition(ctx, code, a)
+}
+
+// UpdateAcquisition calls UpdateAcquisitionContext with a background context.
+func (m *AccountsService) UpdateAcquisition(code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error) {
+	return m.UpdateAcquisitionContext(context.Background(), code, a)
+}
+
+// UpdateAcquisitionContext records the call and returns the result of OnUpdateAcquisition.
+func (m *AccountsService) UpdateAcquisitionContext(ctx context.Context, code string, a recurly.AccountAcquisition) (*recurly.Response, recurly.AccountAcquisition, error) {
+	m.record("UpdateAcquisition", code, a)
+	if m.OnUpdateAcquisition == nil {
+		return nil, recurly.AccountAcquisition{}, notSet("AccountsService", "UpdateAcquisition")
+	}
+	return m.OnUpdateAcquisition(ctx, code, a)
+}
+
+// DeleteAcquisition calls DeleteAcquisitionContext with a background context.
+func (m *AccountsService) DeleteAcquisition(code string) (*recurly.Response, error) {
+	return m.DeleteAcquisitionContext(context.Background(), code)
+}
+
+// DeleteAcquisitionContext records the call and returns the result of OnDeleteAcquisition.
+func (m *AccountsService) DeleteAcquisitionContext(ctx context.Context, code string) (*recurly.Response, error) {
+	m.record("DeleteAcquisition", code)
+	if m.OnDeleteAcquisition == nil {
+		return nil, notSet("AccountsService", "DeleteAcquisition")
+	}
+	return m.OnDeleteAcquisition(ctx, code)
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.AddOnsService = &AddOnsService{}
+
+// AddOnsService is a mock recurly.AddOnsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type AddOnsService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, planCode string, params recurly.Params) (*recurly.Response, []recurly.AddOn, error)
+	OnGet    func(ctx context.Context, planCode string, code string) (*recurly.Response, recurly.AddOn, error)
+	OnCreate func(ctx context.Context, planCode string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error)
+	OnUpdate func(ctx context.Context, planCode string, code string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error)
+	OnDelete func(ctx context.Context, planCode string, code string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *AddOnsService) List(planCode string, params recurly.Params) (*recurly.Response, []recurly.AddOn, error) {
+	return m.ListContext(context.Background(), planCode, params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *AddOnsService) ListContext(ctx context.Context, planCode string, params recurly.Params) (*recurly.Response, []recurly.AddOn, error) {
+	m.record("List", planCode, params)
+	if m.OnList == nil {
+		return nil, nil, notSet("AddOnsService", "List")
+	}
+	return m.OnList(ctx, planCode, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *AddOnsService) ListPager(planCode string, params recurly.Params) *recurly.Pager[recurly.AddOn] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.AddOn, error) {
+		return m.ListContext(ctx, planCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *AddOnsService) Get(planCode string, code string) (*recurly.Response, recurly.AddOn, error) {
+	return m.GetContext(context.Background(), planCode, code)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *AddOnsService) GetContext(ctx context.Context, planCode string, code string) (*recurly.Response, recurly.AddOn, error) {
+	m.record("Get", planCode, code)
+	if m.OnGet == nil {
+		return nil, recurly.AddOn{}, notSet("AddOnsService", "Get")
+	}
+	return m.OnGet(ctx, planCode, code)
+}
+
+// Create calls CreateContext with a background context.
+func (m *AddOnsService) Create(planCode string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error) {
+	return m.CreateContext(context.Background(), planCode, a)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *AddOnsService) CreateContext(ctx context.Context, planCode string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error) {
+	m.record("Create", planCode, a)
+	if m.OnCreate == nil {
+		return nil, recurly.AddOn{}, notSet("AddOnsService", "Create")
+	}
+	return m.OnCreate(ctx, planCode, a)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *AddOnsService) Update(planCode string, code string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error) {
+	return m.UpdateContext(context.Background(), planCode, code, a)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *AddOnsService) UpdateContext(ctx context.Context, planCode string, code string, a recurly.AddOn) (*recurly.Response, recurly.AddOn, error) {
+	m.record("Update", planCode, code, a)
+	if m.OnUpdate == nil {
+		return nil, recurly.AddOn{}, notSet("AddOnsService", "Update")
+	}
+	return m.OnUpdate(ctx, planCode, code, a)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *AddOnsService) Delete(planCode string, code string) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), planCode, code)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *AddOnsService) DeleteContext(ctx context.Context, planCode string, code string) (*recurly.Response, error) {
+	m.record("Delete", planCode, code)
+	if m.OnDelete == nil {
+		return nil, notSet("AddOnsService", "Delete")
+	}
+	return m.OnDelete(ctx, planCode, code)
+}
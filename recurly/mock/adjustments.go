@@ -0,0 +1,84 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.AdjustmentsService = &AdjustmentsService{}
+
+// AdjustmentsService is a mock recurly.AdjustmentsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type AdjustmentsService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Adjustment, error)
+	OnGet    func(ctx context.Context, uuid string) (*recurly.Response, recurly.Adjustment, error)
+	OnCreate func(ctx context.Context, accountCode string, a recurly.Adjustment) (*recurly.Response, recurly.Adjustment, error)
+	OnDelete func(ctx context.Context, uuid string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *AdjustmentsService) List(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Adjustment, error) {
+	return m.ListContext(context.Background(), accountCode, params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *AdjustmentsService) ListContext(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Adjustment, error) {
+	m.record("List", accountCode, params)
+	if m.OnList == nil {
+		return nil, nil, notSet("AdjustmentsService", "List")
+	}
+	return m.OnList(ctx, accountCode, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *AdjustmentsService) ListPager(accountCode string, params recurly.Params) *recurly.Pager[recurly.Adjustment] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Adjustment, error) {
+		return m.ListContext(ctx, accountCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *AdjustmentsService) Get(uuid string) (*recurly.Response, recurly.Adjustment, error) {
+	return m.GetContext(context.Background(), uuid)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *AdjustmentsService) GetContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Adjustment, error) {
+	m.record("Get", uuid)
+	if m.OnGet == nil {
+		return nil, recurly.Adjustment{}, notSet("AdjustmentsService", "Get")
+	}
+	return m.OnGet(ctx, uuid)
+}
+
+// Create calls CreateContext with a background context.
+func (m *AdjustmentsService) Create(accountCode string, a recurly.Adjustment) (*recurly.Response, recurly.Adjustment, error) {
+	return m.CreateContext(context.Background(), accountCode, a)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *AdjustmentsService) CreateContext(ctx context.Context, accountCode string, a recurly.Adjustment) (*recurly.Response, recurly.Adjustment, error) {
+	m.record("Create", accountCode, a)
+	if m.OnCreate == nil {
+		return nil, recurly.Adjustment{}, notSet("AdjustmentsService", "Create")
+	}
+	return m.OnCreate(ctx, accountCode, a)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *AdjustmentsService) Delete(uuid string) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), uuid)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *AdjustmentsService) DeleteContext(ctx context.Context, uuid string) (*recurly.Response, error) {
+	m.record("Delete", uuid)
+	if m.OnDelete == nil {
+		return nil, notSet("AdjustmentsService", "Delete")
+	}
+	return m.OnDelete(ctx, uuid)
+}
@@ -0,0 +1,107 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.BillingService = &BillingService{}
+
+// BillingService is a mock recurly.BillingService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type BillingService struct {
+	Recorder
+
+	OnGet             func(ctx context.Context, accountCode string) (*recurly.Response, recurly.Billing, error)
+	OnCreate          func(ctx context.Context, accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error)
+	OnCreateWithToken func(ctx context.Context, accountCode string, token string) (*recurly.Response, recurly.Billing, error)
+	OnUpdate          func(ctx context.Context, accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error)
+	OnUpdateWithToken func(ctx context.Context, accountCode string, token string) (*recurly.Response, recurly.Billing, error)
+	OnClear           func(ctx context.Context, accountCode string) (*recurly.Response, error)
+}
+
+// Get calls GetContext with a background context.
+func (m *BillingService) Get(accountCode string) (*recurly.Response, recurly.Billing, error) {
+	return m.GetContext(context.Background(), accountCode)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *BillingService) GetContext(ctx context.Context, accountCode string) (*recurly.Response, recurly.Billing, error) {
+	m.record("Get", accountCode)
+	if m.OnGet == nil {
+		return nil, recurly.Billing{}, notSet("BillingService", "Get")
+	}
+	return m.OnGet(ctx, accountCode)
+}
+
+// Create calls CreateContext with a background context.
+func (m *BillingService) Create(accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error) {
+	return m.CreateContext(context.Background(), accountCode, b)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *BillingService) CreateContext(ctx context.Context, accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error) {
+	m.record("Create", accountCode, b)
+	if m.OnCreate == nil {
+		return nil, recurly.Billing{}, notSet("BillingService", "Create")
+	}
+	return m.OnCreate(ctx, accountCode, b)
+}
+
+// CreateWithToken calls CreateWithTokenContext with a background context.
+func (m *BillingService) CreateWithToken(accountCode string, token string) (*recurly.Response, recurly.Billing, error) {
+	return m.CreateWithTokenContext(context.Background(), accountCode, token)
+}
+
+// CreateWithTokenContext records the call and returns the result of OnCreateWithToken.
+func (m *BillingService) CreateWithTokenContext(ctx context.Context, accountCode string, token string) (*recurly.Response, recurly.Billing, error) {
+	m.record("CreateWithToken", accountCode, token)
+	if m.OnCreateWithToken == nil {
+		return nil, recurly.Billing{}, notSet("BillingService", "CreateWithToken")
+	}
+	return m.OnCreateWithToken(ctx, accountCode, token)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *BillingService) Update(accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error) {
+	return m.UpdateContext(context.Background(), accountCode, b)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *BillingService) UpdateContext(ctx context.Context, accountCode string, b recurly.Billing) (*recurly.Response, recurly.Billing, error) {
+	m.record("Update", accountCode, b)
+	if m.OnUpdate == nil {
+		return nil, recurly.Billing{}, notSet("BillingService", "Update")
+	}
+	return m.OnUpdate(ctx, accountCode, b)
+}
+
+// UpdateWithToken calls UpdateWithTokenContext with a background context.
+func (m *BillingService) UpdateWithToken(accountCode string, token string) (*recurly.Response, recurly.Billing, error) {
+	return m.UpdateWithTokenContext(context.Background(), accountCode, token)
+}
+
+// UpdateWithTokenContext records the call and returns the result of OnUpdateWithToken.
+func (m *BillingService) UpdateWithTokenContext(ctx context.Context, accountCode string, token string) (*recurly.Response, recurly.Billing, error) {
+	m.record("UpdateWithToken", accountCode, token)
+	if m.OnUpdateWithToken == nil {
+		return nil, recurly.Billing{}, notSet("BillingService", "UpdateWithToken")
+	}
+	return m.OnUpdateWithToken(ctx, accountCode, token)
+}
+
+// Clear calls ClearContext with a background context.
+func (m *BillingService) Clear(accountCode string) (*recurly.Response, error) {
+	return m.ClearContext(context.Background(), accountCode)
+}
+
+// ClearContext records the call and returns the result of OnClear.
+func (m *BillingService) ClearContext(ctx context.Context, accountCode string) (*recurly.Response, error) {
+	m.record("Clear", accountCode)
+	if m.OnClear == nil {
+		return nil, notSet("BillingService", "Clear")
+	}
+	return m.OnClear(ctx, accountCode)
+}
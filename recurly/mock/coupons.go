@@ -0,0 +1,84 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.CouponsService = &CouponsService{}
+
+// CouponsService is a mock recurly.CouponsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type CouponsService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Coupon, error)
+	OnGet    func(ctx context.Context, code string) (*recurly.Response, recurly.Coupon, error)
+	OnCreate func(ctx context.Context, c recurly.Coupon) (*recurly.Response, recurly.Coupon, error)
+	OnDelete func(ctx context.Context, code string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *CouponsService) List(params recurly.Params) (*recurly.Response, []recurly.Coupon, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *CouponsService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Coupon, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("CouponsService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *CouponsService) ListPager(params recurly.Params) *recurly.Pager[recurly.Coupon] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Coupon, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *CouponsService) Get(code string) (*recurly.Response, recurly.Coupon, error) {
+	return m.GetContext(context.Background(), code)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *CouponsService) GetContext(ctx context.Context, code string) (*recurly.Response, recurly.Coupon, error) {
+	m.record("Get", code)
+	if m.OnGet == nil {
+		return nil, recurly.Coupon{}, notSet("CouponsService", "Get")
+	}
+	return m.OnGet(ctx, code)
+}
+
+// Create calls CreateContext with a background context.
+func (m *CouponsService) Create(c recurly.Coupon) (*recurly.Response, recurly.Coupon, error) {
+	return m.CreateContext(context.Background(), c)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *CouponsService) CreateContext(ctx context.Context, c recurly.Coupon) (*recurly.Response, recurly.Coupon, error) {
+	m.record("Create", c)
+	if m.OnCreate == nil {
+		return nil, recurly.Coupon{}, notSet("CouponsService", "Create")
+	}
+	return m.OnCreate(ctx, c)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *CouponsService) Delete(code string) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), code)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *CouponsService) DeleteContext(ctx context.Context, code string) (*recurly.Response, error) {
+	m.record("Delete", code)
+	if m.OnDelete == nil {
+		return nil, notSet("CouponsService", "Delete")
+	}
+	return m.OnDelete(ctx, code)
+}
@@ -0,0 +1,197 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.InvoicesService = &InvoicesService{}
+
+// InvoicesService is a mock recurly.InvoicesService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type InvoicesService struct {
+	Recorder
+
+	OnList            func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Invoice, error)
+	OnListAccount     func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error)
+	OnGet             func(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error)
+	OnGetPDF          func(ctx context.Context, invoiceNumber int, language string) (*recurly.Response, *bytes.Buffer, error)
+	OnPreview         func(ctx context.Context, accountCode string) (*recurly.Response, recurly.Invoice, error)
+	OnCreate          func(ctx context.Context, accountCode string, invoice recurly.Invoice) (*recurly.Response, recurly.Invoice, error)
+	OnMarkAsPaid      func(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error)
+	OnMarkAsFailed    func(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error)
+	OnRefundAmount    func(ctx context.Context, invoiceNumber int, amountInCents int, refundMethod string) (*recurly.Response, recurly.Invoice, error)
+	OnRefundLineItems func(ctx context.Context, invoiceNumber int, items []recurly.RefundLineItem, refundMethod string) (*recurly.Response, recurly.Invoice, error)
+	OnVoid            func(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error)
+}
+
+// List calls ListContext with a background context.
+func (m *InvoicesService) List(params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *InvoicesService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("InvoicesService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *InvoicesService) ListPager(params recurly.Params) *recurly.Pager[recurly.Invoice] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// ListAccount calls ListAccountContext with a background context.
+func (m *InvoicesService) ListAccount(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+	return m.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext records the call and returns the result of OnListAccount.
+func (m *InvoicesService) ListAccountContext(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+	m.record("ListAccount", accountCode, params)
+	if m.OnListAccount == nil {
+		return nil, nil, notSet("InvoicesService", "ListAccount")
+	}
+	return m.OnListAccount(ctx, accountCode, params)
+}
+
+// ListAccountPager returns a Pager that fetches each page with ListAccountContext.
+func (m *InvoicesService) ListAccountPager(accountCode string, params recurly.Params) *recurly.Pager[recurly.Invoice] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+		return m.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *InvoicesService) Get(invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	return m.GetContext(context.Background(), invoiceNumber)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *InvoicesService) GetContext(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	m.record("Get", invoiceNumber)
+	if m.OnGet == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "Get")
+	}
+	return m.OnGet(ctx, invoiceNumber)
+}
+
+// GetPDF calls GetPDFContext with a background context.
+func (m *InvoicesService) GetPDF(invoiceNumber int, language string) (*recurly.Response, *bytes.Buffer, error) {
+	return m.GetPDFContext(context.Background(), invoiceNumber, language)
+}
+
+// GetPDFContext records the call and returns the result of OnGetPDF.
+func (m *InvoicesService) GetPDFContext(ctx context.Context, invoiceNumber int, language string) (*recurly.Response, *bytes.Buffer, error) {
+	m.record("GetPDF", invoiceNumber, language)
+	if m.OnGetPDF == nil {
+		return nil, nil, notSet("InvoicesService", "GetPDF")
+	}
+	return m.OnGetPDF(ctx, invoiceNumber, language)
+}
+
+// Preview calls PreviewContext with a background context.
+func (m *InvoicesService) Preview(accountCode string) (*recurly.Response, recurly.Invoice, error) {
+	return m.PreviewContext(context.Background(), accountCode)
+}
+
+// PreviewContext records the call and returns the result of OnPreview.
+func (m *InvoicesService) PreviewContext(ctx context.Context, accountCode string) (*recurly.Response, recurly.Invoice, error) {
+	m.record("Preview", accountCode)
+	if m.OnPreview == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "Preview")
+	}
+	return m.OnPreview(ctx, accountCode)
+}
+
+// Create calls CreateContext with a background context.
+func (m *InvoicesService) Create(accountCode string, invoice recurly.Invoice) (*recurly.Response, recurly.Invoice, error) {
+	return m.CreateContext(context.Background(), accountCode, invoice)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *InvoicesService) CreateContext(ctx context.Context, accountCode string, invoice recurly.Invoice) (*recurly.Response, recurly.Invoice, error) {
+	m.record("Create", accountCode, invoice)
+	if m.OnCreate == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "Create")
+	}
+	return m.OnCreate(ctx, accountCode, invoice)
+}
+
+// MarkAsPaid calls MarkAsPaidContext with a background context.
+func (m *InvoicesService) MarkAsPaid(invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	return m.MarkAsPaidContext(context.Background(), invoiceNumber)
+}
+
+// MarkAsPaidContext records the call and returns the result of OnMarkAsPaid.
+func (m *InvoicesService) MarkAsPaidContext(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	m.record("MarkAsPaid", invoiceNumber)
+	if m.OnMarkAsPaid == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "MarkAsPaid")
+	}
+	return m.OnMarkAsPaid(ctx, invoiceNumber)
+}
+
+// MarkAsFailed calls MarkAsFailedContext with a background context.
+func (m *InvoicesService) MarkAsFailed(invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	return m.MarkAsFailedContext(context.Background(), invoiceNumber)
+}
+
+// MarkAsFailedContext records the call and returns the result of OnMarkAsFailed.
+func (m *InvoicesService) MarkAsFailedContext(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	m.record("MarkAsFailed", invoiceNumber)
+	if m.OnMarkAsFailed == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "MarkAsFailed")
+	}
+	return m.OnMarkAsFailed(ctx, invoiceNumber)
+}
+
+// RefundAmount calls RefundAmountContext with a background context.
+func (m *InvoicesService) RefundAmount(invoiceNumber int, amountInCents int, refundMethod string) (*recurly.Response, recurly.Invoice, error) {
+	return m.RefundAmountContext(context.Background(), invoiceNumber, amountInCents, refundMethod)
+}
+
+// RefundAmountContext records the call and returns the result of OnRefundAmount.
+func (m *InvoicesService) RefundAmountContext(ctx context.Context, invoiceNumber int, amountInCents int, refundMethod string) (*recurly.Response, recurly.Invoice, error) {
+	m.record("RefundAmount", invoiceNumber, amountInCents, refundMethod)
+	if m.OnRefundAmount == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "RefundAmount")
+	}
+	return m.OnRefundAmount(ctx, invoiceNumber, amountInCents, refundMethod)
+}
+
+// RefundLineItems calls RefundLineItemsContext with a background context.
+func (m *InvoicesService) RefundLineItems(invoiceNumber int, items []recurly.RefundLineItem, refundMethod string) (*recurly.Response, recurly.Invoice, error) {
+	return m.RefundLineItemsContext(context.Background(), invoiceNumber, items, refundMethod)
+}
+
+// RefundLineItemsContext records the call and returns the result of OnRefundLineItems.
+func (m *InvoicesService) RefundLineItemsContext(ctx context.Context, invoiceNumber int, items []recurly.RefundLineItem, refundMethod string) (*recurly.Response, recurly.Invoice, error) {
+	m.record("RefundLineItems", invoiceNumber, items, refundMethod)
+	if m.OnRefundLineItems == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "RefundLineItems")
+	}
+	return m.OnRefundLineItems(ctx, invoiceNumber, items, refundMethod)
+}
+
+// Void calls VoidContext with a background context.
+func (m *InvoicesService) Void(invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	return m.VoidContext(context.Background(), invoiceNumber)
+}
+
+// VoidContext records the call and returns the result of OnVoid.
+func (m *InvoicesService) VoidContext(ctx context.Context, invoiceNumber int) (*recurly.Response, recurly.Invoice, error) {
+	m.record("Void", invoiceNumber)
+	if m.OnVoid == nil {
+		return nil, recurly.Invoice{}, notSet("InvoicesService", "Void")
+	}
+	return m.OnVoid(ctx, invoiceNumber)
+}
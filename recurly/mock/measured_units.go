@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.MeasuredUnitsService = &MeasuredUnitsService{}
+
+// MeasuredUnitsService is a mock recurly.MeasuredUnitsService. Each call is
+// recorded and answered by the matching On field, e.g. OnGet for Get and
+// GetContext. Calls whose On field is nil return an error.
+type MeasuredUnitsService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.MeasuredUnit, error)
+	OnGet    func(ctx context.Context, id int) (*recurly.Response, recurly.MeasuredUnit, error)
+	OnCreate func(ctx context.Context, u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error)
+	OnUpdate func(ctx context.Context, id int, u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error)
+	OnDelete func(ctx context.Context, id int) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *MeasuredUnitsService) List(params recurly.Params) (*recurly.Response, []recurly.MeasuredUnit, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *MeasuredUnitsService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.MeasuredUnit, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("MeasuredUnitsService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *MeasuredUnitsService) ListPager(params recurly.Params) *recurly.Pager[recurly.MeasuredUnit] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.MeasuredUnit, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *MeasuredUnitsService) Get(id int) (*recurly.Response, recurly.MeasuredUnit, error) {
+	return m.GetContext(context.Background(), id)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *MeasuredUnitsService) GetContext(ctx context.Context, id int) (*recurly.Response, recurly.MeasuredUnit, error) {
+	m.record("Get", id)
+	if m.OnGet == nil {
+		return nil, recurly.MeasuredUnit{}, notSet("MeasuredUnitsService", "Get")
+	}
+	return m.OnGet(ctx, id)
+}
+
+// Create calls CreateContext with a background context.
+func (m *MeasuredUnitsService) Create(u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error) {
+	return m.CreateContext(context.Background(), u)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *MeasuredUnitsService) CreateContext(ctx context.Context, u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error) {
+	m.record("Create", u)
+	if m.OnCreate == nil {
+		return nil, recurly.MeasuredUnit{}, notSet("MeasuredUnitsService", "Create")
+	}
+	return m.OnCreate(ctx, u)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *MeasuredUnitsService) Update(id int, u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error) {
+	return m.UpdateContext(context.Background(), id, u)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *MeasuredUnitsService) UpdateContext(ctx context.Context, id int, u recurly.MeasuredUnit) (*recurly.Response, recurly.MeasuredUnit, error) {
+	m.record("Update", id, u)
+	if m.OnUpdate == nil {
+		return nil, recurly.MeasuredUnit{}, notSet("MeasuredUnitsService", "Update")
+	}
+	return m.OnUpdate(ctx, id, u)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *MeasuredUnitsService) Delete(id int) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *MeasuredUnitsService) DeleteContext(ctx context.Context, id int) (*recurly.Response, error) {
+	m.record("Delete", id)
+	if m.OnDelete == nil {
+		return nil, notSet("MeasuredUnitsService", "Delete")
+	}
+	return m.OnDelete(ctx, id)
+}
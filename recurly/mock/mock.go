@@ -0,0 +1,213 @@
+// Package mock provides mock implementations of the recurly service
+// interfaces for unit testing code that uses a recurly.Client.
+//
+// Each mock records the calls made to it and answers them with the matching
+// On field:
+//
+//	client, m := mock.NewClient()
+//	m.Accounts.OnGet = func(ctx context.Context, code string) (*recurly.Response, recurly.Account, error) {
+//		return &recurly.Response{Response: &http.Response{StatusCode: 200}}, recurly.Account{Code: code}, nil
+//	}
+//
+//	// ... exercise code that uses client ...
+//
+//	if m.Accounts.Count("Get") != 1 {
+//		t.Error("expected one call to Accounts.Get")
+//	}
+//
+// New returns just the mock Client, which implements recurly.API, for code
+// that depends on that interface rather than a *recurly.Client.
+//
+// Calls are recorded under the method name without the Context suffix, so
+// Get and GetContext are both recorded as "Get". The context argument is
+// not recorded.
+package mock
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+type (
+	// Client holds a mock for each service of a recurly.Client.
+	Client struct {
+		Accounts          *AccountsService
+		Adjustments       *AdjustmentsService
+		Billing           *BillingService
+		Coupons           *CouponsService
+		Redemptions       *RedemptionsService
+		Invoices          *InvoicesService
+		Plans             *PlansService
+		AddOns            *AddOnsService
+		Subscriptions     *SubscriptionsService
+		Transactions      *TransactionsService
+		ShippingAddresses *ShippingAddressesService
+		MeasuredUnits     *MeasuredUnitsService
+		Usage             *UsageService
+	}
+
+	// Call is a single recorded call to a mock.
+	Call struct {
+		Method string
+		Args   []interface{}
+	}
+
+	// Recorder records the calls made to a mock. It is safe for concurrent
+	// use.
+	Recorder struct {
+		mu    sync.Mutex
+		calls []Call
+	}
+)
+
+var _ recurly.API = (*Client)(nil)
+
+// New returns a Client holding a new mock for each service. The Client
+// implements recurly.API, so it can be passed directly to code that depends
+// on the interface rather than on a *recurly.Client.
+func New() *Client {
+	return &Client{
+		Accounts:          &AccountsService{},
+		Adjustments:       &AdjustmentsService{},
+		Billing:           &BillingService{},
+		Coupons:           &CouponsService{},
+		Redemptions:       &RedemptionsService{},
+		Invoices:          &InvoicesService{},
+		Plans:             &PlansService{},
+		AddOns:            &AddOnsService{},
+		Subscriptions:     &SubscriptionsService{},
+		Transactions:      &TransactionsService{},
+		ShippingAddresses: &ShippingAddressesService{},
+		MeasuredUnits:     &MeasuredUnitsService{},
+		Usage:             &UsageService{},
+	}
+}
+
+// NewClient returns a recurly.Client whose services are the mocks held by
+// the returned Client.
+func NewClient() (*recurly.Client, *Client) {
+	m := New()
+	c := recurly.NewClient("mock", "", nil)
+	c.Accounts = m.Accounts
+	c.Adjustments = m.Adjustments
+	c.Billing = m.Billing
+	c.Coupons = m.Coupons
+	c.Redemptions = m.Redemptions
+	c.Invoices = m.Invoices
+	c.Plans = m.Plans
+	c.AddOns = m.AddOns
+	c.Subscriptions = m.Subscriptions
+	c.Transactions = m.Transactions
+	c.ShippingAddresses = m.ShippingAddresses
+	c.MeasuredUnits = m.MeasuredUnits
+	c.Usage = m.Usage
+
+	return c, m
+}
+
+// AccountsService returns m.Accounts.
+func (m *Client) AccountsService() recurly.AccountsService {
+	return m.Accounts
+}
+
+// AdjustmentsService returns m.Adjustments.
+func (m *Client) AdjustmentsService() recurly.AdjustmentsService {
+	return m.Adjustments
+}
+
+// BillingService returns m.Billing.
+func (m *Client) BillingService() recurly.BillingService {
+	return m.Billing
+}
+
+// CouponsService returns m.Coupons.
+func (m *Client) CouponsService() recurly.CouponsService {
+	return m.Coupons
+}
+
+// RedemptionsService returns m.Redemptions.
+func (m *Client) RedemptionsService() recurly.RedemptionsService {
+	return m.Redemptions
+}
+
+// InvoicesService returns m.Invoices.
+func (m *Client) InvoicesService() recurly.InvoicesService {
+	return m.Invoices
+}
+
+// PlansService returns m.Plans.
+func (m *Client) PlansService() recurly.PlansService {
+	return m.Plans
+}
+
+// AddOnsService returns m.AddOns.
+func (m *Client) AddOnsService() recurly.AddOnsService {
+	return m.AddOns
+}
+
+// SubscriptionsService returns m.Subscriptions.
+func (m *Client) SubscriptionsService() recurly.SubscriptionsService {
+	return m.Subscriptions
+}
+
+// TransactionsService returns m.Transactions.
+func (m *Client) TransactionsService() recurly.TransactionsService {
+	return m.Transactions
+}
+
+// ShippingAddressesService returns m.ShippingAddresses.
+func (m *Client) ShippingAddressesService() recurly.ShippingAddressesService {
+	return m.ShippingAddresses
+}
+
+// MeasuredUnitsService returns m.MeasuredUnits.
+func (m *Client) MeasuredUnitsService() recurly.MeasuredUnitsService {
+	return m.MeasuredUnits
+}
+
+// UsageService returns m.Usage.
+func (m *Client) UsageService() recurly.UsageService {
+	return m.Usage
+}
+
+// record appends a call.
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls recorded so far, in the order they were made.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Count returns the number of recorded calls to method.
+func (r *Recorder) Count(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int
+	for _, c := range r.calls {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset clears the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// notSet returns the error returned by calls without a scripted result.
+func notSet(service, method string) error {
+	return fmt.Errorf("mock: %s.%s called but On%s is not set", service, method, method)
+}
@@ -0,0 +1,106 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+func TestNewClient(t *testing.T) {
+	client, m := NewClient()
+
+	m.Accounts.OnGet = func(ctx context.Context, code string) (*recurly.Response, recurly.Account, error) {
+		return &recurly.Response{Response: &http.Response{StatusCode: 200}}, recurly.Account{Code: code, Email: "verena@example.com"}, nil
+	}
+
+	r, a, err := client.Accounts.Get("1")
+	if err != nil || r.StatusCode != 200 {
+		t.Fatalf("TestNewClient Error: Expected scripted response, given %v (%+v)", err, r)
+	}
+
+	if a.Code != "1" || a.Email != "verena@example.com" {
+		t.Errorf("TestNewClient Error: Expected scripted account, given %#v", a)
+	}
+
+	client.Accounts.GetContext(context.Background(), "2")
+
+	expected := []Call{
+		Call{Method: "Get", Args: []interface{}{"1"}},
+		Call{Method: "Get", Args: []interface{}{"2"}},
+	}
+	if given := m.Accounts.Calls(); !reflect.DeepEqual(given, expected) {
+		t.Errorf("TestNewClient Error: Expected calls %#v, given %#v", expected, given)
+	}
+
+	if m.Accounts.Count("Get") != 2 || m.Accounts.Count("Create") != 0 {
+		t.Errorf("TestNewClient Error: Expected 2 calls to Get and none to Create, given %d and %d", m.Accounts.Count("Get"), m.Accounts.Count("Create"))
+	}
+
+	m.Accounts.Reset()
+	if len(m.Accounts.Calls()) != 0 {
+		t.Errorf("TestNewClient Error: Expected no calls after reset, given %#v", m.Accounts.Calls())
+	}
+}
+
+// accountEmail stands in for application code that depends on recurly.API.
+func accountEmail(api recurly.API, code string) string {
+	_, a, _ := api.AccountsService().Get(code)
+	return a.Email
+}
+
+func TestNew(t *testing.T) {
+	m := New()
+	m.Accounts.OnGet = func(ctx context.Context, code string) (*recurly.Response, recurly.Account, error) {
+		return &recurly.Response{Response: &http.Response{StatusCode: 200}}, recurly.Account{Code: code, Email: "verena@example.com"}, nil
+	}
+
+	if email := accountEmail(m, "1"); email != "verena@example.com" {
+		t.Errorf("TestNew Error: Expected scripted account email, given %s", email)
+	}
+
+	if m.AccountsService() != m.Accounts || m.Accounts.Count("Get") != 1 {
+		t.Errorf("TestNew Error: Expected one call to the Accounts mock, given %#v", m.Accounts.Calls())
+	}
+}
+
+func TestNotSet(t *testing.T) {
+	client, m := NewClient()
+
+	_, sub, err := client.Subscriptions.Cancel("abc")
+	if err == nil || err.Error() != "mock: SubscriptionsService.Cancel called but OnCancel is not set" {
+		t.Errorf("TestNotSet Error: Expected unset error, given %v", err)
+	}
+
+	if sub.UUID != "" {
+		t.Errorf("TestNotSet Error: Expected zero subscription, given %#v", sub)
+	}
+
+	if m.Subscriptions.Count("Cancel") != 1 {
+		t.Errorf("TestNotSet Error: Expected call to be recorded, given %#v", m.Subscriptions.Calls())
+	}
+}
+
+func TestPager(t *testing.T) {
+	client, m := NewClient()
+
+	m.Invoices.OnListAccount = func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+		return nil, []recurly.Invoice{{InvoiceNumber: 1}, {InvoiceNumber: 2}}, nil
+	}
+
+	var numbers []int
+	pager := client.Invoices.ListAccountPager("1", nil)
+	for pager.Next(context.Background()) {
+		numbers = append(numbers, pager.Value().InvoiceNumber)
+	}
+
+	if pager.Err() != nil || !reflect.DeepEqual(numbers, []int{1, 2}) {
+		t.Errorf("TestPager Error: Expected invoices 1 and 2, given %v (%v)", numbers, pager.Err())
+	}
+
+	if calls := m.Invoices.Calls(); len(calls) != 1 || calls[0].Method != "ListAccount" || calls[0].Args[0] != "1" {
+		t.Errorf("TestPager Error: Expected one ListAccount call for account 1, given %#v", calls)
+	}
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.PlansService = &PlansService{}
+
+// PlansService is a mock recurly.PlansService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type PlansService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Plan, error)
+	OnGet    func(ctx context.Context, code string) (*recurly.Response, recurly.Plan, error)
+	OnCreate func(ctx context.Context, p recurly.Plan) (*recurly.Response, recurly.Plan, error)
+	OnUpdate func(ctx context.Context, code string, p recurly.Plan) (*recurly.Response, recurly.Plan, error)
+	OnDelete func(ctx context.Context, code string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *PlansService) List(params recurly.Params) (*recurly.Response, []recurly.Plan, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *PlansService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Plan, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("PlansService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *PlansService) ListPager(params recurly.Params) *recurly.Pager[recurly.Plan] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Plan, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *PlansService) Get(code string) (*recurly.Response, recurly.Plan, error) {
+	return m.GetContext(context.Background(), code)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *PlansService) GetContext(ctx context.Context, code string) (*recurly.Response, recurly.Plan, error) {
+	m.record("Get", code)
+	if m.OnGet == nil {
+		return nil, recurly.Plan{}, notSet("PlansService", "Get")
+	}
+	return m.OnGet(ctx, code)
+}
+
+// Create calls CreateContext with a background context.
+func (m *PlansService) Create(p recurly.Plan) (*recurly.Response, recurly.Plan, error) {
+	return m.CreateContext(context.Background(), p)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *PlansService) CreateContext(ctx context.Context, p recurly.Plan) (*recurly.Response, recurly.Plan, error) {
+	m.record("Create", p)
+	if m.OnCreate == nil {
+		return nil, recurly.Plan{}, notSet("PlansService", "Create")
+	}
+	return m.OnCreate(ctx, p)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *PlansService) Update(code string, p recurly.Plan) (*recurly.Response, recurly.Plan, error) {
+	return m.UpdateContext(context.Background(), code, p)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *PlansService) UpdateContext(ctx context.Context, code string, p recurly.Plan) (*recurly.Response, recurly.Plan, error) {
+	m.record("Update", code, p)
+	if m.OnUpdate == nil {
+		return nil, recurly.Plan{}, notSet("PlansService", "Update")
+	}
+	return m.OnUpdate(ctx, code, p)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *PlansService) Delete(code string) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), code)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *PlansService) DeleteContext(ctx context.Context, code string) (*recurly.Response, error) {
+	m.record("Delete", code)
+	if m.OnDelete == nil {
+		return nil, notSet("PlansService", "Delete")
+	}
+	return m.OnDelete(ctx, code)
+}
@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.RedemptionsService = &RedemptionsService{}
+
+// RedemptionsService is a mock recurly.RedemptionsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type RedemptionsService struct {
+	Recorder
+
+	OnGetForAccount func(ctx context.Context, accountCode string) (*recurly.Response, recurly.Redemption, error)
+	OnGetForInvoice func(ctx context.Context, invoiceNumber string) (*recurly.Response, recurly.Redemption, error)
+	OnRedeem        func(ctx context.Context, code string, accountCode string, currency string) (*recurly.Response, recurly.Redemption, error)
+	OnDelete        func(ctx context.Context, accountCode string) (*recurly.Response, error)
+}
+
+// GetForAccount calls GetForAccountContext with a background context.
+func (m *RedemptionsService) GetForAccount(accountCode string) (*recurly.Response, recurly.Redemption, error) {
+	return m.GetForAccountContext(context.Background(), accountCode)
+}
+
+// GetForAccountContext records the call and returns the result of OnGetForAccount.
+func (m *RedemptionsService) GetForAccountContext(ctx context.Context, accountCode string) (*recurly.Response, recurly.Redemption, error) {
+	m.record("GetForAccount", accountCode)
+	if m.OnGetForAccount == nil {
+		return nil, recurly.Redemption{}, notSet("RedemptionsService", "GetForAccount")
+	}
+	return m.OnGetForAccount(ctx, accountCode)
+}
+
+// GetForInvoice calls GetForInvoiceContext with a background context.
+func (m *RedemptionsService) GetForInvoice(invoiceNumber string) (*recurly.Response, recurly.Redemption, error) {
+	return m.GetForInvoiceContext(context.Background(), invoiceNumber)
+}
+
+// GetForInvoiceContext records the call and returns the result of OnGetForInvoice.
+func (m *RedemptionsService) GetForInvoiceContext(ctx context.Context, invoiceNumber string) (*recurly.Response, recurly.Redemption, error) {
+	m.record("GetForInvoice", invoiceNumber)
+	if m.OnGetForInvoice == nil {
+		return nil, recurly.Redemption{}, notSet("RedemptionsService", "GetForInvoice")
+	}
+	return m.OnGetForInvoice(ctx, invoiceNumber)
+}
+
+// Redeem calls RedeemContext with a background context.
+func (m *RedemptionsService) Redeem(code string, accountCode string, currency string) (*recurly.Response, recurly.Redemption, error) {
+	return m.RedeemContext(context.Background(), code, accountCode, currency)
+}
+
+// RedeemContext records the call and returns the result of OnRedeem.
+func (m *RedemptionsService) RedeemContext(ctx context.Context, code string, accountCode string, currency string) (*recurly.Response, recurly.Redemption, error) {
+	m.record("Redeem", code, accountCode, currency)
+	if m.OnRedeem == nil {
+		return nil, recurly.Redemption{}, notSet("RedemptionsService", "Redeem")
+	}
+	return m.OnRedeem(ctx, code, accountCode, currency)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *RedemptionsService) Delete(accountCode string) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), accountCode)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *RedemptionsService) DeleteContext(ctx context.Context, accountCode string) (*recurly.Response, error) {
+	m.record("Delete", accountCode)
+	if m.OnDelete == nil {
+		return nil, notSet("RedemptionsService", "Delete")
+	}
+	return m.OnDelete(ctx, accountCode)
+}
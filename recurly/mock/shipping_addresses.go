@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.ShippingAddressesService = &ShippingAddressesService{}
+
+// ShippingAddressesService is a mock recurly.ShippingAddressesService. Each
+// call is recorded and answered by the matching On field, e.g. OnGet for Get
+// and GetContext. Calls whose On field is nil return an error.
+type ShippingAddressesService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.ShippingAddress, error)
+	OnGet    func(ctx context.Context, accountCode string, id int) (*recurly.Response, recurly.ShippingAddress, error)
+	OnCreate func(ctx context.Context, accountCode string, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error)
+	OnUpdate func(ctx context.Context, accountCode string, id int, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error)
+	OnDelete func(ctx context.Context, accountCode string, id int) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *ShippingAddressesService) List(accountCode string, params recurly.Params) (*recurly.Response, []recurly.ShippingAddress, error) {
+	return m.ListContext(context.Background(), accountCode, params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *ShippingAddressesService) ListContext(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.ShippingAddress, error) {
+	m.record("List", accountCode, params)
+	if m.OnList == nil {
+		return nil, nil, notSet("ShippingAddressesService", "List")
+	}
+	return m.OnList(ctx, accountCode, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *ShippingAddressesService) ListPager(accountCode string, params recurly.Params) *recurly.Pager[recurly.ShippingAddress] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.ShippingAddress, error) {
+		return m.ListContext(ctx, accountCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *ShippingAddressesService) Get(accountCode string, id int) (*recurly.Response, recurly.ShippingAddress, error) {
+	return m.GetContext(context.Background(), accountCode, id)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *ShippingAddressesService) GetContext(ctx context.Context, accountCode string, id int) (*recurly.Response, recurly.ShippingAddress, error) {
+	m.record("Get", accountCode, id)
+	if m.OnGet == nil {
+		return nil, recurly.ShippingAddress{}, notSet("ShippingAddressesService", "Get")
+	}
+	return m.OnGet(ctx, accountCode, id)
+}
+
+// Create calls CreateContext with a background context.
+func (m *ShippingAddressesService) Create(accountCode string, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error) {
+	return m.CreateContext(context.Background(), accountCode, address)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *ShippingAddressesService) CreateContext(ctx context.Context, accountCode string, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error) {
+	m.record("Create", accountCode, address)
+	if m.OnCreate == nil {
+		return nil, recurly.ShippingAddress{}, notSet("ShippingAddressesService", "Create")
+	}
+	return m.OnCreate(ctx, accountCode, address)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *ShippingAddressesService) Update(accountCode string, id int, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error) {
+	return m.UpdateContext(context.Background(), accountCode, id, address)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *ShippingAddressesService) UpdateContext(ctx context.Context, accountCode string, id int, address recurly.ShippingAddress) (*recurly.Response, recurly.ShippingAddress, error) {
+	m.record("Update", accountCode, id, address)
+	if m.OnUpdate == nil {
+		return nil, recurly.ShippingAddress{}, notSet("ShippingAddressesService", "Update")
+	}
+	return m.OnUpdate(ctx, accountCode, id, address)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *ShippingAddressesService) Delete(accountCode string, id int) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), accountCode, id)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *ShippingAddressesService) DeleteContext(ctx context.Context, accountCode string, id int) (*recurly.Response, error) {
+	m.record("Delete", accountCode, id)
+	if m.OnDelete == nil {
+		return nil, notSet("ShippingAddressesService", "Delete")
+	}
+	return m.OnDelete(ctx, accountCode, id)
+}
@@ -0,0 +1,302 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.SubscriptionsService = &SubscriptionsService{}
+
+// SubscriptionsService is a mock recurly.SubscriptionsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type SubscriptionsService struct {
+	Recorder
+
+	OnList                       func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Subscription, error)
+	OnListAccount                func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Subscription, error)
+	OnGet                        func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnCreate                     func(ctx context.Context, s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error)
+	OnPreview                    func(ctx context.Context, s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error)
+	OnUpdate                     func(ctx context.Context, uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error)
+	OnUpdateNotes                func(ctx context.Context, uuid string, n recurly.SubscriptionNotes) (*recurly.Response, recurly.Subscription, error)
+	OnPreviewChange              func(ctx context.Context, uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error)
+	OnCancel                     func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnReactivate                 func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnTerminateWithPartialRefund func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnTerminateWithFullRefund    func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnTerminateWithoutRefund     func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnPostpone                   func(ctx context.Context, uuid string, dt time.Time, bulk bool) (*recurly.Response, recurly.Subscription, error)
+	OnPause                      func(ctx context.Context, uuid string, cycles int) (*recurly.Response, recurly.Subscription, error)
+	OnRemovePause                func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnResume                     func(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error)
+	OnCancelPendingChange        func(ctx context.Context, uuid string) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *SubscriptionsService) List(params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *SubscriptionsService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("SubscriptionsService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *SubscriptionsService) ListPager(params recurly.Params) *recurly.Pager[recurly.Subscription] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// ListAccount calls ListAccountContext with a background context.
+func (m *SubscriptionsService) ListAccount(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+	return m.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext records the call and returns the result of OnListAccount.
+func (m *SubscriptionsService) ListAccountContext(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+	m.record("ListAccount", accountCode, params)
+	if m.OnListAccount == nil {
+		return nil, nil, notSet("SubscriptionsService", "ListAccount")
+	}
+	return m.OnListAccount(ctx, accountCode, params)
+}
+
+// ListAccountPager returns a Pager that fetches each page with ListAccountContext.
+func (m *SubscriptionsService) ListAccountPager(accountCode string, params recurly.Params) *recurly.Pager[recurly.Subscription] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+		return m.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *SubscriptionsService) Get(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.GetContext(context.Background(), uuid)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *SubscriptionsService) GetContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Get", uuid)
+	if m.OnGet == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Get")
+	}
+	return m.OnGet(ctx, uuid)
+}
+
+// Create calls CreateContext with a background context.
+func (m *SubscriptionsService) Create(s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error) {
+	return m.CreateContext(context.Background(), s)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *SubscriptionsService) CreateContext(ctx context.Context, s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Create", s)
+	if m.OnCreate == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Create")
+	}
+	return m.OnCreate(ctx, s)
+}
+
+// Preview calls PreviewContext with a background context.
+func (m *SubscriptionsService) Preview(s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error) {
+	return m.PreviewContext(context.Background(), s)
+}
+
+// PreviewContext records the call and returns the result of OnPreview.
+func (m *SubscriptionsService) PreviewContext(ctx context.Context, s recurly.NewSubscription) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Preview", s)
+	if m.OnPreview == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Preview")
+	}
+	return m.OnPreview(ctx, s)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *SubscriptionsService) Update(uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error) {
+	return m.UpdateContext(context.Background(), uuid, s)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *SubscriptionsService) UpdateContext(ctx context.Context, uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Update", uuid, s)
+	if m.OnUpdate == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Update")
+	}
+	return m.OnUpdate(ctx, uuid, s)
+}
+
+// UpdateNotes calls UpdateNotesContext with a background context.
+func (m *SubscriptionsService) UpdateNotes(uuid string, n recurly.SubscriptionNotes) (*recurly.Response, recurly.Subscription, error) {
+	return m.UpdateNotesContext(context.Background(), uuid, n)
+}
+
+// UpdateNotesContext records the call and returns the result of OnUpdateNotes.
+func (m *SubscriptionsService) UpdateNotesContext(ctx context.Context, uuid string, n recurly.SubscriptionNotes) (*recurly.Response, recurly.Subscription, error) {
+	m.record("UpdateNotes", uuid, n)
+	if m.OnUpdateNotes == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "UpdateNotes")
+	}
+	return m.OnUpdateNotes(ctx, uuid, n)
+}
+
+// PreviewChange calls PreviewChangeContext with a background context.
+func (m *SubscriptionsService) PreviewChange(uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error) {
+	return m.PreviewChangeContext(context.Background(), uuid, s)
+}
+
+// PreviewChangeContext records the call and returns the result of OnPreviewChange.
+func (m *SubscriptionsService) PreviewChangeContext(ctx context.Context, uuid string, s recurly.UpdateSubscription) (*recurly.Response, recurly.Subscription, error) {
+	m.record("PreviewChange", uuid, s)
+	if m.OnPreviewChange == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "PreviewChange")
+	}
+	return m.OnPreviewChange(ctx, uuid, s)
+}
+
+// Cancel calls CancelContext with a background context.
+func (m *SubscriptionsService) Cancel(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.CancelContext(context.Background(), uuid)
+}
+
+// CancelContext records the call and returns the result of OnCancel.
+func (m *SubscriptionsService) CancelContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Cancel", uuid)
+	if m.OnCancel == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Cancel")
+	}
+	return m.OnCancel(ctx, uuid)
+}
+
+// Reactivate calls ReactivateContext with a background context.
+func (m *SubscriptionsService) Reactivate(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.ReactivateContext(context.Background(), uuid)
+}
+
+// ReactivateContext records the call and returns the result of OnReactivate.
+func (m *SubscriptionsService) ReactivateContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Reactivate", uuid)
+	if m.OnReactivate == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Reactivate")
+	}
+	return m.OnReactivate(ctx, uuid)
+}
+
+// TerminateWithPartialRefund calls TerminateWithPartialRefundContext with a background context.
+func (m *SubscriptionsService) TerminateWithPartialRefund(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.TerminateWithPartialRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithPartialRefundContext records the call and returns the result of OnTerminateWithPartialRefund.
+func (m *SubscriptionsService) TerminateWithPartialRefundContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("TerminateWithPartialRefund", uuid)
+	if m.OnTerminateWithPartialRefund == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "TerminateWithPartialRefund")
+	}
+	return m.OnTerminateWithPartialRefund(ctx, uuid)
+}
+
+// TerminateWithFullRefund calls TerminateWithFullRefundContext with a background context.
+func (m *SubscriptionsService) TerminateWithFullRefund(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.TerminateWithFullRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithFullRefundContext records the call and returns the result of OnTerminateWithFullRefund.
+func (m *SubscriptionsService) TerminateWithFullRefundContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("TerminateWithFullRefund", uuid)
+	if m.OnTerminateWithFullRefund == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "TerminateWithFullRefund")
+	}
+	return m.OnTerminateWithFullRefund(ctx, uuid)
+}
+
+// TerminateWithoutRefund calls TerminateWithoutRefundContext with a background context.
+func (m *SubscriptionsService) TerminateWithoutRefund(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.TerminateWithoutRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithoutRefundContext records the call and returns the result of OnTerminateWithoutRefund.
+func (m *SubscriptionsService) TerminateWithoutRefundContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("TerminateWithoutRefund", uuid)
+	if m.OnTerminateWithoutRefund == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "TerminateWithoutRefund")
+	}
+	return m.OnTerminateWithoutRefund(ctx, uuid)
+}
+
+// Postpone calls PostponeContext with a background context.
+func (m *SubscriptionsService) Postpone(uuid string, dt time.Time, bulk bool) (*recurly.Response, recurly.Subscription, error) {
+	return m.PostponeContext(context.Background(), uuid, dt, bulk)
+}
+
+// PostponeContext records the call and returns the result of OnPostpone.
+func (m *SubscriptionsService) PostponeContext(ctx context.Context, uuid string, dt time.Time, bulk bool) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Postpone", uuid, dt, bulk)
+	if m.OnPostpone == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Postpone")
+	}
+	return m.OnPostpone(ctx, uuid, dt, bulk)
+}
+
+// Pause calls PauseContext with a background context.
+func (m *SubscriptionsService) Pause(uuid string, cycles int) (*recurly.Response, recurly.Subscription, error) {
+	return m.PauseContext(context.Background(), uuid, cycles)
+}
+
+// PauseContext records the call and returns the result of OnPause.
+func (m *SubscriptionsService) PauseContext(ctx context.Context, uuid string, cycles int) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Pause", uuid, cycles)
+	if m.OnPause == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Pause")
+	}
+	return m.OnPause(ctx, uuid, cycles)
+}
+
+// RemovePause calls RemovePauseContext with a background context.
+func (m *SubscriptionsService) RemovePause(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.RemovePauseContext(context.Background(), uuid)
+}
+
+// RemovePauseContext records the call and returns the result of OnRemovePause.
+func (m *SubscriptionsService) RemovePauseContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("RemovePause", uuid)
+	if m.OnRemovePause == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "RemovePause")
+	}
+	return m.OnRemovePause(ctx, uuid)
+}
+
+// Resume calls ResumeContext with a background context.
+func (m *SubscriptionsService) Resume(uuid string) (*recurly.Response, recurly.Subscription, error) {
+	return m.ResumeContext(context.Background(), uuid)
+}
+
+// ResumeContext records the call and returns the result of OnResume.
+func (m *SubscriptionsService) ResumeContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Subscription, error) {
+	m.record("Resume", uuid)
+	if m.OnResume == nil {
+		return nil, recurly.Subscription{}, notSet("SubscriptionsService", "Resume")
+	}
+	return m.OnResume(ctx, uuid)
+}
+
+// CancelPendingChange calls CancelPendingChangeContext with a background context.
+func (m *SubscriptionsService) CancelPendingChange(uuid string) (*recurly.Response, error) {
+	return m.CancelPendingChangeContext(context.Background(), uuid)
+}
+
+// CancelPendingChangeContext records the call and returns the result of OnCancelPendingChange.
+func (m *SubscriptionsService) CancelPendingChangeContext(ctx context.Context, uuid string) (*recurly.Response, error) {
+	m.record("CancelPendingChange", uuid)
+	if m.OnCancelPendingChange == nil {
+		return nil, notSet("SubscriptionsService", "CancelPendingChange")
+	}
+	return m.OnCancelPendingChange(ctx, uuid)
+}
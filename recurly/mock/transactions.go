@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.TransactionsService = &TransactionsService{}
+
+// TransactionsService is a mock recurly.TransactionsService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type TransactionsService struct {
+	Recorder
+
+	OnList        func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Transaction, error)
+	OnListAccount func(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error)
+	OnGet         func(ctx context.Context, uuid string) (*recurly.Response, recurly.Transaction, error)
+	OnCreate      func(ctx context.Context, nt recurly.NewTransaction) (*recurly.Response, recurly.Transaction, error)
+	OnRefund      func(ctx context.Context, uuid string, amountInCents int) (*recurly.Response, recurly.Transaction, error)
+	OnVoid        func(ctx context.Context, uuid string) (*recurly.Response, recurly.Transaction, error)
+}
+
+// List calls ListContext with a background context.
+func (m *TransactionsService) List(params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	return m.ListContext(context.Background(), params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *TransactionsService) ListContext(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	m.record("List", params)
+	if m.OnList == nil {
+		return nil, nil, notSet("TransactionsService", "List")
+	}
+	return m.OnList(ctx, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *TransactionsService) ListPager(params recurly.Params) *recurly.Pager[recurly.Transaction] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+		return m.ListContext(ctx, params)
+	})
+}
+
+// ListAccount calls ListAccountContext with a background context.
+func (m *TransactionsService) ListAccount(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	return m.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext records the call and returns the result of OnListAccount.
+func (m *TransactionsService) ListAccountContext(ctx context.Context, accountCode string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	m.record("ListAccount", accountCode, params)
+	if m.OnListAccount == nil {
+		return nil, nil, notSet("TransactionsService", "ListAccount")
+	}
+	return m.OnListAccount(ctx, accountCode, params)
+}
+
+// ListAccountPager returns a Pager that fetches each page with ListAccountContext.
+func (m *TransactionsService) ListAccountPager(accountCode string, params recurly.Params) *recurly.Pager[recurly.Transaction] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+		return m.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *TransactionsService) Get(uuid string) (*recurly.Response, recurly.Transaction, error) {
+	return m.GetContext(context.Background(), uuid)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *TransactionsService) GetContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Transaction, error) {
+	m.record("Get", uuid)
+	if m.OnGet == nil {
+		return nil, recurly.Transaction{}, notSet("TransactionsService", "Get")
+	}
+	return m.OnGet(ctx, uuid)
+}
+
+// Create calls CreateContext with a background context.
+func (m *TransactionsService) Create(nt recurly.NewTransaction) (*recurly.Response, recurly.Transaction, error) {
+	return m.CreateContext(context.Background(), nt)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *TransactionsService) CreateContext(ctx context.Context, nt recurly.NewTransaction) (*recurly.Response, recurly.Transaction, error) {
+	m.record("Create", nt)
+	if m.OnCreate == nil {
+		return nil, recurly.Transaction{}, notSet("TransactionsService", "Create")
+	}
+	return m.OnCreate(ctx, nt)
+}
+
+// Refund calls RefundContext with a background context.
+func (m *TransactionsService) Refund(uuid string, amountInCents int) (*recurly.Response, recurly.Transaction, error) {
+	return m.RefundContext(context.Background(), uuid, amountInCents)
+}
+
+// RefundContext records the call and returns the result of OnRefund.
+func (m *TransactionsService) RefundContext(ctx context.Context, uuid string, amountInCents int) (*recurly.Response, recurly.Transaction, error) {
+	m.record("Refund", uuid, amountInCents)
+	if m.OnRefund == nil {
+		return nil, recurly.Transaction{}, notSet("TransactionsService", "Refund")
+	}
+	return m.OnRefund(ctx, uuid, amountInCents)
+}
+
+// Void calls VoidContext with a background context.
+func (m *TransactionsService) Void(uuid string) (*recurly.Response, recurly.Transaction, error) {
+	return m.VoidContext(context.Background(), uuid)
+}
+
+// VoidContext records the call and returns the result of OnVoid.
+func (m *TransactionsService) VoidContext(ctx context.Context, uuid string) (*recurly.Response, recurly.Transaction, error) {
+	m.record("Void", uuid)
+	if m.OnVoid == nil {
+		return nil, recurly.Transaction{}, notSet("TransactionsService", "Void")
+	}
+	return m.OnVoid(ctx, uuid)
+}
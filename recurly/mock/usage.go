@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var _ recurly.UsageService = &UsageService{}
+
+// UsageService is a mock recurly.UsageService. Each call is recorded and
+// answered by the matching On field, e.g. OnGet for Get and GetContext.
+// Calls whose On field is nil return an error.
+type UsageService struct {
+	Recorder
+
+	OnList   func(ctx context.Context, subUUID string, addOnCode string, params recurly.Params) (*recurly.Response, []recurly.Usage, error)
+	OnGet    func(ctx context.Context, subUUID string, addOnCode string, id int) (*recurly.Response, recurly.Usage, error)
+	OnCreate func(ctx context.Context, subUUID string, addOnCode string, u recurly.Usage) (*recurly.Response, recurly.Usage, error)
+	OnUpdate func(ctx context.Context, subUUID string, addOnCode string, id int, u recurly.Usage) (*recurly.Response, recurly.Usage, error)
+	OnDelete func(ctx context.Context, subUUID string, addOnCode string, id int) (*recurly.Response, error)
+}
+
+// List calls ListContext with a background context.
+func (m *UsageService) List(subUUID string, addOnCode string, params recurly.Params) (*recurly.Response, []recurly.Usage, error) {
+	return m.ListContext(context.Background(), subUUID, addOnCode, params)
+}
+
+// ListContext records the call and returns the result of OnList.
+func (m *UsageService) ListContext(ctx context.Context, subUUID string, addOnCode string, params recurly.Params) (*recurly.Response, []recurly.Usage, error) {
+	m.record("List", subUUID, addOnCode, params)
+	if m.OnList == nil {
+		return nil, nil, notSet("UsageService", "List")
+	}
+	return m.OnList(ctx, subUUID, addOnCode, params)
+}
+
+// ListPager returns a Pager that fetches each page with ListContext.
+func (m *UsageService) ListPager(subUUID string, addOnCode string, params recurly.Params) *recurly.Pager[recurly.Usage] {
+	return recurly.NewPager(params, func(ctx context.Context, params recurly.Params) (*recurly.Response, []recurly.Usage, error) {
+		return m.ListContext(ctx, subUUID, addOnCode, params)
+	})
+}
+
+// Get calls GetContext with a background context.
+func (m *UsageService) Get(subUUID string, addOnCode string, id int) (*recurly.Response, recurly.Usage, error) {
+	return m.GetContext(context.Background(), subUUID, addOnCode, id)
+}
+
+// GetContext records the call and returns the result of OnGet.
+func (m *UsageService) GetContext(ctx context.Context, subUUID string, addOnCode string, id int) (*recurly.Response, recurly.Usage, error) {
+	m.record("Get", subUUID, addOnCode, id)
+	if m.OnGet == nil {
+		return nil, recurly.Usage{}, notSet("UsageService", "Get")
+	}
+	return m.OnGet(ctx, subUUID, addOnCode, id)
+}
+
+// Create calls CreateContext with a background context.
+func (m *UsageService) Create(subUUID string, addOnCode string, u recurly.Usage) (*recurly.Response, recurly.Usage, error) {
+	return m.CreateContext(context.Background(), subUUID, addOnCode, u)
+}
+
+// CreateContext records the call and returns the result of OnCreate.
+func (m *UsageService) CreateContext(ctx context.Context, subUUID string, addOnCode string, u recurly.Usage) (*recurly.Response, recurly.Usage, error) {
+	m.record("Create", subUUID, addOnCode, u)
+	if m.OnCreate == nil {
+		return nil, recurly.Usage{}, notSet("UsageService", "Create")
+	}
+	return m.OnCreate(ctx, subUUID, addOnCode, u)
+}
+
+// Update calls UpdateContext with a background context.
+func (m *UsageService) Update(subUUID string, addOnCode string, id int, u recurly.Usage) (*recurly.Response, recurly.Usage, error) {
+	return m.UpdateContext(context.Background(), subUUID, addOnCode, id, u)
+}
+
+// UpdateContext records the call and returns the result of OnUpdate.
+func (m *UsageService) UpdateContext(ctx context.Context, subUUID string, addOnCode string, id int, u recurly.Usage) (*recurly.Response, recurly.Usage, error) {
+	m.record("Update", subUUID, addOnCode, id, u)
+	if m.OnUpdate == nil {
+		return nil, recurly.Usage{}, notSet("UsageService", "Update")
+	}
+	return m.OnUpdate(ctx, subUUID, addOnCode, id, u)
+}
+
+// Delete calls DeleteContext with a background context.
+func (m *UsageService) Delete(subUUID string, addOnCode string, id int) (*recurly.Response, error) {
+	return m.DeleteContext(context.Background(), subUUID, addOnCode, id)
+}
+
+// DeleteContext records the call and returns the result of OnDelete.
+func (m *UsageService) DeleteContext(ctx context.Context, subUUID string, addOnCode string, id int) (*recurly.Response, error) {
+	m.record("Delete", subUUID, addOnCode, id)
+	if m.OnDelete == nil {
+		return nil, notSet("UsageService", "Delete")
+	}
+	return m.OnDelete(ctx, subUUID, addOnCode, id)
+}
@@ -0,0 +1,172 @@
+// Package otelrecurly instruments a recurly.Client with OpenTelemetry.
+//
+// Add the middleware to a client to trace and measure every API call:
+//
+//	client.Middleware = append(client.Middleware, otelrecurly.Middleware())
+//
+// Each call produces a client span named after the service method, such as
+// "recurly.Subscriptions.Create", with the HTTP method, the endpoint's path
+// template, the status code and any Recurly error symbols as attributes. A
+// counter and a latency histogram record every call by operation and
+// status code.
+//
+// The package lives apart from recurly so the core library doesn't depend
+// on OpenTelemetry.
+package otelrecurly
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer and meter used by the middleware.
+const instrumentationName = "github.com/blacklightcms/go-recurly/recurly/otelrecurly"
+
+// Attribute keys set on spans and metrics, in addition to the standard
+// HTTP ones.
+const (
+	OperationKey        = attribute.Key("recurly.operation")
+	ErrorSymbolsKey     = attribute.Key("recurly.error.symbols")
+	TransactionErrorKey = attribute.Key("recurly.transaction_error.code")
+)
+
+type (
+	// Option configures the middleware.
+	Option func(*config)
+
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+	}
+)
+
+// WithTracerProvider sets the TracerProvider spans are created with.
+// Defaults to the global TracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the MeterProvider metrics are recorded with.
+// Defaults to the global MeterProvider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Middleware returns recurly.Middleware that traces and measures every API
+// call. Errors creating the metric instruments are reported to the global
+// OpenTelemetry error handler.
+func Middleware(opts ...Option) recurly.Middleware {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	requests, err := meter.Int64Counter("recurly.client.requests",
+		metric.WithDescription("Number of Recurly API calls."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	duration, err := meter.Float64Histogram("recurly.client.request.duration",
+		metric.WithDescription("Duration of Recurly API calls."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next recurly.RoundTripFunc) recurly.RoundTripFunc {
+		return func(req *http.Request) (*recurly.Response, error) {
+			name := "recurly " + req.Method
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("server.address", req.URL.Hostname()),
+			}
+			if op, ok := recurly.RequestOperation(req); ok {
+				name = "recurly." + op.Name
+				attrs = append(attrs,
+					OperationKey.String(op.Name),
+					attribute.String("url.template", "/v2/"+op.Route),
+				)
+			}
+
+			ctx, span := tracer.Start(req.Context(), name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...))
+			defer span.End()
+
+			start := time.Now()
+			resp, err := next(req.WithContext(ctx))
+			elapsed := time.Since(start)
+
+			var errorType string
+			if resp != nil && resp.Response != nil {
+				attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+				span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+				if symbols := errorSymbols(resp); len(symbols) > 0 {
+					span.SetAttributes(ErrorSymbolsKey.StringSlice(symbols))
+				}
+				if code := resp.TransactionError.ErrorCode; code != "" {
+					span.SetAttributes(TransactionErrorKey.String(code))
+				}
+				if resp.IsError() {
+					errorType = strconv.Itoa(resp.StatusCode)
+				}
+			}
+			if err != nil {
+				span.RecordError(err)
+				if errorType == "" {
+					errorType = "_OTHER"
+				}
+			}
+			if errorType != "" {
+				attrs = append(attrs, attribute.String("error.type", errorType))
+				span.SetAttributes(attribute.String("error.type", errorType))
+				span.SetStatus(codes.Error, errorType)
+			}
+
+			set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+			if requests != nil {
+				requests.Add(ctx, 1, set)
+			}
+			if duration != nil {
+				duration.Record(ctx, elapsed.Seconds(), set)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// errorSymbols returns the symbols of the errors in resp, without
+// duplicates.
+func errorSymbols(resp *recurly.Response) []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, e := range resp.Errors {
+		if e.Symbol == "" || seen[e.Symbol] {
+			continue
+		}
+		seen[e.Symbol] = true
+		symbols = append(symbols, e.Symbol)
+	}
+	return symbols
+}
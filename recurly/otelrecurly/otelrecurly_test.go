@@ -0,0 +1,142 @@
+package otelrecurly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setup returns a client for a test server using mux, instrumented with an
+// in-memory span exporter and a manual metric reader.
+func setup(t *testing.T, mux *http.ServeMux) (*recurly.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := recurly.NewClient("test", "abc", nil)
+	client.BaseURL = server.URL + "/"
+	client.Middleware = []recurly.Middleware{Middleware(WithTracerProvider(tp), WithMeterProvider(mp))}
+
+	return client, exporter, reader
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Error("TestMiddleware Error: Expected request to be authenticated")
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>canceled</state></subscription>`)
+	})
+
+	client, exporter, reader := setup(t, mux)
+	if _, s, err := client.Subscriptions.Cancel("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("TestMiddleware Error: Error occurred making API call. Err: %s", err)
+	} else if s.State != "canceled" {
+		t.Errorf("TestMiddleware Error: Expected subscription to be decoded, given %#v", s)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("TestMiddleware Error: Expected 1 span, given %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "recurly.Subscriptions.Cancel" {
+		t.Errorf("TestMiddleware Error: Expected span name recurly.Subscriptions.Cancel, given %s", span.Name)
+	} else if span.Status.Code != codes.Unset {
+		t.Errorf("TestMiddleware Error: Expected unset status, given %v", span.Status)
+	}
+
+	attrs := attribute.NewSet(span.Attributes...)
+	for k, v := range map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue("PUT"),
+		"url.template":              attribute.StringValue("/v2/subscriptions/{uuid}/cancel"),
+		"http.response.status_code": attribute.IntValue(200),
+		OperationKey:                attribute.StringValue("Subscriptions.Cancel"),
+	} {
+		if given, ok := attrs.Value(k); !ok || given != v {
+			t.Errorf("TestMiddleware Error: Expected %s to be %v, given %v", k, v.Emit(), given.Emit())
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("TestMiddleware Error: Error collecting metrics. Err: %s", err)
+	}
+
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if len(data.DataPoints) != 1 || data.DataPoints[0].Value != 1 {
+					t.Errorf("TestMiddleware Error: Expected one request, given %#v", data.DataPoints)
+				} else if v, _ := data.DataPoints[0].Attributes.Value(OperationKey); v.AsString() != "Subscriptions.Cancel" {
+					t.Errorf("TestMiddleware Error: Expected operation attribute, given %v", v.Emit())
+				}
+			case metricdata.Histogram[float64]:
+				if len(data.DataPoints) != 1 || data.DataPoints[0].Count != 1 {
+					t.Errorf("TestMiddleware Error: Expected one duration, given %#v", data.DataPoints)
+				}
+			}
+		}
+	}
+
+	if !found["recurly.client.requests"] || !found["recurly.client.request.duration"] {
+		t.Errorf("TestMiddleware Error: Expected request counter and duration histogram, given %v", found)
+	}
+}
+
+func TestMiddlewareErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="account.account_code" symbol="taken">has already been taken</error><error field="account.email" symbol="invalid_email">is not a valid email</error></errors>`)
+	})
+
+	client, exporter, _ := setup(t, mux)
+	client.TypedErrors = true
+	if _, _, err := client.Accounts.Create(recurly.Account{Code: "1"}); err == nil {
+		t.Fatal("TestMiddlewareErrors Error: Expected validation error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("TestMiddlewareErrors Error: Expected 1 span, given %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Status.Code != codes.Error {
+		t.Errorf("TestMiddlewareErrors Error: Expected error status, given %v", span.Status)
+	}
+
+	attrs := attribute.NewSet(span.Attributes...)
+	if v, _ := attrs.Value(ErrorSymbolsKey); fmt.Sprint(v.AsStringSlice()) != "[taken invalid_email]" {
+		t.Errorf("TestMiddlewareErrors Error: Expected error symbols, given %v", v.Emit())
+	}
+
+	if v, _ := attrs.Value("error.type"); v.AsString() != "422" {
+		t.Errorf("TestMiddlewareErrors Error: Expected error type 422, given %v", v.Emit())
+	}
+
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Errorf("TestMiddlewareErrors Error: Expected error to be recorded, given %#v", span.Events)
+	}
+}
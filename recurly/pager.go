@@ -0,0 +1,134 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Pager lazily walks every page of a paginated list endpoint. It is used
+	// like a bufio.Scanner:
+	//
+	//	pager := client.Accounts.ListPager(recurly.Params{"per_page": 50})
+	//	for pager.Next(ctx) {
+	//		a := pager.Value()
+	//		// ...
+	//	}
+	//	if err := pager.Err(); err != nil {
+	//		// ...
+	//	}
+	//
+	// Pages are only requested as they are needed, so breaking out of the
+	// loop stops the pager without making further API calls.
+	Pager[T any] struct {
+		fetch   func(ctx context.Context, params Params) (*Response, []T, error)
+		params  Params
+		cursor  string
+		started bool
+		done    bool
+
+		items []T
+		index int
+		res   *Response
+		err   error
+	}
+)
+
+// NewPager creates a Pager that calls fetch for each page. The caller's
+// params are copied so cursors can be added without modifying them. It is
+// exported so other implementations of the service interfaces, such as
+// mocks, can return a Pager.
+func NewPager[T any](params Params, fetch func(ctx context.Context, params Params) (*Response, []T, error)) *Pager[T] {
+	return &Pager[T]{
+		fetch:  fetch,
+		params: params,
+		index:  -1,
+	}
+}
+
+// Next advances the pager to the next item, fetching the next page when the
+// current one is exhausted. It returns false when there are no more items or
+// an error occurred; check Err to tell the difference.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+
+	for p.index+1 >= len(p.items) {
+		if p.done {
+			return false
+		}
+		if !p.fetchPage(ctx) {
+			return false
+		}
+	}
+
+	p.index++
+	return true
+}
+
+// fetchPage requests the next page of results.
+func (p *Pager[T]) fetchPage(ctx context.Context) bool {
+	params := Params{}
+	for k, v := range p.params {
+		params[k] = v
+	}
+	if p.started {
+		params["cursor"] = p.cursor
+	}
+
+	res, items, err := p.fetch(ctx, params)
+	p.started = true
+	if res != nil {
+		p.res = res
+	}
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if res != nil && res.IsError() {
+		p.err = fmt.Errorf("recurly: list request failed with status code %d", res.StatusCode)
+		return false
+	}
+
+	p.items = items
+	p.index = -1
+	p.cursor = ""
+	if res != nil {
+		p.cursor = res.Next()
+	}
+	p.done = p.cursor == ""
+
+	return true
+}
+
+// Value returns the current item. It should only be called after a call to
+// Next returns true.
+func (p *Pager[T]) Value() T {
+	if p.index < 0 || p.index >= len(p.items) {
+		var zero T
+		return zero
+	}
+	return p.items[p.index]
+}
+
+// Err returns the first error encountered while fetching pages.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Response returns the response for the most recently fetched page. It
+// returns nil until the first page has been requested.
+func (p *Pager[T]) Response() *Response {
+	return p.res
+}
+
+// Total returns the total number of records across all pages, as reported
+// by the X-Records header of the most recently fetched page. It returns -1
+// if no page has been fetched or the header was not present.
+func (p *Pager[T]) Total() int {
+	if p.res == nil {
+		return -1
+	}
+	return p.res.TotalRecords()
+}
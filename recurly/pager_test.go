@@ -0,0 +1,149 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPagerWalksAllPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var cursors []string
+	mux.HandleFunc("/v2/accounts", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("TestPagerWalksAllPages Error: Expected per_page of %s, given %s", "2", r.URL.Query().Get("per_page"))
+		}
+
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		rw.Header().Set("X-Records", "5")
+		switch cursor {
+		case "":
+			rw.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts?cursor=1304958672>; rel="next"`)
+			fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><accounts><account><account_code>1</account_code></account><account><account_code>2</account_code></account></accounts>`)
+		case "1304958672":
+			rw.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts>; rel="start",
+  <https://your-subdomain.recurly.com/v2/accounts?cursor=-1304958672>; rel="prev",
+  <https://your-subdomain.recurly.com/v2/accounts?cursor=1318388868>; rel="next"`)
+			fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><accounts><account><account_code>3</account_code></account><account><account_code>4</account_code></account></accounts>`)
+		case "1318388868":
+			rw.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts>; rel="start",
+  <https://your-subdomain.recurly.com/v2/accounts?cursor=-1318388868>; rel="prev"`)
+			fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><accounts><account><account_code>5</account_code></account></accounts>`)
+		default:
+			t.Errorf("TestPagerWalksAllPages Error: Unexpected cursor %s", cursor)
+		}
+	})
+
+	params := Params{"per_page": 2}
+	pager := client.Accounts.ListPager(params)
+	if pager.Total() != -1 {
+		t.Errorf("TestPagerWalksAllPages Error: Expected total of -1 before the first page, given %d", pager.Total())
+	}
+
+	var codes []string
+	for pager.Next(context.Background()) {
+		codes = append(codes, pager.Value().Code)
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("TestPagerWalksAllPages Error: Error occurred walking pages. Err: %s", err)
+	}
+
+	if expected := []string{"1", "2", "3", "4", "5"}; !reflect.DeepEqual(expected, codes) {
+		t.Errorf("TestPagerWalksAllPages Error: Expected account codes %v, given %v", expected, codes)
+	}
+
+	if expected := []string{"", "1304958672", "1318388868"}; !reflect.DeepEqual(expected, cursors) {
+		t.Errorf("TestPagerWalksAllPages Error: Expected cursors %v, given %v", expected, cursors)
+	}
+
+	if pager.Total() != 5 {
+		t.Errorf("TestPagerWalksAllPages Error: Expected total of %d, given %d", 5, pager.Total())
+	}
+
+	if len(params) != 1 {
+		t.Errorf("TestPagerWalksAllPages Error: Expected params to be left unmodified, given %v", params)
+	}
+}
+
+func TestPagerEarlyStop(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/v2/accounts/1/subscriptions", func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts/1/subscriptions?cursor=1318388868>; rel="next"`)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><subscriptions><subscription><uuid>a</uuid></subscription><subscription><uuid>b</uuid></subscription></subscriptions>`)
+	})
+
+	pager := client.Subscriptions.ListAccountPager("1", nil)
+	for pager.Next(context.Background()) {
+		if pager.Value().UUID == "b" {
+			break
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("TestPagerEarlyStop Error: Expected %d request, given %d", 1, requests)
+	}
+}
+
+func TestPagerAccountScopedList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/100/adjustments", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "pending" {
+			t.Errorf("TestPagerAccountScopedList Error: Expected state param of %s, given %s", "pending", r.URL.Query().Get("state"))
+		}
+		rw.Header().Set("X-Records", "1")
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><adjustments><adjustment><uuid>626db120a84102b1809909071c701c60</uuid></adjustment></adjustments>`)
+	})
+
+	pager := client.Adjustments.ListPager("100", Params{"state": "pending"})
+	var uuids []string
+	for pager.Next(context.Background()) {
+		uuids = append(uuids, pager.Value().UUID)
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("TestPagerAccountScopedList Error: Error occurred walking pages. Err: %s", err)
+	}
+
+	if expected := []string{"626db120a84102b1809909071c701c60"}; !reflect.DeepEqual(expected, uuids) {
+		t.Errorf("TestPagerAccountScopedList Error: Expected uuids %v, given %v", expected, uuids)
+	}
+
+	if pager.Total() != 1 {
+		t.Errorf("TestPagerAccountScopedList Error: Expected total of %d, given %d", 1, pager.Total())
+	}
+}
+
+func TestPagerErrorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/plans/gold/add_ons", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description>Couldn't find Plan with plan_code = gold</description></error>`)
+	})
+
+	pager := client.AddOns.ListPager("gold", nil)
+	if pager.Next(context.Background()) {
+		t.Fatal("TestPagerErrorResponse Error: Expected Next to return false")
+	}
+
+	if pager.Err() == nil {
+		t.Error("TestPagerErrorResponse Error: Expected an error, given nil")
+	}
+
+	if pager.Response() == nil || pager.Response().StatusCode != http.StatusNotFound {
+		t.Errorf("TestPagerErrorResponse Error: Expected response with 404 status code, given %#v", pager.Response())
+	}
+}
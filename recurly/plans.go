@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -8,7 +9,22 @@ import (
 type (
 	// PlansService handles communication with the plans related methods
 	// of the recurly API.
-	PlansService struct {
+	PlansService interface {
+		List(params Params) (*Response, []Plan, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Plan, error)
+		ListPager(params Params) *Pager[Plan]
+		Get(code string) (*Response, Plan, error)
+		GetContext(ctx context.Context, code string) (*Response, Plan, error)
+		Create(p Plan) (*Response, Plan, error)
+		CreateContext(ctx context.Context, p Plan) (*Response, Plan, error)
+		Update(code string, p Plan) (*Response, Plan, error)
+		UpdateContext(ctx context.Context, code string, p Plan) (*Response, Plan, error)
+		Delete(code string) (*Response, error)
+		DeleteContext(ctx context.Context, code string) (*Response, error)
+	}
+
+	// plansImpl implements PlansService.
+	plansImpl struct {
 		client *Client
 	}
 
@@ -40,10 +56,58 @@ type (
 	}
 )
 
+// Plan interval units, for IntervalUnit and TrialIntervalUnit.
+const (
+	PlanIntervalUnitDays   = "days"
+	PlanIntervalUnitMonths = "months"
+)
+
+// Validate checks the plan for mistakes Recurly would reject, such as a
+// missing code or name, an interval unit other than days or months, or
+// prices in unknown currencies or below zero. It returns a *ValidationError
+// listing every problem found, or nil. See Client.ValidateRequests.
+func (p Plan) Validate() error {
+	return p.validate(true)
+}
+
+// ValidateUpdate is the same as Validate, but for a partial update with
+// Update: the fields that aren't set are left unchanged, so none are
+// required.
+func (p Plan) ValidateUpdate() error {
+	return p.validate(false)
+}
+
+// validate checks p, requiring the fields needed to create a plan if create
+// is true.
+func (p Plan) validate(create bool) error {
+	var v validation
+	if create {
+		v.required("plan.plan_code", p.Code != "")
+		v.required("plan.name", p.Name != "")
+	}
+	v.oneOf("plan.plan_interval_unit", p.IntervalUnit, PlanIntervalUnitDays, PlanIntervalUnitMonths)
+	v.nonNegative("plan.plan_interval_length", p.IntervalLength)
+	v.oneOf("plan.trial_interval_unit", p.TrialIntervalUnit, PlanIntervalUnitDays, PlanIntervalUnitMonths)
+	v.nonNegative("plan.trial_interval_length", p.TrialIntervalLength)
+	if p.TotalBillingCycles.Valid {
+		v.nonNegative("plan.total_billing_cycles", p.TotalBillingCycles.Int)
+	}
+	v.unitAmount("plan.unit_amount_in_cents", p.UnitAmountInCents)
+	v.unitAmount("plan.setup_fee_in_cents", p.SetupFeeInCents)
+
+	return v.err()
+}
+
 // List will retrieve all your active subscription plans.
 // https://docs.recurly.com/api/plans#list-plans
-func (service PlansService) List(params Params) (*Response, []Plan, error) {
-	req, err := service.client.newRequest("GET", "plans", params, nil)
+func (service plansImpl) List(params Params) (*Response, []Plan, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service plansImpl) ListContext(ctx context.Context, params Params) (*Response, []Plan, error) {
+	ctx = withOperation(ctx, "Plans.List", "plans")
+	req, err := service.client.newRequest(ctx, "GET", "plans", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,11 +121,24 @@ func (service PlansService) List(params Params) (*Response, []Plan, error) {
 	return res, p.Plans, err
 }
 
+// ListPager returns a Pager that walks every page of plans.
+func (service plansImpl) ListPager(params Params) *Pager[Plan] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Plan, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // Get will lookup a specific plan by code.
 // https://docs.recurly.com/api/plans#lookup-plan
-func (service PlansService) Get(code string) (*Response, Plan, error) {
+func (service plansImpl) Get(code string) (*Response, Plan, error) {
+	return service.GetContext(context.Background(), code)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service plansImpl) GetContext(ctx context.Context, code string) (*Response, Plan, error) {
+	ctx = withOperation(ctx, "Plans.Get", "plans/{plan_code}")
 	action := fmt.Sprintf("plans/%s", code)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Plan{}, err
 	}
@@ -74,8 +151,14 @@ func (service PlansService) Get(code string) (*Response, Plan, error) {
 
 // Create will create a new subscription plan.
 // https://docs.recurly.com/api/plans#create-plan
-func (service PlansService) Create(p Plan) (*Response, Plan, error) {
-	req, err := service.client.newRequest("POST", "plans", nil, p)
+func (service plansImpl) Create(p Plan) (*Response, Plan, error) {
+	return service.CreateContext(context.Background(), p)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service plansImpl) CreateContext(ctx context.Context, p Plan) (*Response, Plan, error) {
+	ctx = withOperation(ctx, "Plans.Create", "plans")
+	req, err := service.client.newRequest(ctx, "POST", "plans", nil, p)
 	if err != nil {
 		return nil, Plan{}, err
 	}
@@ -89,9 +172,15 @@ func (service PlansService) Create(p Plan) (*Response, Plan, error) {
 // Update will update the pricing or details for a plan. Existing subscriptions
 // will remain at the previous renewal amounts.
 // https://docs.recurly.com/api/plans#update-plan
-func (service PlansService) Update(code string, p Plan) (*Response, Plan, error) {
+func (service plansImpl) Update(code string, p Plan) (*Response, Plan, error) {
+	return service.UpdateContext(context.Background(), code, p)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service plansImpl) UpdateContext(ctx context.Context, code string, p Plan) (*Response, Plan, error) {
+	ctx = withOperation(ctx, "Plans.Update", "plans/{plan_code}")
 	action := fmt.Sprintf("plans/%s", code)
-	req, err := service.client.newRequest("PUT", action, nil, p)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, p)
 	if err != nil {
 		return nil, Plan{}, err
 	}
@@ -104,9 +193,15 @@ func (service PlansService) Update(code string, p Plan) (*Response, Plan, error)
 
 // Delete will make a plan inactive. New accounts cannot be created on the plan.
 // https://docs.recurly.com/api/plans#delete-plan
-func (service PlansService) Delete(code string) (*Response, error) {
+func (service plansImpl) Delete(code string) (*Response, error) {
+	return service.DeleteContext(context.Background(), code)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service plansImpl) DeleteContext(ctx context.Context, code string) (*Response, error) {
+	ctx = withOperation(ctx, "Plans.Delete", "plans/{plan_code}")
 	action := fmt.Sprintf("plans/%s", code)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
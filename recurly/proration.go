@@ -0,0 +1,359 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	// Proration holds the line items a subscription change creates. Credits
+	// have negative unit amounts.
+	Proration struct {
+		// Now holds the credits for the unused part of the current term and
+		// the charges for the rest of it at the new price, or for a new term
+		// when the billing interval changes. It is empty for changes that
+		// wait for renewal and for subscriptions in a trial.
+		Now []Adjustment
+
+		// Renewal holds the charges for the first full term after the
+		// change.
+		Renewal []Adjustment
+	}
+
+	// prorationLine is the price of the plan or one add on of a
+	// subscription.
+	prorationLine struct {
+		code      string
+		origin    string
+		unit      int
+		quantity  int
+		isPlan    bool
+		isChanged bool
+	}
+)
+
+// Prorate computes the line items Recurly creates when a subscription is
+// changed by u at the given time, without making a request. Quotes for a
+// checkout page can be shown instantly, and the result can be compared with
+// SubscriptionsService.PreviewChange in tests.
+//
+// current is the plan the subscription is on now. plan is the plan it is on
+// after the change, and addOns are that plan's add ons. They are used to
+// price a new plan or add on unless u gives its unit amount. When u has no
+// add ons, the subscription keeps its current add ons at their current
+// prices.
+//
+// With a Timeframe of "now" (or none), the unused part of the current term
+// is credited and charged again at the new price, prorated by the seconds
+// left until CurrentPeriodEndsAt. Lines that don't change are left out.
+// Changes with a Timeframe of "renewal" or "bill_date" only affect Renewal.
+// Usage add ons are billed for the usage logged against them, so they are
+// left out too.
+//
+// A change to a plan with a different billing interval than current starts a
+// new term at the time of the change instead: every line of the current term
+// is credited for the unused part, the new term is charged in full, and
+// Renewal starts when the new term ends.
+func Prorate(s Subscription, current Plan, plan Plan, addOns []AddOn, u UpdateSubscription, at time.Time) (Proration, error) {
+	if current.Code != s.Plan.Code {
+		return Proration{}, fmt.Errorf("recurly: expected current plan %q, given %q", s.Plan.Code, current.Code)
+	} else if s.State == SubscriptionStateExpired {
+		return Proration{}, errors.New("recurly: expired subscriptions can't be changed")
+	} else if s.CurrentPeriodStartedAt.Time == nil || s.CurrentPeriodEndsAt.Time == nil {
+		return Proration{}, errors.New("recurly: subscription has no current period")
+	}
+
+	start, end := *s.CurrentPeriodStartedAt.Time, *s.CurrentPeriodEndsAt.Time
+	if !end.After(start) {
+		return Proration{}, errors.New("recurly: subscription period ends before it starts")
+	} else if at.Before(start) || at.After(end) {
+		return Proration{}, fmt.Errorf("recurly: %s is outside the current period", at.UTC().Format(datetimeFormat))
+	}
+
+	usage := make(map[string]bool)
+	for _, a := range addOns {
+		usage[a.Code] = a.AddOnType == AddOnTypeUsage
+	}
+
+	lines, err := currentLines(s, usage)
+	if err != nil {
+		return Proration{}, err
+	}
+	changed, err := changedLines(s, plan, addOns, u, lines, usage)
+	if err != nil {
+		return Proration{}, err
+	}
+
+	var p Proration
+	renewal := end
+	inTrial := s.TrialEndsAt.Time != nil && s.TrialEndsAt.After(at)
+	switch u.Timeframe {
+	case "", "now":
+		if inTrial {
+			break
+		}
+
+		if !sameInterval(current, plan) {
+			renewal = addPlanInterval(at, plan)
+			for _, l := range lines {
+				a, err := prorate(l, s.Currency, start, end, at, true)
+				if err != nil {
+					return Proration{}, err
+				}
+				p.Now = append(p.Now, a)
+			}
+			for _, l := range changed {
+				p.Now = append(p.Now, l.adjustment(l.unit, l.unit*l.quantity, s.Currency, at, renewal))
+			}
+			break
+		}
+
+		for _, l := range lines {
+			if l.isChanged {
+				a, err := prorate(l, s.Currency, start, end, at, true)
+				if err != nil {
+					return Proration{}, err
+				}
+				p.Now = append(p.Now, a)
+			}
+		}
+		for _, l := range changed {
+			if l.isChanged {
+				a, err := prorate(l, s.Currency, start, end, at, false)
+				if err != nil {
+					return Proration{}, err
+				}
+				p.Now = append(p.Now, a)
+			}
+		}
+	case "renewal", "bill_date":
+	default:
+		return Proration{}, fmt.Errorf("recurly: unknown timeframe %q", u.Timeframe)
+	}
+
+	renewalEnd := addPlanInterval(renewal, plan)
+	for _, l := range changed {
+		p.Renewal = append(p.Renewal, l.adjustment(l.unit, l.unit*l.quantity, s.Currency, renewal, renewalEnd))
+	}
+
+	return p, nil
+}
+
+// NowInCents returns the total of the line items created with the change.
+// It is negative when the credits are larger than the charges.
+func (p Proration) NowInCents() int {
+	return totalInCents(p.Now)
+}
+
+// RenewalInCents returns the total of the line items for the first term
+// after the change.
+func (p Proration) RenewalInCents() int {
+	return totalInCents(p.Renewal)
+}
+
+// totalInCents adds up the totals of adjustments.
+func totalInCents(adjustments []Adjustment) int {
+	var total int
+	for _, a := range adjustments {
+		total += a.TotalInCents
+	}
+	return total
+}
+
+// currentLines returns the plan and add on lines of the subscription as it
+// is now, leaving out the add ons in usage.
+func currentLines(s Subscription, usage map[string]bool) ([]prorationLine, error) {
+	lines := []prorationLine{{
+		code:     s.Plan.Code,
+		origin:   "plan",
+		unit:     s.UnitAmountInCents,
+		quantity: defaultQuantity(s.Quantity),
+		isPlan:   true,
+	}}
+	for _, a := range s.SubscriptionAddOns {
+		if a.Code == "" {
+			return nil, errors.New("recurly: subscription add on has no code")
+		} else if usage[a.Code] {
+			continue
+		}
+		lines = append(lines, prorationLine{
+			code:     a.Code,
+			origin:   "add_on",
+			unit:     a.UnitAmountInCents,
+			quantity: defaultQuantity(a.Quantity),
+		})
+	}
+	return lines, nil
+}
+
+// changedLines returns the plan and add on lines of the subscription after
+// u, leaving out the add ons in usage. Lines are marked as changed in both
+// current and the result when their code, unit amount or quantity differ.
+func changedLines(s Subscription, plan Plan, addOns []AddOn, u UpdateSubscription, current []prorationLine, usage map[string]bool) ([]prorationLine, error) {
+	code := s.Plan.Code
+	if u.PlanCode != "" {
+		code = u.PlanCode
+	}
+	if plan.Code != code {
+		return nil, fmt.Errorf("recurly: expected pricing for plan %q, given %q", code, plan.Code)
+	}
+
+	p := prorationLine{code: code, origin: "plan", unit: s.UnitAmountInCents, quantity: defaultQuantity(s.Quantity), isPlan: true}
+	if u.UnitAmountInCents > 0 {
+		p.unit = u.UnitAmountInCents
+	} else if code != s.Plan.Code {
+		unit, ok := plan.UnitAmountInCents.Get(s.Currency)
+		if !ok {
+			return nil, fmt.Errorf("recurly: plan %q has no %s price", code, s.Currency)
+		}
+		p.unit = unit
+	}
+	if u.Quantity > 0 {
+		p.quantity = u.Quantity
+	}
+	lines := []prorationLine{p}
+
+	if u.SubscriptionAddOns == nil {
+		lines = append(lines, current[1:]...)
+	} else {
+		for _, v := range *u.SubscriptionAddOns {
+			if usage[v.Code] {
+				continue
+			}
+			l := prorationLine{code: v.Code, origin: "add_on", unit: v.UnitAmountInCents, quantity: defaultQuantity(v.Quantity)}
+			if l.unit == 0 {
+				unit, err := addOnPrice(v.Code, addOns, s.Currency)
+				if err != nil {
+					return nil, err
+				}
+				l.unit = unit
+			}
+			lines = append(lines, l)
+		}
+	}
+
+	for i := range current {
+		current[i].isChanged = !hasLine(lines, current[i])
+	}
+	for i := range lines {
+		lines[i].isChanged = !hasLine(current, lines[i])
+	}
+	return lines, nil
+}
+
+// addOnPrice returns the price of the add on with the given code.
+func addOnPrice(code string, addOns []AddOn, currency string) (int, error) {
+	for _, a := range addOns {
+		if a.Code == code {
+			unit, ok := a.UnitAmountInCents.Get(currency)
+			if !ok {
+				return 0, fmt.Errorf("recurly: add on %q has no %s price", code, currency)
+			}
+			return unit, nil
+		}
+	}
+	return 0, fmt.Errorf("recurly: no pricing for add on %q", code)
+}
+
+// hasLine returns true if lines has a line with the same code, price and
+// quantity as l.
+func hasLine(lines []prorationLine, l prorationLine) bool {
+	for _, v := range lines {
+		if v.isPlan == l.isPlan && v.code == l.code && v.unit == l.unit && v.quantity == l.quantity {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultQuantity returns n, or the quantity of 1 Recurly uses when none is
+// given.
+func defaultQuantity(n int) int {
+	if n <= 0 {
+		return 1
+	}
+	return n
+}
+
+// prorate returns the charge, or the credit, for the part of the period
+// from at to end. The line's total is prorated, rather than its unit amount,
+// so rounding is off by at most a cent whatever the quantity. The unit amount
+// is the prorated total divided by the quantity.
+func prorate(l prorationLine, currency string, start, end, at time.Time, credit bool) (Adjustment, error) {
+	left, used := int(end.Sub(at)/time.Second), int(at.Sub(start)/time.Second)
+
+	total, err := NewMoney(l.unit, currency).Mul(l.quantity)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	parts, err := total.Allocate(left, used)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	amount := parts[0].Amount
+	unit := (2*amount + l.quantity) / (2 * l.quantity)
+	if credit {
+		amount, unit = -amount, -unit
+	}
+	a := l.adjustment(unit, amount, currency, at, end)
+	if credit {
+		a.Origin = "credit"
+	}
+	return a, nil
+}
+
+// adjustment returns the line as an adjustment for the period from start
+// to end.
+func (l prorationLine) adjustment(unit int, total int, currency string, start, end time.Time) Adjustment {
+	return Adjustment{
+		Description:       l.code,
+		ProductCode:       l.code,
+		Origin:            l.origin,
+		UnitAmountInCents: unit,
+		Quantity:          l.quantity,
+		TotalInCents:      total,
+		Currency:          currency,
+		StartDate:         NewTime(start),
+		EndDate:           NewTime(end),
+	}
+}
+
+// sameInterval returns true if plans a and b are billed over the same
+// interval.
+func sameInterval(a Plan, b Plan) bool {
+	unitA, lengthA := planInterval(a)
+	unitB, lengthB := planInterval(b)
+	return unitA == unitB && lengthA == lengthB
+}
+
+// planInterval returns the unit and length of a plan's billing interval.
+// Plans without an interval are billed monthly.
+func planInterval(p Plan) (string, int) {
+	unit, length := p.IntervalUnit, p.IntervalLength
+	if unit == "" {
+		unit = PlanIntervalUnitMonths
+	}
+	if length <= 0 {
+		length = 1
+	}
+	return unit, length
+}
+
+// addPlanInterval returns t moved forward by one billing cycle of the plan.
+// Like Recurly, a day past the end of the month is moved back to the month's
+// last day, so a term starting January 31st ends on the last day of February.
+func addPlanInterval(t time.Time, plan Plan) time.Time {
+	unit, length := planInterval(plan)
+	if unit == PlanIntervalUnitDays {
+		return t.AddDate(0, 0, length)
+	}
+
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(length), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
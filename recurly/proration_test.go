@@ -0,0 +1,280 @@
+package recurly
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProrate(t *testing.T) {
+	start := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)
+	renewal := time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2017, time.April, 16, 0, 0, 0, 0, time.UTC)
+
+	sub := Subscription{
+		Plan:                   nestedPlan{Code: "gold", Name: "Gold"},
+		State:                  SubscriptionStateActive,
+		UnitAmountInCents:      1000,
+		Currency:               "USD",
+		Quantity:               1,
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(end),
+		SubscriptionAddOns: []SubscriptionAddOn{
+			{Code: "ip", UnitAmountInCents: 200, Quantity: 2},
+		},
+	}
+	gold := Plan{Code: "gold", IntervalUnit: "months", IntervalLength: 1, UnitAmountInCents: UnitAmount{USD: 1000}}
+	platinum := Plan{Code: "platinum", IntervalUnit: "months", IntervalLength: 1, UnitAmountInCents: UnitAmount{USD: 3000}}
+	addOns := []AddOn{
+		{Code: "ip", UnitAmountInCents: UnitAmount{USD: 200}},
+		{Code: "support", UnitAmountInCents: UnitAmount{USD: 500}},
+	}
+
+	line := func(code string, origin string, unit int, quantity int, from time.Time, to time.Time) Adjustment {
+		return Adjustment{
+			Description:       code,
+			ProductCode:       code,
+			Origin:            origin,
+			UnitAmountInCents: unit,
+			Quantity:          quantity,
+			TotalInCents:      unit * quantity,
+			Currency:          "USD",
+			StartDate:         NewTime(from),
+			EndDate:           NewTime(to),
+		}
+	}
+
+	// Upgrading half way through the term credits half of the old plan and
+	// charges half of the new one. The add ons don't change.
+	p, err := Prorate(sub, gold, platinum, addOns, UpdateSubscription{PlanCode: "platinum"}, at)
+	if err != nil {
+		t.Fatalf("TestProrate Error: %s", err)
+	}
+
+	expected := Proration{
+		Now: []Adjustment{
+			line("gold", "credit", -500, 1, at, end),
+			line("platinum", "plan", 1500, 1, at, end),
+		},
+		Renewal: []Adjustment{
+			line("platinum", "plan", 3000, 1, end, renewal),
+			line("ip", "add_on", 200, 2, end, renewal),
+		},
+	}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestProrate Error: Expected %#v, given %#v", expected, p)
+	} else if p.NowInCents() != 1000 || p.RenewalInCents() != 3400 {
+		t.Errorf("TestProrate Error: Expected totals of 1000 and 3400, given %d and %d", p.NowInCents(), p.RenewalInCents())
+	}
+
+	// Changing add ons leaves the plan out.
+	p, _ = Prorate(sub, gold, gold, addOns, UpdateSubscription{SubscriptionAddOns: &[]SubscriptionAddOn{
+		{Code: "ip", Quantity: 3},
+		{Code: "support", UnitAmountInCents: 400},
+	}}, at)
+	expected.Now = []Adjustment{
+		line("ip", "credit", -100, 2, at, end),
+		line("ip", "add_on", 100, 3, at, end),
+		line("support", "add_on", 200, 1, at, end),
+	}
+	expected.Renewal = []Adjustment{
+		line("gold", "plan", 1000, 1, end, renewal),
+		line("ip", "add_on", 200, 3, end, renewal),
+		line("support", "add_on", 400, 1, end, renewal),
+	}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestProrate Error: Expected %#v, given %#v", expected, p)
+	}
+
+	// Changes at renewal and changes in a trial are not prorated.
+	p, _ = Prorate(sub, gold, gold, addOns, UpdateSubscription{Timeframe: "renewal", Quantity: 2}, at)
+	expected.Now = nil
+	expected.Renewal = []Adjustment{
+		line("gold", "plan", 1000, 2, end, renewal),
+		line("ip", "add_on", 200, 2, end, renewal),
+	}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestProrate Error: Expected %#v, given %#v", expected, p)
+	}
+
+	// Usage add ons are billed for usage, not prorated.
+	metered := sub
+	metered.SubscriptionAddOns = append(metered.SubscriptionAddOns, SubscriptionAddOn{Code: "calls", UnitAmountInCents: 1})
+	meteredAddOns := append(addOns, AddOn{Code: "calls", AddOnType: AddOnTypeUsage, UsageType: UsageTypePrice, UnitAmountInCents: UnitAmount{USD: 1}})
+	p, _ = Prorate(metered, gold, gold, meteredAddOns, UpdateSubscription{Timeframe: "renewal", Quantity: 2}, at)
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestProrate Error: Expected %#v, given %#v", expected, p)
+	}
+
+	trial := sub
+	trial.TrialEndsAt = NewTime(end)
+	if p, _ = Prorate(trial, gold, gold, addOns, UpdateSubscription{Quantity: 2}, at); p.Now != nil {
+		t.Errorf("TestProrate Error: Expected no proration in a trial, given %#v", p.Now)
+	}
+
+	// Amounts that don't divide evenly are rounded to the nearest cent.
+	odd := sub
+	odd.UnitAmountInCents = 1001
+	p, _ = Prorate(odd, gold, gold, addOns, UpdateSubscription{UnitAmountInCents: 2000}, at)
+	if p.Now[0].UnitAmountInCents != -501 || p.Now[1].UnitAmountInCents != 1000 {
+		t.Errorf("TestProrate Error: Expected -501 and 1000, given %#v", p.Now)
+	}
+}
+
+func TestProrateIntervalChange(t *testing.T) {
+	start := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2017, time.April, 16, 0, 0, 0, 0, time.UTC)
+	termEnd := time.Date(2018, time.April, 16, 0, 0, 0, 0, time.UTC)
+	renewalEnd := time.Date(2019, time.April, 16, 0, 0, 0, 0, time.UTC)
+
+	sub := Subscription{
+		Plan:                   nestedPlan{Code: "gold"},
+		State:                  SubscriptionStateActive,
+		UnitAmountInCents:      1000,
+		Currency:               "USD",
+		Quantity:               1,
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(end),
+		SubscriptionAddOns: []SubscriptionAddOn{
+			{Code: "ip", UnitAmountInCents: 200, Quantity: 2},
+		},
+	}
+	gold := Plan{Code: "gold", UnitAmountInCents: UnitAmount{USD: 1000}}
+	annual := Plan{Code: "annual", IntervalUnit: PlanIntervalUnitMonths, IntervalLength: 12, UnitAmountInCents: UnitAmount{USD: 10000}}
+
+	line := func(code string, origin string, unit int, quantity int, from time.Time, to time.Time) Adjustment {
+		return Adjustment{
+			Description:       code,
+			ProductCode:       code,
+			Origin:            origin,
+			UnitAmountInCents: unit,
+			Quantity:          quantity,
+			TotalInCents:      unit * quantity,
+			Currency:          "USD",
+			StartDate:         NewTime(from),
+			EndDate:           NewTime(to),
+		}
+	}
+
+	// A yearly plan starts a new term at the change: the rest of the month
+	// is credited, including the unchanged add on, and the year is charged
+	// in full.
+	p, err := Prorate(sub, gold, annual, nil, UpdateSubscription{PlanCode: "annual"}, at)
+	if err != nil {
+		t.Fatalf("TestProrateIntervalChange Error: %s", err)
+	}
+
+	expected := Proration{
+		Now: []Adjustment{
+			line("gold", "credit", -500, 1, at, end),
+			line("ip", "credit", -100, 2, at, end),
+			line("annual", "plan", 10000, 1, at, termEnd),
+			line("ip", "add_on", 200, 2, at, termEnd),
+		},
+		Renewal: []Adjustment{
+			line("annual", "plan", 10000, 1, termEnd, renewalEnd),
+			line("ip", "add_on", 200, 2, termEnd, renewalEnd),
+		},
+	}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestProrateIntervalChange Error: Expected %#v, given %#v", expected, p)
+	}
+
+	// A plan with the same interval keeps the current term.
+	monthly := Plan{Code: "platinum", IntervalUnit: PlanIntervalUnitMonths, IntervalLength: 1, UnitAmountInCents: UnitAmount{USD: 3000}}
+	if p, _ = Prorate(sub, gold, monthly, nil, UpdateSubscription{PlanCode: "platinum"}, at); len(p.Now) != 2 || !p.Renewal[0].StartDate.Equal(end) {
+		t.Errorf("TestProrateIntervalChange Error: Expected the current term to be kept, given %#v", p)
+	}
+
+	// The interval comes from the plans, not the current period, so a term
+	// made longer by postponing the renewal is still prorated.
+	postponed := sub
+	postponed.CurrentPeriodEndsAt = NewTime(time.Date(2017, time.May, 16, 0, 0, 0, 0, time.UTC))
+	p, _ = Prorate(postponed, gold, monthly, nil, UpdateSubscription{PlanCode: "platinum"}, at)
+	expected.Now = []Adjustment{
+		line("gold", "credit", -667, 1, at, *postponed.CurrentPeriodEndsAt.Time),
+		line("platinum", "plan", 2000, 1, at, *postponed.CurrentPeriodEndsAt.Time),
+	}
+	if !reflect.DeepEqual(expected.Now, p.Now) {
+		t.Errorf("TestProrateIntervalChange Error: Expected %#v, given %#v", expected.Now, p.Now)
+	}
+}
+
+func TestProrateRounding(t *testing.T) {
+	start := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{
+		Plan:                   nestedPlan{Code: "gold"},
+		State:                  SubscriptionStateActive,
+		UnitAmountInCents:      100,
+		Currency:               "USD",
+		Quantity:               30,
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(end),
+	}
+	gold := Plan{Code: "gold", UnitAmountInCents: UnitAmount{USD: 100}}
+
+	// A third of the term is left. Prorating each seat would give 33 cents,
+	// or 990 for 30 seats, instead of 1000.
+	at := time.Date(2017, time.April, 21, 0, 0, 0, 0, time.UTC)
+	p, err := Prorate(sub, gold, gold, nil, UpdateSubscription{Quantity: 60}, at)
+	if err != nil {
+		t.Fatalf("TestProrateRounding Error: %s", err)
+	}
+
+	if p.Now[0].TotalInCents != -1000 || p.Now[1].TotalInCents != 2000 {
+		t.Errorf("TestProrateRounding Error: Expected totals of -1000 and 2000, given %d and %d", p.Now[0].TotalInCents, p.Now[1].TotalInCents)
+	} else if p.Now[0].UnitAmountInCents != -33 || p.Now[1].UnitAmountInCents != 33 {
+		t.Errorf("TestProrateRounding Error: Expected unit amounts of -33 and 33, given %d and %d", p.Now[0].UnitAmountInCents, p.Now[1].UnitAmountInCents)
+	}
+}
+
+func TestAddPlanInterval(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"plan": Plan{}, "from": time.Date(2017, time.January, 15, 0, 0, 0, 0, time.UTC), "to": time.Date(2017, time.February, 15, 0, 0, 0, 0, time.UTC)},
+		map[string]interface{}{"plan": Plan{IntervalUnit: PlanIntervalUnitMonths, IntervalLength: 1}, "from": time.Date(2017, time.January, 31, 0, 0, 0, 0, time.UTC), "to": time.Date(2017, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		map[string]interface{}{"plan": Plan{IntervalUnit: PlanIntervalUnitMonths, IntervalLength: 12}, "from": time.Date(2016, time.February, 29, 0, 0, 0, 0, time.UTC), "to": time.Date(2017, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		map[string]interface{}{"plan": Plan{IntervalUnit: PlanIntervalUnitDays, IntervalLength: 30}, "from": time.Date(2017, time.January, 31, 0, 0, 0, 0, time.UTC), "to": time.Date(2017, time.March, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for i, s := range suite {
+		if given := addPlanInterval(s["from"].(time.Time), s["plan"].(Plan)); !given.Equal(s["to"].(time.Time)) {
+			t.Errorf("TestAddPlanInterval Error (%d): Expected %s, given %s", i, s["to"], given)
+		}
+	}
+}
+
+func TestProrateErrors(t *testing.T) {
+	start := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{
+		Plan:                   nestedPlan{Code: "gold"},
+		State:                  SubscriptionStateActive,
+		UnitAmountInCents:      1000,
+		Currency:               "USD",
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(end),
+	}
+	expired := sub
+	expired.State = SubscriptionStateExpired
+	gold := Plan{Code: "gold", UnitAmountInCents: UnitAmount{USD: 1000}}
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"current": Plan{Code: "platinum"}, "sub": sub, "plan": gold, "update": UpdateSubscription{}, "at": start},
+		map[string]interface{}{"current": gold, "sub": sub, "plan": Plan{Code: "platinum"}, "update": UpdateSubscription{}, "at": start},
+		map[string]interface{}{"current": gold, "sub": sub, "plan": Plan{Code: "platinum"}, "update": UpdateSubscription{PlanCode: "platinum"}, "at": start},
+		map[string]interface{}{"current": gold, "sub": sub, "plan": gold, "update": UpdateSubscription{Timeframe: "later"}, "at": start},
+		map[string]interface{}{"current": gold, "sub": sub, "plan": gold, "update": UpdateSubscription{}, "at": end.Add(time.Second)},
+		map[string]interface{}{"current": gold, "sub": sub, "plan": gold, "update": UpdateSubscription{SubscriptionAddOns: &[]SubscriptionAddOn{{Code: "ip"}}}, "at": start},
+		map[string]interface{}{"current": gold, "sub": expired, "plan": gold, "update": UpdateSubscription{}, "at": start},
+		map[string]interface{}{"current": gold, "sub": Subscription{Plan: nestedPlan{Code: "gold"}}, "plan": gold, "update": UpdateSubscription{}, "at": start},
+	}
+
+	for i, s := range suite {
+		if _, err := Prorate(s["sub"].(Subscription), s["current"].(Plan), s["plan"].(Plan), nil, s["update"].(UpdateSubscription), s["at"].(time.Time)); err == nil {
+			t.Errorf("TestProrateErrors Error (%d): Expected error", i)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package recurly
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitWindow is the length of Recurly's rate limit window.
+const defaultRateLimitWindow = 5 * time.Minute
+
+type (
+	// RateLimiter keeps a client under Recurly's rate limit. It is a token
+	// bucket that allows bursts of up to the limit and refills evenly over
+	// the window. Each response's X-RateLimit headers adjust the bucket, so
+	// requests made with the same API key from elsewhere are accounted for,
+	// and requests block until the window resets once the limit is reached.
+	//
+	// A RateLimiter is safe for concurrent use, and can be shared by clients
+	// that use the same API key.
+	RateLimiter struct {
+		// Reserve is the number of requests to leave unused in each window,
+		// e.g. for a web app that shares the API key with a batch job.
+		Reserve int
+
+		window time.Duration
+
+		mu     sync.Mutex
+		limit  int
+		tokens float64
+		last   time.Time
+		until  time.Time
+	}
+)
+
+// NewRateLimiter creates a RateLimiter that allows limit requests per
+// window. If window is zero, Recurly's five minute window is used. The limit
+// is replaced by the X-RateLimit-Limit header once a response is received.
+// A limit of zero or less means the limit is unknown: requests aren't
+// throttled until a response reports it. The zero RateLimiter behaves the
+// same way.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	return &RateLimiter{
+		window: window,
+		limit:  limit,
+		tokens: float64(limit),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be made or ctx is done, in which case the
+// context's error is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.refill(now)
+
+		var wait time.Duration
+		switch {
+		case now.Before(l.until):
+			wait = l.until.Sub(now)
+		case l.limit <= 0:
+			l.mu.Unlock()
+			return nil
+		case l.tokens >= 1:
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		default:
+			wait = time.Duration((1 - l.tokens) / l.rate() * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe adjusts the limiter from the X-RateLimit headers of resp. It is
+// called by the client for every response.
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	r := Response{Response: resp}
+	limit, remaining, reset := r.RateLimit(), r.RateLimitRemaining(), r.RateLimitReset()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	if limit > 0 {
+		// The bucket starts full once the limit is first known.
+		unknown := l.limit <= 0
+		l.limit = limit
+		if unknown {
+			l.tokens = l.capacity()
+		}
+	}
+
+	if remaining >= 0 {
+		// Other users of the API key may have used part of the window, so
+		// never allow more than Recurly says is left.
+		if avail := float64(remaining - l.Reserve); l.tokens > avail {
+			l.tokens = avail
+		}
+		if remaining <= l.Reserve && reset.After(now) {
+			l.until = reset
+			l.tokens = 0
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests && reset.After(l.until) {
+		l.until = reset
+		l.tokens = 0
+	}
+}
+
+// capacity returns the number of requests the bucket holds when full.
+func (l *RateLimiter) capacity() float64 {
+	c := l.limit - l.Reserve
+	if c < 1 {
+		c = 1
+	}
+	return float64(c)
+}
+
+// rate returns the number of requests added to the bucket each second.
+func (l *RateLimiter) rate() float64 {
+	window := l.window
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	return l.capacity() / window.Seconds()
+}
+
+// refill adds the requests accrued since the last refill.
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.until.IsZero() {
+		if now.Before(l.until) {
+			l.last = now
+			return
+		}
+
+		// The window has reset.
+		l.tokens = l.capacity()
+		l.until = time.Time{}
+	}
+
+	if l.limit <= 0 {
+		l.last = now
+		return
+	}
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate()
+	}
+	if c := l.capacity(); l.tokens > c {
+		l.tokens = c
+	}
+	l.last = now
+}
@@ -0,0 +1,167 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	r := Response{Response: &http.Response{Header: http.Header{}}}
+	if r.RateLimit() != -1 || r.RateLimitRemaining() != -1 || !r.RateLimitReset().IsZero() {
+		t.Errorf("TestResponseRateLimit Error: Expected missing headers, given %d, %d, %s", r.RateLimit(), r.RateLimitRemaining(), r.RateLimitReset())
+	}
+
+	r.Header.Set("X-RateLimit-Limit", "2000")
+	r.Header.Set("X-RateLimit-Remaining", "1990")
+	r.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if r.RateLimit() != 2000 || r.RateLimitRemaining() != 1990 || !r.RateLimitReset().Equal(reset) {
+		t.Errorf("TestResponseRateLimit Error: Expected 2000, 1990, %s, given %d, %d, %s", reset, r.RateLimit(), r.RateLimitRemaining(), r.RateLimitReset())
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(2, 200*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("TestRateLimiterWait Error: Unexpected error: %s", err)
+		}
+	}
+
+	// The bucket starts full, then refills one request every 100ms.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("TestRateLimiterWait Error: Expected third request to wait, given %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("TestRateLimiterWait Error: Expected context.DeadlineExceeded, given %v", err)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(5, 250*time.Millisecond)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("TestRateLimiterConcurrent Error: Unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Five requests are allowed at once, and the other five take a window
+	// to refill.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("TestRateLimiterConcurrent Error: Expected requests to be spread over the window, given %s", elapsed)
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.Header().Set("X-RateLimit-Limit", "1000")
+		rw.Header().Set("X-RateLimit-Remaining", "10")
+		rw.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	client.RateLimiter = NewRateLimiter(1000, time.Hour)
+	client.RateLimiter.Reserve = 10
+
+	resp, _, err := client.Accounts.Get("1")
+	if err != nil {
+		t.Fatalf("TestRateLimiterHeaders Error: Error occurred making API call. Err: %s", err)
+	} else if resp.RateLimitRemaining() != 10 {
+		t.Errorf("TestRateLimiterHeaders Error: Expected 10 remaining requests, given %d", resp.RateLimitRemaining())
+	}
+
+	// Only the reserve is left, so the next request waits for the reset.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Accounts.GetContext(ctx, "1"); err != context.DeadlineExceeded {
+		t.Errorf("TestRateLimiterHeaders Error: Expected context.DeadlineExceeded, given %v", err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("TestRateLimiterHeaders Error: Expected 1 request to be sent, given %d", n)
+	}
+}
+
+func TestRateLimiterReset(t *testing.T) {
+	l := NewRateLimiter(100, time.Hour)
+	l.Observe(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Reset":     []string{strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10)},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("TestRateLimiterReset Error: Expected requests to resume after the reset, given %v", err)
+		}
+	}
+
+	// The first request waits for the reset and the rest use the refilled
+	// window.
+	if elapsed := time.Since(start); elapsed > 2100*time.Millisecond {
+		t.Errorf("TestRateLimiterReset Error: Expected window to refill at the reset, given %s", elapsed)
+	}
+}
+
+func TestRateLimiterUnknownLimit(t *testing.T) {
+	for _, l := range []*RateLimiter{&RateLimiter{}, NewRateLimiter(0, 0), NewRateLimiter(-1, time.Hour)} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		for i := 0; i < 100; i++ {
+			if err := l.Wait(ctx); err != nil {
+				t.Fatalf("TestRateLimiterUnknownLimit Error: Expected no waiting without a limit, given %v", err)
+			}
+		}
+		cancel()
+	}
+
+	// The limit and remaining requests reported by a response take over.
+	l := &RateLimiter{}
+	l.Observe(&http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     []string{"1000"},
+			"X-Ratelimit-Remaining": []string{"1"},
+			"X-Ratelimit-Reset":     []string{strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+		},
+	})
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("TestRateLimiterUnknownLimit Error: Expected the remaining request to be allowed, given %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("TestRateLimiterUnknownLimit Error: Expected context.DeadlineExceeded once the limit is known, given %v", err)
+	}
+}
@@ -0,0 +1,421 @@
+package recurlytest
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// account returns the account with the given code, or nil.
+func (s *Server) account(code string) *recurly.Account {
+	for _, a := range s.accounts {
+		if a.Code == code {
+			return a
+		}
+	}
+	return nil
+}
+
+// findAccount returns the account named in the request path. It writes a 404
+// response and returns nil if the account does not exist.
+func (s *Server) findAccount(rw http.ResponseWriter, r *http.Request) *recurly.Account {
+	code := r.PathValue("code")
+	a := s.account(code)
+	if a == nil {
+		notFound(rw, "Account", "account_code", code)
+	}
+	return a
+}
+
+// addAccount validates and stores a new account, along with any billing
+// info given with it. It writes a 422 response and returns nil if the
+// account is invalid.
+func (s *Server) addAccount(rw http.ResponseWriter, a recurly.Account) *recurly.Account {
+	if a.Code == "" {
+		invalid(rw, "account.account_code", "blank", "can't be blank")
+		return nil
+	} else if s.account(a.Code) != nil {
+		invalid(rw, "account.account_code", "taken", "has already been taken")
+		return nil
+	}
+
+	if !s.setParent(rw, &a, a.ParentAccountCode) {
+		return nil
+	}
+
+	if a.BillingInfo != nil {
+		s.billing[a.Code] = s.newBilling(*a.BillingInfo)
+	}
+
+	a.XMLName.Local = "account"
+	a.State = "active"
+	a.BillingInfo = nil
+	a.HostedLoginToken = s.newID()
+	a.CreatedAt = s.now()
+	a.UpdatedAt = a.CreatedAt
+	s.accounts = append(s.accounts, &a)
+
+	return &a
+}
+
+// newBilling returns billing info as Recurly stores it. Card numbers are
+// reduced to their first six and last four digits, bank account numbers and
+// IBANs to their last four, and tokens are exchanged for a test Visa card.
+func (s *Server) newBilling(b recurly.Billing) *recurly.Billing {
+	b.XMLName.Local = "billing_info"
+	if b.Token != "" {
+		b.Number = "4111111111111111"
+		b.Month = 12
+		b.Year = s.Now().Year() + 1
+	}
+
+	if b.Number != "" {
+		if len(b.Number) >= 10 {
+			b.FirstSix, b.LastFour = b.Number[:6], b.Number[len(b.Number)-4:]
+		}
+
+		switch b.Number[0] {
+		case '3':
+			b.CardType = "American Express"
+		case '4':
+			b.CardType = "Visa"
+		case '5':
+			b.CardType = "MasterCard"
+		case '6':
+			b.CardType = "Discover"
+		default:
+			b.CardType = "Unknown"
+		}
+	}
+
+	for _, number := range []string{b.AccountNumber, b.IBAN} {
+		if len(number) >= 4 {
+			b.LastFour = number[len(number)-4:]
+		}
+	}
+
+	b.Number = ""
+	b.VerificationValue = ""
+	b.AccountNumber = ""
+	b.IBAN = ""
+	b.Token = ""
+
+	return &b
+}
+
+// setParent makes a a child of the account with the given code. It writes a
+// 422 response and returns false if there is no such account, or if the
+// account would become its own ancestor.
+func (s *Server) setParent(rw http.ResponseWriter, a *recurly.Account, code string) bool {
+	a.ParentAccountCode = ""
+	if code == "" {
+		return true
+	}
+
+	for p := s.account(code); p != nil; p = s.account(p.ParentAccount.Code) {
+		if p.Code == a.Code {
+			invalid(rw, "account.parent_account_code", "invalid", "can't be a child of itself")
+			return false
+		}
+		if p.ParentAccount.Code == "" {
+			a.ParentAccount.Code = code
+			return true
+		}
+	}
+
+	invalid(rw, "account.parent_account_code", "invalid", "is invalid")
+	return false
+}
+
+func (s *Server) listAccounts(rw http.ResponseWriter, r *http.Request) {
+	var accounts []accountXML
+	state := r.URL.Query().Get("state")
+	for _, a := range s.accounts {
+		if state == "" || a.State == state {
+			accounts = append(accounts, s.accountXML(a))
+		}
+	}
+
+	start, end := paginate(rw, r, len(accounts))
+	writeXML(rw, http.StatusOK, accountsXML{Accounts: accounts[start:end]})
+}
+
+func (s *Server) listChildAccounts(rw http.ResponseWriter, r *http.Request) {
+	parent := s.findAccount(rw, r)
+	if parent == nil {
+		return
+	}
+
+	var accounts []accountXML
+	for _, a := range s.accounts {
+		if a.ParentAccount.Code == parent.Code {
+			accounts = append(accounts, s.accountXML(a))
+		}
+	}
+
+	start, end := paginate(rw, r, len(accounts))
+	writeXML(rw, http.StatusOK, accountsXML{Accounts: accounts[start:end]})
+}
+
+func (s *Server) createAccount(rw http.ResponseWriter, r *http.Request) {
+	var a recurly.Account
+	if !decode(rw, r, &a) {
+		return
+	}
+
+	if created := s.addAccount(rw, a); created != nil {
+		writeXML(rw, http.StatusCreated, s.accountXML(created))
+	}
+}
+
+func (s *Server) getAccount(rw http.ResponseWriter, r *http.Request) {
+	if a := s.findAccount(rw, r); a != nil {
+		writeXML(rw, http.StatusOK, s.accountXML(a))
+	}
+}
+
+func (s *Server) updateAccount(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	var update recurly.Account
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	if update.ParentAccountCode != "" && !s.setParent(rw, a, update.ParentAccountCode) {
+		return
+	}
+
+	if update.BillingInfo != nil {
+		s.billing[a.Code] = s.newBilling(*update.BillingInfo)
+	}
+
+	// The account code, state and read only fields can't be updated.
+	update.Code, update.State, update.BillingInfo = "", "", nil
+	update.HostedLoginToken, update.CreatedAt = "", recurly.NullTime{}
+	update.ParentAccountCode, update.ClosedAt = "", recurly.NullTime{}
+	merge(a, update)
+	a.UpdatedAt = s.now()
+
+	writeXML(rw, http.StatusOK, s.accountXML(a))
+}
+
+func (s *Server) closeAccount(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	// Closing an account removes its billing info and cancels its active
+	// subscriptions.
+	a.State = "closed"
+	a.ClosedAt = s.now()
+	delete(s.billing, a.Code)
+	for _, sub := range s.subscriptions {
+		if sub.Account.Code == a.Code && sub.State == recurly.SubscriptionStateActive {
+			s.cancel(sub)
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) reopenAccount(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	a.State = "active"
+	a.ClosedAt = recurly.NullTime{}
+	writeXML(rw, http.StatusOK, s.accountXML(a))
+}
+
+// listNotes returns the notes added to an account with AddNote, newest
+// first.
+func (s *Server) listNotes(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	notes := notesXML{}
+	for i := len(s.notes[a.Code]) - 1; i >= 0; i-- {
+		n := s.notes[a.Code][i]
+		n.Account = s.link("accounts/%s", a.Code)
+		notes.Notes = append(notes.Notes, n)
+	}
+
+	writeXML(rw, http.StatusOK, notes)
+}
+
+// getBalance sums the account's open and past due invoices and its pending
+// adjustments in each currency.
+func (s *Server) getBalance(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	b := recurly.AccountBalance{XMLName: xml.Name{Local: "account_balance"}, PastDue: recurly.NewBool(false)}
+	add := func(currency string, amount int) {
+		v, _ := b.Balance.Get(currency)
+		b.Balance.Set(currency, v+amount)
+	}
+
+	for _, inv := range s.invoices {
+		if inv.Account.Code != a.Code {
+			continue
+		}
+		switch inv.State {
+		case recurly.InvoiceStatePastDue:
+			b.PastDue = recurly.NewBool(true)
+			add(inv.Currency, inv.TotalInCents)
+		case recurly.InvoiceStateOpen:
+			add(inv.Currency, inv.TotalInCents)
+		}
+	}
+	for _, adj := range s.adjustments {
+		if adj.Account.Code == a.Code && adj.State == "pending" {
+			add(adj.Currency, adj.TotalInCents)
+		}
+	}
+
+	writeXML(rw, http.StatusOK, b)
+}
+
+func (s *Server) getAcquisition(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	acq, ok := s.acquisitions[a.Code]
+	if !ok {
+		notFound(rw, "AccountAcquisition", "account_code", a.Code)
+		return
+	}
+
+	writeXML(rw, http.StatusOK, acq)
+}
+
+// saveAcquisition creates or updates the account's acquisition data. Like
+// Recurly, updates only change the fields that are given.
+func (s *Server) saveAcquisition(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	var v recurly.AccountAcquisition
+	if !decode(rw, r, &v) {
+		return
+	}
+
+	acq, ok := s.acquisitions[a.Code]
+	if r.Method == http.MethodPost {
+		if ok {
+			invalid(rw, "account_acquisition.account", "taken", "already has acquisition data")
+			return
+		}
+		acq = &recurly.AccountAcquisition{CreatedAt: s.now()}
+	} else if !ok {
+		notFound(rw, "AccountAcquisition", "account_code", a.Code)
+		return
+	}
+
+	if v.CostInCents != 0 {
+		acq.CostInCents = v.CostInCents
+	}
+	if v.Currency != "" {
+		acq.Currency = v.Currency
+	}
+	if v.Channel != "" {
+		acq.Channel = v.Channel
+	}
+	if v.Subchannel != "" {
+		acq.Subchannel = v.Subchannel
+	}
+	if v.Campaign != "" {
+		acq.Campaign = v.Campaign
+	}
+	acq.XMLName.Local = "account_acquisition"
+	acq.UpdatedAt = s.now()
+
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusCreated
+	}
+
+	s.acquisitions[a.Code] = acq
+	writeXML(rw, status, acq)
+}
+
+func (s *Server) deleteAcquisition(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	if _, ok := s.acquisitions[a.Code]; !ok {
+		notFound(rw, "AccountAcquisition", "account_code", a.Code)
+		return
+	}
+
+	delete(s.acquisitions, a.Code)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getBilling(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	b, ok := s.billing[a.Code]
+	if !ok {
+		notFound(rw, "BillingInfo", "account_code", a.Code)
+		return
+	}
+
+	writeXML(rw, http.StatusOK, b)
+}
+
+func (s *Server) saveBilling(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	var b recurly.Billing
+	if !decode(rw, r, &b) {
+		return
+	}
+
+	if b.Type() == "" && b.Token == "" {
+		invalid(rw, "billing_info.number", "required", "is required")
+		return
+	}
+
+	status := http.StatusOK
+	if _, ok := s.billing[a.Code]; !ok {
+		status = http.StatusCreated
+	}
+
+	s.billing[a.Code] = s.newBilling(b)
+	writeXML(rw, status, s.billing[a.Code])
+}
+
+func (s *Server) clearBilling(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	delete(s.billing, a.Code)
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,221 @@
+package recurlytest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// coupon returns the coupon with the given code, or nil.
+func (s *Server) coupon(code string) *recurly.Coupon {
+	for _, c := range s.coupons {
+		if c.Code == code {
+			return c
+		}
+	}
+	return nil
+}
+
+// findCoupon returns the coupon named in the request path. It writes a 404
+// response and returns nil if the coupon does not exist.
+func (s *Server) findCoupon(rw http.ResponseWriter, r *http.Request) *recurly.Coupon {
+	code := r.PathValue("code")
+	c := s.coupon(code)
+	if c == nil {
+		notFound(rw, "Coupon", "coupon_code", code)
+	}
+	return c
+}
+
+// redemption returns the active redemption on an account, or nil.
+func (s *Server) redemption(accountCode string) *redemption {
+	for i := len(s.redemptions) - 1; i >= 0; i-- {
+		if r := s.redemptions[i]; r.Account.Code == accountCode && r.State == "active" {
+			return r
+		}
+	}
+	return nil
+}
+
+// discount applies a coupon to the charges. Percent discounts apply to each
+// charge, and fixed discounts are spread over the charges in order.
+func discount(c *recurly.Coupon, charges []*recurly.Adjustment) {
+	remaining := c.DiscountInCents
+	for _, a := range charges {
+		var d int
+		switch c.DiscountType {
+		case "percent":
+			d = a.TotalInCents * c.DiscountPercent / 100
+		case "dollars":
+			d = remaining
+			if d > a.TotalInCents {
+				d = a.TotalInCents
+			}
+			remaining -= d
+		}
+
+		a.DiscountInCents += d
+		a.TotalInCents -= d
+	}
+}
+
+// redeem redeems a coupon on an account. The coupon is maxed out once it
+// reaches its maximum number of redemptions.
+func (s *Server) redeem(c *recurly.Coupon, accountCode string, currency string) *redemption {
+	r := &redemption{}
+	r.XMLName.Local = "redemption"
+	r.Coupon.Code = c.Code
+	r.Account.Code = accountCode
+	r.SingleUse = c.SingleUse
+	r.Currency = currency
+	r.State = "active"
+	r.CreatedAt = s.now()
+	s.redemptions = append(s.redemptions, r)
+
+	if c.MaxRedemptions.Valid {
+		var n int
+		for _, v := range s.redemptions {
+			if v.Coupon.Code == c.Code {
+				n++
+			}
+		}
+
+		if n >= c.MaxRedemptions.Int {
+			c.State = "maxed_out"
+		}
+	}
+
+	return r
+}
+
+func (s *Server) listCoupons(rw http.ResponseWriter, r *http.Request) {
+	var coupons []recurly.Coupon
+	state := r.URL.Query().Get("state")
+	for _, c := range s.coupons {
+		if state == "" || c.State == state {
+			coupons = append(coupons, *c)
+		}
+	}
+
+	start, end := paginate(rw, r, len(coupons))
+	writeXML(rw, http.StatusOK, couponsXML{Coupons: coupons[start:end]})
+}
+
+func (s *Server) createCoupon(rw http.ResponseWriter, r *http.Request) {
+	var c recurly.Coupon
+	if !decode(rw, r, &c) {
+		return
+	}
+
+	switch {
+	case c.Code == "":
+		invalid(rw, "coupon.coupon_code", "blank", "can't be blank")
+		return
+	case c.Name == "":
+		invalid(rw, "coupon.name", "blank", "can't be blank")
+		return
+	case c.DiscountType != "percent" && c.DiscountType != "dollars" && c.DiscountType != "free_trial":
+		invalid(rw, "coupon.discount_type", "invalid", "is invalid")
+		return
+	case s.coupon(c.Code) != nil:
+		invalid(rw, "coupon.coupon_code", "taken", "has already been taken")
+		return
+	}
+
+	c.State = "redeemable"
+	c.CreatedAt = s.now()
+	s.coupons = append(s.coupons, &c)
+
+	writeXML(rw, http.StatusCreated, c)
+}
+
+func (s *Server) getCoupon(rw http.ResponseWriter, r *http.Request) {
+	if c := s.findCoupon(rw, r); c != nil {
+		writeXML(rw, http.StatusOK, c)
+	}
+}
+
+func (s *Server) deleteCoupon(rw http.ResponseWriter, r *http.Request) {
+	c := s.findCoupon(rw, r)
+	if c == nil {
+		return
+	}
+
+	c.State = "inactive"
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) redeemCoupon(rw http.ResponseWriter, r *http.Request) {
+	c := s.findCoupon(rw, r)
+	if c == nil {
+		return
+	}
+
+	var v struct {
+		XMLName     xml.Name `xml:"redemption"`
+		AccountCode string   `xml:"account_code"`
+		Currency    string   `xml:"currency"`
+	}
+	if !decode(rw, r, &v) {
+		return
+	}
+
+	if s.account(v.AccountCode) == nil {
+		invalid(rw, "redemption.account_code", "invalid", "is invalid")
+		return
+	} else if c.State != "redeemable" {
+		invalid(rw, "redemption.coupon", "invalid", "is not redeemable")
+		return
+	}
+
+	writeXML(rw, http.StatusCreated, s.redemptionXML(s.redeem(c, v.AccountCode, v.Currency)))
+}
+
+func (s *Server) getAccountRedemption(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	red := s.redemption(a.Code)
+	if red == nil {
+		notFound(rw, "Redemption", "account_code", a.Code)
+		return
+	}
+
+	writeXML(rw, http.StatusOK, s.redemptionXML(red))
+}
+
+func (s *Server) deleteRedemption(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	red := s.redemption(a.Code)
+	if red == nil {
+		notFound(rw, "Redemption", "account_code", a.Code)
+		return
+	}
+
+	red.State = "inactive"
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getInvoiceRedemption(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	for _, red := range s.redemptions {
+		if red.invoiceNumber == inv.InvoiceNumber {
+			writeXML(rw, http.StatusOK, s.redemptionXML(red))
+			return
+		}
+	}
+
+	notFound(rw, "Redemption", "invoice_number", strconv.Itoa(inv.InvoiceNumber))
+}
@@ -0,0 +1,73 @@
+package recurlytest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+func TestCouponDiscounts(t *testing.T) {
+	_, client := newServer(t)
+
+	_, c, err := client.Coupons.Create(recurly.Coupon{Code: "save20", Name: "Save 20%", DiscountType: "percent", DiscountPercent: 20, MaxRedemptions: recurly.NewInt(1)})
+	if err != nil || c.State != "redeemable" {
+		t.Fatalf("TestCouponDiscounts Error: Expected redeemable coupon, given %#v (%v)", c, err)
+	}
+
+	ns := signup("1")
+	ns.CouponCode = "save20"
+	if _, _, err := client.Subscriptions.Create(ns); err != nil {
+		t.Fatalf("TestCouponDiscounts Error: Error creating subscription. Err: %s", err)
+	}
+
+	_, inv, _ := client.Invoices.Get(1000)
+	if inv.TotalInCents != 800 || inv.LineItems[0].DiscountInCents != 200 {
+		t.Errorf("TestCouponDiscounts Error: Expected 200 cent discount on 800 cent invoice, given %#v", inv)
+	}
+
+	_, red, err := client.Redemptions.GetForAccount("1")
+	if err != nil || red.Coupon.Code != "save20" || red.State != "active" || red.TotalDiscountedInCents != 200 {
+		t.Errorf("TestCouponDiscounts Error: Expected active save20 redemption, given %#v (%v)", red, err)
+	}
+
+	if _, red, _ := client.Redemptions.GetForInvoice("1000"); red.Coupon.Code != "save20" {
+		t.Errorf("TestCouponDiscounts Error: Expected invoice redemption of save20, given %#v", red)
+	}
+
+	// The coupon could only be redeemed once.
+	_, c, _ = client.Coupons.Get("save20")
+	if c.State != "maxed_out" {
+		t.Errorf("TestCouponDiscounts Error: Expected maxed out coupon, given %s", c.State)
+	}
+
+	ns = signup("2")
+	ns.CouponCode = "save20"
+	if r, _, _ := client.Subscriptions.Create(ns); r.StatusCode != 422 || r.Errors[0].Field != "subscription.coupon_code" {
+		t.Errorf("TestCouponDiscounts Error: Expected maxed out coupon to be invalid, given %d %#v", r.StatusCode, r.Errors)
+	}
+}
+
+func TestRedemptions(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+	client.Coupons.Create(recurly.Coupon{Code: "5off", Name: "$5 off", DiscountType: "dollars", DiscountInCents: 500})
+
+	r, red, err := client.Redemptions.Redeem("5off", "1", "USD")
+	if err != nil || r.StatusCode != http.StatusCreated || red.Account.Code != "1" {
+		t.Fatalf("TestRedemptions Error: Expected redemption on account 1, given %#v (%v)", red, err)
+	}
+
+	if _, err := client.Redemptions.Delete("1"); err != nil {
+		t.Fatalf("TestRedemptions Error: Error deleting redemption. Err: %s", err)
+	}
+
+	if r, _, _ := client.Redemptions.GetForAccount("1"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestRedemptions Error: Expected status code %d after removing redemption, given %d", http.StatusNotFound, r.StatusCode)
+	}
+
+	client.Coupons.Delete("5off")
+	if r, _, _ := client.Redemptions.Redeem("5off", "1", "USD"); r.StatusCode != 422 {
+		t.Errorf("TestRedemptions Error: Expected status code 422 redeeming inactive coupon, given %d", r.StatusCode)
+	}
+}
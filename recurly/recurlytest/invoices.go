@@ -0,0 +1,678 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// adjustment returns the adjustment with the given UUID, or nil.
+func (s *Server) adjustment(uuid string) *recurly.Adjustment {
+	for _, a := range s.adjustments {
+		if a.UUID == uuid {
+			return a
+		}
+	}
+	return nil
+}
+
+// findInvoice returns the invoice named in the request path. It writes a 404
+// response and returns nil if the invoice does not exist.
+func (s *Server) findInvoice(rw http.ResponseWriter, r *http.Request) *recurly.Invoice {
+	number := r.PathValue("number")
+	for _, inv := range s.invoices {
+		if strconv.Itoa(inv.InvoiceNumber) == number {
+			return inv
+		}
+	}
+
+	notFound(rw, "Invoice", "invoice_number", number)
+	return nil
+}
+
+// total returns the sum of the charges.
+func total(charges []*recurly.Adjustment) int {
+	var n int
+	for _, a := range charges {
+		n += a.TotalInCents
+	}
+	return n
+}
+
+// paymentMethod returns the payment method used to collect with billing
+// info.
+func paymentMethod(b *recurly.Billing) string {
+	switch {
+	case b.PaypalAgreementID != "":
+		return "paypal"
+	case b.AmazonAgreementID != "":
+		return "amazon"
+	case b.RoutingNumber != "":
+		return "ach"
+	}
+	return "credit_card"
+}
+
+// invoice posts the charges to a new invoice on an account. Unless manual is
+// set, or the account has no billing info, the invoice is collected with a
+// successful transaction.
+func (s *Server) invoice(accountCode string, charges []*recurly.Adjustment, manual bool, netTerms recurly.NullInt, poNumber string) *recurly.Invoice {
+	inv := s.draftInvoice(accountCode, charges)
+	inv.InvoiceNumber = s.invoiceNumber
+	inv.PONumber = poNumber
+	s.invoiceNumber++
+
+	for _, a := range charges {
+		a.State = "invoiced"
+		a.Invoice.Code = strconv.Itoa(inv.InvoiceNumber)
+	}
+
+	billing, ok := s.billing[accountCode]
+	if manual || !ok {
+		inv.State = recurly.InvoiceStateOpen
+		inv.CollectionMethod = "manual"
+		inv.NetTerms = netTerms
+		if !inv.NetTerms.Valid {
+			inv.NetTerms = recurly.NewInt(0)
+		}
+	} else {
+		inv.State = recurly.InvoiceStateCollected
+		inv.CollectionMethod = "automatic"
+		inv.ClosedAt = inv.CreatedAt
+		if inv.TotalInCents > 0 {
+			t := &recurly.Transaction{
+				UUID:          s.newID(),
+				Action:        "purchase",
+				AmountInCents: inv.TotalInCents,
+				Currency:      inv.Currency,
+				Status:        recurly.TransactionStatusSuccess,
+				PaymentMethod: paymentMethod(billing),
+				Reference:     s.newID()[16:],
+				Source:        "transaction",
+				Test:          true,
+				Voidable:      recurly.NewBool(true),
+				Refundable:    recurly.NewBool(true),
+				CreatedAt:     inv.CreatedAt,
+				Account:       *s.account(accountCode),
+			}
+			t.XMLName.Local = "transaction"
+			t.Invoice.Code = strconv.Itoa(inv.InvoiceNumber)
+			t.Account.BillingInfo = billing
+			s.transactions = append(s.transactions, t)
+		}
+	}
+
+	s.invoices = append(s.invoices, inv)
+
+	return inv
+}
+
+// draftInvoice returns an unsaved invoice for the charges.
+func (s *Server) draftInvoice(accountCode string, charges []*recurly.Adjustment) *recurly.Invoice {
+	inv := &recurly.Invoice{
+		UUID:      s.newID(),
+		CreatedAt: s.now(),
+	}
+	inv.XMLName.Local = "invoice"
+	inv.Account.Code = accountCode
+	if len(charges) > 0 {
+		inv.Currency = charges[0].Currency
+	}
+
+	for _, a := range charges {
+		inv.SubtotalInCents += a.TotalInCents
+		inv.TaxInCents += a.TaxInCents
+	}
+	inv.TotalInCents = inv.SubtotalInCents + inv.TaxInCents
+
+	return inv
+}
+
+// pending returns the uninvoiced charges and credits on an account.
+func (s *Server) pending(accountCode string) []*recurly.Adjustment {
+	var charges []*recurly.Adjustment
+	for _, a := range s.adjustments {
+		if a.Account.Code == accountCode && a.State == "pending" {
+			charges = append(charges, a)
+		}
+	}
+	return charges
+}
+
+func (s *Server) listAdjustments(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	var adjustments []adjustmentXML
+	state := r.URL.Query().Get("state")
+	for _, v := range s.adjustments {
+		if v.Account.Code == a.Code && (state == "" || v.State == state) {
+			adjustments = append(adjustments, s.adjustmentXML(v))
+		}
+	}
+
+	start, end := paginate(rw, r, len(adjustments))
+	writeXML(rw, http.StatusOK, adjustmentsXML{Adjustments: adjustments[start:end]})
+}
+
+func (s *Server) createAdjustment(rw http.ResponseWriter, r *http.Request) {
+	acct := s.findAccount(rw, r)
+	if acct == nil {
+		return
+	}
+
+	var a recurly.Adjustment
+	if !decode(rw, r, &a) {
+		return
+	}
+
+	if a.UnitAmountInCents == 0 {
+		invalid(rw, "adjustment.unit_amount_in_cents", "blank", "can't be blank")
+		return
+	} else if a.Currency == "" {
+		invalid(rw, "adjustment.currency", "blank", "can't be blank")
+		return
+	}
+
+	a.XMLName.Local = "adjustment"
+	a.Account.Code = acct.Code
+	a.UUID = s.newID()
+	a.State = "pending"
+	a.Origin = "debit"
+	if a.UnitAmountInCents < 0 {
+		a.Origin = "credit"
+	}
+	if a.Quantity == 0 {
+		a.Quantity = 1
+	}
+	a.TotalInCents = a.UnitAmountInCents * a.Quantity
+	a.CreatedAt = s.now()
+	s.adjustments = append(s.adjustments, &a)
+
+	writeXML(rw, http.StatusCreated, s.adjustmentXML(&a))
+}
+
+func (s *Server) getAdjustment(rw http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	a := s.adjustment(uuid)
+	if a == nil {
+		notFound(rw, "Adjustment", "uuid", uuid)
+		return
+	}
+
+	writeXML(rw, http.StatusOK, s.adjustmentXML(a))
+}
+
+func (s *Server) deleteAdjustment(rw http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	a := s.adjustment(uuid)
+	if a == nil {
+		notFound(rw, "Adjustment", "uuid", uuid)
+		return
+	} else if a.State != "pending" {
+		invalid(rw, "adjustment.base", "invalid_state", "has been invoiced and can't be deleted")
+		return
+	}
+
+	for i, v := range s.adjustments {
+		if v == a {
+			s.adjustments = append(s.adjustments[:i], s.adjustments[i+1:]...)
+			break
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listInvoices(rw http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code != "" && s.findAccount(rw, r) == nil {
+		return
+	}
+
+	var invoices []invoiceXML
+	state := r.URL.Query().Get("state")
+	for _, inv := range s.invoices {
+		if (code == "" || inv.Account.Code == code) && (state == "" || inv.State == state) {
+			invoices = append(invoices, s.invoiceXML(inv))
+		}
+	}
+
+	start, end := paginate(rw, r, len(invoices))
+	writeXML(rw, http.StatusOK, invoicesXML{Invoices: invoices[start:end]})
+}
+
+func (s *Server) createInvoice(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	var v recurly.Invoice
+	if !decode(rw, r, &v) {
+		return
+	}
+
+	charges := s.pending(a.Code)
+	if len(charges) == 0 {
+		invalid(rw, "invoice.base", "will_not_invoice", "No charges to invoice")
+		return
+	}
+
+	inv := s.invoice(a.Code, charges, v.CollectionMethod == "manual", v.NetTerms, v.PONumber)
+	writeXML(rw, http.StatusCreated, s.invoiceXML(inv))
+}
+
+func (s *Server) previewInvoice(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAccount(rw, r)
+	if a == nil {
+		return
+	}
+
+	charges := s.pending(a.Code)
+	if len(charges) == 0 {
+		invalid(rw, "invoice.base", "will_not_invoice", "No charges to invoice")
+		return
+	}
+
+	inv := s.draftInvoice(a.Code, charges)
+	inv.State = recurly.InvoiceStateOpen
+	v := s.invoiceXML(inv)
+	for _, c := range charges {
+		v.LineItems = append(v.LineItems, s.adjustmentXML(c))
+	}
+
+	writeXML(rw, http.StatusOK, v)
+}
+
+func (s *Server) getInvoice(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	if r.Header.Get("Accept") == "application/pdf" {
+		rw.Header().Set("Content-Type", "application/pdf")
+		rw.Write([]byte("%PDF-1.4\n% Invoice " + strconv.Itoa(inv.InvoiceNumber) + "\n%%EOF\n"))
+		return
+	}
+
+	writeXML(rw, http.StatusOK, s.invoiceXML(inv))
+}
+
+func (s *Server) markInvoiceSuccessful(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	inv.State = recurly.InvoiceStateCollected
+	inv.ClosedAt = s.now()
+	writeXML(rw, http.StatusOK, s.invoiceXML(inv))
+}
+
+func (s *Server) markInvoiceFailed(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	inv.State = recurly.InvoiceStateFailed
+	inv.ClosedAt = s.now()
+	writeXML(rw, http.StatusOK, s.invoiceXML(inv))
+}
+
+// refundable returns the amount of an invoice that has not been refunded.
+func (s *Server) refundable(inv *recurly.Invoice) int {
+	n := inv.TotalInCents
+	for _, v := range s.invoices {
+		if v.OriginalInvoice.Code == strconv.Itoa(inv.InvoiceNumber) {
+			n += v.TotalInCents
+		}
+	}
+	return n
+}
+
+// refundedQuantity returns how many units of a line item have been refunded.
+func (s *Server) refundedQuantity(a *recurly.Adjustment) int {
+	var n int
+	for _, v := range s.adjustments {
+		if v.OriginalAdjustmentUUID == a.UUID {
+			n += v.Quantity
+		}
+	}
+	return n
+}
+
+// refundInvoice creates a refund invoice with a credit for each refunded
+// line item, or a single credit when refunding an amount. Invoices are only
+// ever paid with transactions here, so credit_first and transaction_first
+// both refund the invoice's transactions, and any amount left over is
+// issued as account credit. all_credit issues the whole refund as account
+// credit.
+func (s *Server) refundInvoice(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	var v refundXML
+	if !decode(rw, r, &v) {
+		return
+	}
+
+	if inv.State != recurly.InvoiceStateCollected || inv.OriginalInvoice.Code != "" {
+		invalid(rw, "invoice.base", "not_refundable", "Only collected invoices can be refunded.")
+		return
+	}
+
+	number := strconv.Itoa(inv.InvoiceNumber)
+	var credits []*recurly.Adjustment
+	for _, item := range v.LineItems {
+		a := s.adjustment(item.UUID)
+		if a == nil || a.Invoice.Code != number {
+			invalid(rw, "invoice.line_items", "not_found", "could not be found on the invoice")
+			return
+		} else if item.Quantity <= 0 || item.Quantity > a.Quantity-s.refundedQuantity(a) {
+			invalid(rw, "invoice.line_items.quantity", "invalid", "is more than the quantity left to refund")
+			return
+		}
+
+		amount := a.TotalInCents * item.Quantity / a.Quantity
+		if item.Prorate.Bool && a.StartDate.Time != nil && a.EndDate.Time != nil {
+			term := int64(a.EndDate.Sub(*a.StartDate.Time) / time.Second)
+			left := int64(a.EndDate.Sub(s.Now()) / time.Second)
+			if left < 0 {
+				left = 0
+			} else if left > term {
+				left = term
+			}
+			amount = int(int64(amount) * left / term)
+		}
+
+		credits = append(credits, &recurly.Adjustment{
+			Description:            a.Description,
+			Origin:                 "credit",
+			UnitAmountInCents:      -a.UnitAmountInCents,
+			Quantity:               item.Quantity,
+			OriginalAdjustmentUUID: a.UUID,
+			TotalInCents:           -amount,
+			Currency:               a.Currency,
+		})
+	}
+	if len(v.LineItems) == 0 {
+		credits = append(credits, &recurly.Adjustment{
+			Description:       "Refund",
+			Origin:            "credit",
+			UnitAmountInCents: -v.AmountInCents,
+			Quantity:          1,
+			TotalInCents:      -v.AmountInCents,
+			Currency:          inv.Currency,
+		})
+	}
+
+	amount := -total(credits)
+	if amount <= 0 || amount > s.refundable(inv) {
+		invalid(rw, "invoice.amount_in_cents", "invalid", "is more than the amount left to refund")
+		return
+	}
+
+	var transactions []*recurly.Transaction
+	var refundable int
+	for _, t := range s.transactions {
+		if t.Invoice.Code == number && t.Refundable.Bool {
+			transactions = append(transactions, t)
+			refundable += t.AmountInCents - s.refunded[t.UUID]
+		}
+	}
+	if v.RefundMethod == recurly.RefundMethodAllCredit {
+		transactions = nil
+	} else if v.RefundMethod == recurly.RefundMethodAllTransaction && refundable < amount {
+		invalid(rw, "invoice.refund_method", "invalid", "There are not enough transactions to refund")
+		return
+	}
+
+	ref := s.draftInvoice(inv.Account.Code, credits)
+	ref.InvoiceNumber = s.invoiceNumber
+	ref.State = recurly.InvoiceStateCollected
+	ref.CollectionMethod = inv.CollectionMethod
+	ref.ClosedAt = ref.CreatedAt
+	ref.Subscription = inv.Subscription
+	ref.OriginalInvoice.Code = number
+	s.invoiceNumber++
+	s.invoices = append(s.invoices, ref)
+
+	for _, a := range credits {
+		a.XMLName.Local = "adjustment"
+		a.UUID = s.newID()
+		a.State = "invoiced"
+		a.Account.Code = inv.Account.Code
+		a.Invoice.Code = strconv.Itoa(ref.InvoiceNumber)
+		a.CreatedAt = ref.CreatedAt
+		s.adjustments = append(s.adjustments, a)
+	}
+
+	remaining := amount
+	for _, t := range transactions {
+		n := t.AmountInCents - s.refunded[t.UUID]
+		if n > remaining {
+			n = remaining
+		}
+		if n > 0 {
+			s.refund(t, n).Invoice.Code = strconv.Itoa(ref.InvoiceNumber)
+			remaining -= n
+		}
+	}
+
+	if remaining > 0 {
+		credit := &recurly.Adjustment{
+			UUID:              s.newID(),
+			State:             "pending",
+			Description:       "Credit from invoice " + number,
+			Origin:            "credit",
+			UnitAmountInCents: -remaining,
+			Quantity:          1,
+			TotalInCents:      -remaining,
+			Currency:          inv.Currency,
+			CreatedAt:         ref.CreatedAt,
+		}
+		credit.XMLName.Local = "adjustment"
+		credit.Account.Code = inv.Account.Code
+		s.adjustments = append(s.adjustments, credit)
+	}
+
+	writeXML(rw, http.StatusCreated, s.invoiceXML(ref))
+}
+
+func (s *Server) voidInvoice(rw http.ResponseWriter, r *http.Request) {
+	inv := s.findInvoice(rw, r)
+	if inv == nil {
+		return
+	}
+
+	switch inv.State {
+	case recurly.InvoiceStateOpen, recurly.InvoiceStatePastDue, recurly.InvoiceStateFailed:
+	default:
+		invalid(rw, "invoice.state", "invalid_transition", "Only open invoices can be voided.")
+		return
+	}
+
+	inv.State = recurly.InvoiceStateVoid
+	inv.ClosedAt = s.now()
+	writeXML(rw, http.StatusOK, s.invoiceXML(inv))
+}
+
+func (s *Server) listTransactions(rw http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code != "" && s.findAccount(rw, r) == nil {
+		return
+	}
+
+	// The state filter uses different names than the transaction status.
+	status := map[string]string{
+		"successful": recurly.TransactionStatusSuccess,
+		"failed":     recurly.TransactionStatusFailed,
+		"voided":     recurly.TransactionStatusVoid,
+	}[r.URL.Query().Get("state")]
+	action := r.URL.Query().Get("type")
+
+	var transactions []transactionXML
+	for _, t := range s.transactions {
+		if code != "" && t.Account.Code != code {
+			continue
+		} else if status != "" && t.Status != status {
+			continue
+		} else if action != "" && t.Action != action {
+			continue
+		}
+
+		transactions = append(transactions, s.transactionXML(t))
+	}
+
+	start, end := paginate(rw, r, len(transactions))
+	writeXML(rw, http.StatusOK, transactionsXML{Transactions: transactions[start:end]})
+}
+
+func (s *Server) createTransaction(rw http.ResponseWriter, r *http.Request) {
+	var nt recurly.NewTransaction
+	if !decode(rw, r, &nt) {
+		return
+	}
+
+	if nt.AmountInCents <= 0 {
+		invalid(rw, "transaction.amount_in_cents", "blank", "can't be blank")
+		return
+	} else if nt.Currency == "" {
+		invalid(rw, "transaction.currency", "blank", "can't be blank")
+		return
+	} else if nt.Account.Code == "" {
+		invalid(rw, "transaction.account.account_code", "blank", "can't be blank")
+		return
+	} else if _, ok := s.billing[nt.Account.Code]; !ok && nt.Account.BillingInfo == nil {
+		invalid(rw, "transaction.account.billing_info", "blank", "can't be blank")
+		return
+	}
+
+	if !s.validShipping(rw, "transaction", nt.Account.Code, nt.ShippingAddressID, nt.ShippingAddress) {
+		return
+	}
+
+	if a := s.account(nt.Account.Code); a == nil {
+		if s.addAccount(rw, nt.Account) == nil {
+			return
+		}
+	} else if nt.Account.BillingInfo != nil {
+		s.billing[a.Code] = s.newBilling(*nt.Account.BillingInfo)
+	}
+
+	if nt.ShippingAddress != nil {
+		s.addShippingAddress(nt.Account.Code, *nt.ShippingAddress)
+	}
+
+	a := &recurly.Adjustment{
+		UUID:              s.newID(),
+		Origin:            "debit",
+		UnitAmountInCents: nt.AmountInCents,
+		Quantity:          1,
+		TotalInCents:      nt.AmountInCents,
+		Currency:          nt.Currency,
+		CreatedAt:         s.now(),
+	}
+	a.XMLName.Local = "adjustment"
+	a.Account.Code = nt.Account.Code
+	s.adjustments = append(s.adjustments, a)
+
+	inv := s.invoice(nt.Account.Code, []*recurly.Adjustment{a}, false, recurly.NullInt{}, "")
+	for _, t := range s.transactions {
+		if t.Invoice.Code == strconv.Itoa(inv.InvoiceNumber) {
+			t.IPAddress = nt.IPAddress
+			writeXML(rw, http.StatusCreated, s.transactionXML(t))
+			return
+		}
+	}
+}
+
+func (s *Server) getTransaction(rw http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	for _, t := range s.transactions {
+		if t.UUID == uuid {
+			writeXML(rw, http.StatusOK, s.transactionXML(t))
+			return
+		}
+	}
+
+	notFound(rw, "Transaction", "uuid", uuid)
+}
+
+// refundTransaction voids the transaction when its full amount is refunded
+// before any partial refunds. Otherwise a refund transaction is created for
+// the amount, which defaults to what is left to refund.
+func (s *Server) refundTransaction(rw http.ResponseWriter, r *http.Request) {
+	var t *recurly.Transaction
+	for _, v := range s.transactions {
+		if v.UUID == r.PathValue("uuid") {
+			t = v
+		}
+	}
+	if t == nil {
+		notFound(rw, "Transaction", "uuid", r.PathValue("uuid"))
+		return
+	} else if !t.Refundable.Bool {
+		invalid(rw, "transaction.base", "not_refundable", "This transaction is not refundable.")
+		return
+	}
+
+	remaining := t.AmountInCents - s.refunded[t.UUID]
+	amount := remaining
+	if v := r.URL.Query().Get("amount_in_cents"); v != "" {
+		amount, _ = strconv.Atoi(v)
+	}
+	if amount <= 0 || amount > remaining {
+		invalid(rw, "transaction.amount_in_cents", "invalid", "is not a valid refund amount")
+		return
+	}
+
+	if t.Voidable.Bool && amount == t.AmountInCents {
+		t.Status = recurly.TransactionStatusVoid
+		t.Voidable = recurly.NewBool(false)
+		t.Refundable = recurly.NewBool(false)
+		writeXML(rw, http.StatusOK, s.transactionXML(t))
+		return
+	}
+
+	writeXML(rw, http.StatusCreated, s.transactionXML(s.refund(t, amount)))
+}
+
+// refund records a refund of amount against a transaction and returns the
+// refund transaction.
+func (s *Server) refund(t *recurly.Transaction, amount int) *recurly.Transaction {
+	s.refunded[t.UUID] += amount
+	t.Voidable = recurly.NewBool(false)
+	t.Refundable = recurly.NewBool(s.refunded[t.UUID] < t.AmountInCents)
+
+	refund := &recurly.Transaction{
+		UUID:          s.newID(),
+		Action:        "refund",
+		AmountInCents: amount,
+		Currency:      t.Currency,
+		Status:        recurly.TransactionStatusSuccess,
+		PaymentMethod: t.PaymentMethod,
+		Reference:     s.newID()[16:],
+		Source:        "transaction",
+		Test:          true,
+		Voidable:      recurly.NewBool(false),
+		Refundable:    recurly.NewBool(false),
+		CreatedAt:     s.now(),
+		Account:       t.Account,
+	}
+	refund.XMLName.Local = "transaction"
+	refund.Invoice = t.Invoice
+	refund.Subscription = t.Subscription
+	s.transactions = append(s.transactions, refund)
+
+	return refund
+}
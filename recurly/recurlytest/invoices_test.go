@@ -0,0 +1,223 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+func TestAdjustmentsAndInvoices(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+
+	if r, _, _ := client.Invoices.Create("1", recurly.Invoice{}); r.StatusCode != 422 || r.Errors[0].Symbol != "will_not_invoice" {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected will_not_invoice without pending charges, given %d %#v", r.StatusCode, r.Errors)
+	}
+
+	_, charge, err := client.Adjustments.Create("1", recurly.Adjustment{Description: "Setup", UnitAmountInCents: 2000, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("TestAdjustmentsAndInvoices Error: Error creating adjustment. Err: %s", err)
+	}
+
+	if charge.State != "pending" || charge.Origin != "debit" || charge.TotalInCents != 2000 || charge.Account.Code != "1" {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected pending debit for 2000 cents, given %#v", charge)
+	}
+
+	client.Adjustments.Create("1", recurly.Adjustment{Description: "Credit", UnitAmountInCents: -500, Currency: "USD"})
+
+	_, preview, _ := client.Invoices.Preview("1")
+	if preview.TotalInCents != 1500 || len(preview.LineItems) != 2 {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected preview for 1500 cents with 2 line items, given %#v", preview)
+	}
+
+	// Without billing info the invoice is left open for manual collection.
+	_, inv, err := client.Invoices.Create("1", recurly.Invoice{PONumber: "PO-1"})
+	if err != nil {
+		t.Fatalf("TestAdjustmentsAndInvoices Error: Error creating invoice. Err: %s", err)
+	}
+
+	if inv.State != recurly.InvoiceStateOpen || inv.TotalInCents != 1500 || inv.PONumber != "PO-1" || len(inv.LineItems) != 2 {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected open invoice for 1500 cents, given %#v", inv)
+	}
+
+	_, charge, _ = client.Adjustments.Get(charge.UUID)
+	if charge.State != "invoiced" || charge.Invoice.Code != "1000" {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected adjustment to be invoiced on 1000, given %#v", charge)
+	}
+
+	if r, _ := client.Adjustments.Delete(charge.UUID); r.StatusCode != 422 {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected status code 422 deleting invoiced adjustment, given %d", r.StatusCode)
+	}
+
+	_, inv, _ = client.Invoices.MarkAsPaid(1000)
+	if inv.State != recurly.InvoiceStateCollected || !inv.ClosedAt.Equal(now) {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected collected invoice, given %#v", inv)
+	}
+
+	_, pdf, err := client.Invoices.GetPDF(1000, "")
+	if err != nil || !strings.HasPrefix(pdf.String(), "%PDF") {
+		t.Errorf("TestAdjustmentsAndInvoices Error: Expected PDF, given %q (%v)", pdf, err)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	_, client := newServer(t)
+
+	nt := recurly.NewTransaction{AmountInCents: 700, Currency: "USD", Account: recurly.Account{Code: "1"}}
+	if r, _, _ := client.Transactions.Create(nt); r.StatusCode != 422 || r.Errors[0].Field != "transaction.account.billing_info" {
+		t.Errorf("TestTransactions Error: Expected billing info to be required, given %d %#v", r.StatusCode, r.Errors)
+	}
+
+	nt.Account.BillingInfo = &recurly.Billing{Number: "5555555555554444", Month: 1, Year: 2020}
+	r, txn, err := client.Transactions.Create(nt)
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestTransactions Error: Expected transaction to be created, given %v (%+v)", err, r)
+	}
+
+	if txn.Status != recurly.TransactionStatusSuccess || txn.AmountInCents != 700 || txn.Account.Code != "1" || txn.Invoice.Code != "1000" {
+		t.Errorf("TestTransactions Error: Expected successful transaction for 700 cents on invoice 1000, given %#v", txn)
+	}
+
+	if txn.Account.BillingInfo == nil || txn.Account.BillingInfo.CardType != "MasterCard" {
+		t.Errorf("TestTransactions Error: Expected transaction details to include the card, given %#v", txn.Account.BillingInfo)
+	}
+
+	if _, given, _ := client.Transactions.Get(txn.UUID); given.UUID != txn.UUID {
+		t.Errorf("TestTransactions Error: Expected to get transaction %s, given %#v", txn.UUID, given)
+	}
+
+	_, txns, _ := client.Transactions.List(recurly.Params{"state": "failed"})
+	if len(txns) != 0 {
+		t.Errorf("TestTransactions Error: Expected no failed transactions, given %d", len(txns))
+	}
+}
+
+func TestRefundTransaction(t *testing.T) {
+	_, client := newServer(t)
+	nt := recurly.NewTransaction{AmountInCents: 1000, Currency: "USD", Account: recurly.Account{Code: "1", BillingInfo: &recurly.Billing{Number: "4111111111111111", Month: 1, Year: 2020}}}
+
+	_, txn, _ := client.Transactions.Create(nt)
+	r, refund, err := client.Transactions.Refund(txn.UUID, 300)
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestRefundTransaction Error: Expected refund to be created, given %v (%+v)", err, r)
+	}
+
+	if refund.Action != "refund" || refund.AmountInCents != 300 || refund.Status != recurly.TransactionStatusSuccess {
+		t.Errorf("TestRefundTransaction Error: Expected successful refund of 300 cents, given %#v", refund)
+	}
+
+	// A partially refunded transaction can no longer be voided.
+	_, txn, _ = client.Transactions.Get(txn.UUID)
+	if txn.Voidable.Bool || !txn.Refundable.Bool {
+		t.Errorf("TestRefundTransaction Error: Expected refundable transaction that is not voidable, given %#v", txn)
+	}
+
+	if r, _, _ := client.Transactions.Refund(txn.UUID, 800); r.StatusCode != 422 {
+		t.Errorf("TestRefundTransaction Error: Expected status code 422 refunding more than remains, given %d", r.StatusCode)
+	}
+
+	_, refund, _ = client.Transactions.Void(txn.UUID)
+	if refund.Action != "refund" || refund.AmountInCents != 700 {
+		t.Errorf("TestRefundTransaction Error: Expected refund of the remaining 700 cents, given %d", refund.AmountInCents)
+	}
+
+	if r, _, _ := client.Transactions.Void(txn.UUID); r.StatusCode != 422 || r.Errors[0].Symbol != "not_refundable" {
+		t.Errorf("TestRefundTransaction Error: Expected fully refunded transaction to not be refundable, given %d %#v", r.StatusCode, r.Errors)
+	}
+}
+
+func TestVoidTransaction(t *testing.T) {
+	_, client := newServer(t)
+	nt := recurly.NewTransaction{AmountInCents: 1000, Currency: "USD", Account: recurly.Account{Code: "1", BillingInfo: &recurly.Billing{Number: "4111111111111111", Month: 1, Year: 2020}}}
+
+	_, txn, _ := client.Transactions.Create(nt)
+	_, txn, err := client.Transactions.Void(txn.UUID)
+	if err != nil || txn.Status != recurly.TransactionStatusVoid || txn.Refundable.Bool {
+		t.Errorf("TestVoidTransaction Error: Expected voided transaction, given %#v (%v)", txn, err)
+	}
+
+	_, txns, _ := client.Transactions.List(recurly.Params{"state": "voided"})
+	if len(txns) != 1 {
+		t.Errorf("TestVoidTransaction Error: Expected one voided transaction, given %d", len(txns))
+	}
+}
+
+func TestRefundInvoice(t *testing.T) {
+	_, client := newServer(t)
+	client.Subscriptions.Create(signup("1"))
+	client.Adjustments.Create("1", recurly.Adjustment{Description: "Seats", UnitAmountInCents: 300, Quantity: 3, Currency: "USD"})
+	_, inv, _ := client.Invoices.Create("1", recurly.Invoice{})
+
+	// Two of the three seats are refunded to the card.
+	_, ref, err := client.Invoices.RefundLineItems(inv.InvoiceNumber, []recurly.RefundLineItem{inv.LineItems[0].RefundLineItem(2, false)}, recurly.RefundMethodTransactionFirst)
+	if err != nil {
+		t.Fatalf("TestRefundInvoice Error: Error refunding line items. Err: %s", err)
+	}
+
+	if ref.TotalInCents != -600 || ref.OriginalInvoice.Code != "1001" || len(ref.LineItems) != 1 || ref.LineItems[0].OriginalAdjustmentUUID != inv.LineItems[0].UUID {
+		t.Errorf("TestRefundInvoice Error: Expected refund invoice for -600 cents, given %#v", ref)
+	}
+
+	if len(ref.Transactions) != 1 || ref.Transactions[0].Action != "refund" || ref.Transactions[0].AmountInCents != 600 {
+		t.Errorf("TestRefundInvoice Error: Expected refund transaction for 600 cents, given %#v", ref.Transactions)
+	}
+
+	if r, _, _ := client.Invoices.RefundLineItems(inv.InvoiceNumber, []recurly.RefundLineItem{inv.LineItems[0].RefundLineItem(2, false)}, ""); r.StatusCode != 422 {
+		t.Errorf("TestRefundInvoice Error: Expected status code 422 refunding more seats than remain, given %d", r.StatusCode)
+	}
+
+	// The rest is refunded as account credit.
+	_, ref, _ = client.Invoices.RefundAmount(inv.InvoiceNumber, 300, recurly.RefundMethodAllCredit)
+	if ref.TotalInCents != -300 || len(ref.Transactions) != 0 {
+		t.Errorf("TestRefundInvoice Error: Expected refund invoice for -300 cents without transactions, given %#v", ref)
+	}
+
+	_, credits, _ := client.Adjustments.List("1", recurly.Params{"state": "pending"})
+	if len(credits) != 1 || credits[0].TotalInCents != -300 {
+		t.Errorf("TestRefundInvoice Error: Expected pending credit of -300 cents, given %#v", credits)
+	}
+
+	if r, _, _ := client.Invoices.RefundAmount(inv.InvoiceNumber, 100, ""); r.StatusCode != 422 {
+		t.Errorf("TestRefundInvoice Error: Expected status code 422 refunding a fully refunded invoice, given %d", r.StatusCode)
+	}
+}
+
+func TestRefundInvoiceProrated(t *testing.T) {
+	srv, client := newServer(t)
+	client.Subscriptions.Create(signup("1"))
+	_, inv, _ := client.Invoices.Get(1000)
+
+	// A third of the way through the 31 day term of March.
+	srv.Now = func() time.Time { return now.AddDate(0, 0, 10).Add(8 * time.Hour) }
+	_, ref, err := client.Invoices.RefundLineItems(1000, []recurly.RefundLineItem{inv.LineItems[0].RefundLineItem(0, true)}, "")
+	if err != nil {
+		t.Fatalf("TestRefundInvoiceProrated Error: Error refunding line items. Err: %s", err)
+	}
+
+	if ref.TotalInCents != -666 {
+		t.Errorf("TestRefundInvoiceProrated Error: Expected prorated refund of -666 cents, given %d", ref.TotalInCents)
+	}
+}
+
+func TestVoidInvoice(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+	client.Adjustments.Create("1", recurly.Adjustment{Description: "Setup", UnitAmountInCents: 2000, Currency: "USD"})
+	client.Invoices.Create("1", recurly.Invoice{})
+
+	if r, _, _ := client.Invoices.RefundAmount(1000, 100, ""); r.StatusCode != 422 {
+		t.Errorf("TestVoidInvoice Error: Expected status code 422 refunding an open invoice, given %d", r.StatusCode)
+	}
+
+	_, inv, err := client.Invoices.Void(1000)
+	if err != nil || inv.State != recurly.InvoiceStateVoid || !inv.ClosedAt.Equal(now) {
+		t.Errorf("TestVoidInvoice Error: Expected voided invoice, given %#v (%v)", inv, err)
+	}
+
+	if r, _, _ := client.Invoices.Void(1000); r.StatusCode != 422 {
+		t.Errorf("TestVoidInvoice Error: Expected status code 422 voiding twice, given %d", r.StatusCode)
+	}
+}
@@ -0,0 +1,123 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// measuredUnit returns the measured unit with the given ID, or nil.
+func (s *Server) measuredUnit(id int) *recurly.MeasuredUnit {
+	for _, u := range s.measuredUnits {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+// findMeasuredUnit returns the measured unit named in the request path. It
+// writes a 404 response and returns nil if the measured unit does not
+// exist.
+func (s *Server) findMeasuredUnit(rw http.ResponseWriter, r *http.Request) *recurly.MeasuredUnit {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	u := s.measuredUnit(id)
+	if u == nil {
+		notFound(rw, "MeasuredUnit", "id", r.PathValue("id"))
+	}
+	return u
+}
+
+func (s *Server) listMeasuredUnits(rw http.ResponseWriter, r *http.Request) {
+	var units []measuredUnitXML
+	for _, u := range s.measuredUnits {
+		units = append(units, measuredUnitXML(*u))
+	}
+
+	start, end := paginate(rw, r, len(units))
+	writeXML(rw, http.StatusOK, measuredUnitsXML{MeasuredUnits: units[start:end]})
+}
+
+func (s *Server) createMeasuredUnit(rw http.ResponseWriter, r *http.Request) {
+	var u recurly.MeasuredUnit
+	if !decode(rw, r, &u) {
+		return
+	}
+
+	if u.Name == "" {
+		invalid(rw, "measured_unit.name", "blank", "can't be blank")
+		return
+	} else if u.DisplayName == "" {
+		invalid(rw, "measured_unit.display_name", "blank", "can't be blank")
+		return
+	}
+	for _, v := range s.measuredUnits {
+		if v.Name == u.Name {
+			invalid(rw, "measured_unit.name", "taken", "has already been taken")
+			return
+		}
+	}
+
+	s.seq++
+	u.XMLName.Local = "measured_unit"
+	u.ID = s.seq
+	u.CreatedAt = s.now()
+	u.UpdatedAt = u.CreatedAt
+	s.measuredUnits = append(s.measuredUnits, &u)
+
+	writeXML(rw, http.StatusCreated, measuredUnitXML(u))
+}
+
+func (s *Server) getMeasuredUnit(rw http.ResponseWriter, r *http.Request) {
+	if u := s.findMeasuredUnit(rw, r); u != nil {
+		writeXML(rw, http.StatusOK, measuredUnitXML(*u))
+	}
+}
+
+func (s *Server) updateMeasuredUnit(rw http.ResponseWriter, r *http.Request) {
+	u := s.findMeasuredUnit(rw, r)
+	if u == nil {
+		return
+	}
+
+	var update recurly.MeasuredUnit
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	// The ID and timestamps can't be updated.
+	update.ID, update.CreatedAt, update.UpdatedAt = 0, recurly.NullTime{}, recurly.NullTime{}
+	merge(u, update)
+	u.UpdatedAt = s.now()
+
+	writeXML(rw, http.StatusOK, measuredUnitXML(*u))
+}
+
+// deleteMeasuredUnit removes a measured unit. Like Recurly, units used by
+// add ons can't be removed.
+func (s *Server) deleteMeasuredUnit(rw http.ResponseWriter, r *http.Request) {
+	u := s.findMeasuredUnit(rw, r)
+	if u == nil {
+		return
+	}
+
+	id := strconv.Itoa(u.ID)
+	for _, addOns := range s.addOns {
+		for _, a := range addOns {
+			if a.MeasuredUnit.Code == id {
+				invalid(rw, "measured_unit.base", "in_use", "is used by add ons and can't be deleted")
+				return
+			}
+		}
+	}
+
+	for i, v := range s.measuredUnits {
+		if v == u {
+			s.measuredUnits = append(s.measuredUnits[:i], s.measuredUnits[i+1:]...)
+			break
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,283 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// plan returns the plan with the given code, or nil.
+func (s *Server) plan(code string) *recurly.Plan {
+	for _, p := range s.plans {
+		if p.Code == code {
+			return p
+		}
+	}
+	return nil
+}
+
+// addOn returns the add on with the given code for a plan, or nil.
+func (s *Server) addOn(planCode string, code string) *recurly.AddOn {
+	for _, a := range s.addOns[planCode] {
+		if a.Code == code {
+			return a
+		}
+	}
+	return nil
+}
+
+// findPlan returns the plan named in the request path. It writes a 404
+// response and returns nil if the plan does not exist.
+func (s *Server) findPlan(rw http.ResponseWriter, r *http.Request) *recurly.Plan {
+	code := r.PathValue("code")
+	p := s.plan(code)
+	if p == nil {
+		notFound(rw, "Plan", "plan_code", code)
+	}
+	return p
+}
+
+// findAddOn returns the add on named in the request path. It writes a 404
+// response and returns nil if the plan or add on does not exist.
+func (s *Server) findAddOn(rw http.ResponseWriter, r *http.Request) *recurly.AddOn {
+	p := s.findPlan(rw, r)
+	if p == nil {
+		return nil
+	}
+
+	code := r.PathValue("addOn")
+	a := s.addOn(p.Code, code)
+	if a == nil {
+		notFound(rw, "AddOn", "add_on_code", code)
+	}
+	return a
+}
+
+// unitAmount returns the amount in cents for the currency.
+func unitAmount(u recurly.UnitAmount, currency string) int {
+	v, _ := u.Get(currency)
+	return v
+}
+
+func (s *Server) listPlans(rw http.ResponseWriter, r *http.Request) {
+	plans := make([]recurly.Plan, 0, len(s.plans))
+	for _, p := range s.plans {
+		plans = append(plans, *p)
+	}
+
+	start, end := paginate(rw, r, len(plans))
+	writeXML(rw, http.StatusOK, plansXML{Plans: plans[start:end]})
+}
+
+func (s *Server) createPlan(rw http.ResponseWriter, r *http.Request) {
+	var p recurly.Plan
+	if !decode(rw, r, &p) {
+		return
+	}
+
+	if p.Code == "" {
+		invalid(rw, "plan.plan_code", "blank", "can't be blank")
+		return
+	} else if p.Name == "" {
+		invalid(rw, "plan.name", "blank", "can't be blank")
+		return
+	} else if s.plan(p.Code) != nil {
+		invalid(rw, "plan.plan_code", "taken", "has already been taken")
+		return
+	}
+
+	if p.IntervalUnit == "" {
+		p.IntervalUnit = "months"
+	}
+	if p.IntervalLength == 0 {
+		p.IntervalLength = 1
+	}
+	if p.TrialIntervalUnit == "" {
+		p.TrialIntervalUnit = "days"
+	}
+	p.CreatedAt = s.now()
+	s.plans = append(s.plans, &p)
+
+	writeXML(rw, http.StatusCreated, p)
+}
+
+func (s *Server) getPlan(rw http.ResponseWriter, r *http.Request) {
+	if p := s.findPlan(rw, r); p != nil {
+		writeXML(rw, http.StatusOK, p)
+	}
+}
+
+func (s *Server) updatePlan(rw http.ResponseWriter, r *http.Request) {
+	p := s.findPlan(rw, r)
+	if p == nil {
+		return
+	}
+
+	var update recurly.Plan
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	update.Code, update.CreatedAt = "", recurly.NullTime{}
+	merge(p, update)
+
+	writeXML(rw, http.StatusOK, p)
+}
+
+func (s *Server) deletePlan(rw http.ResponseWriter, r *http.Request) {
+	p := s.findPlan(rw, r)
+	if p == nil {
+		return
+	}
+
+	for i, v := range s.plans {
+		if v == p {
+			s.plans = append(s.plans[:i], s.plans[i+1:]...)
+			break
+		}
+	}
+	delete(s.addOns, p.Code)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAddOns(rw http.ResponseWriter, r *http.Request) {
+	p := s.findPlan(rw, r)
+	if p == nil {
+		return
+	}
+
+	addOns := make([]addOnXML, 0, len(s.addOns[p.Code]))
+	for _, a := range s.addOns[p.Code] {
+		addOns = append(addOns, s.addOnXML(a))
+	}
+
+	start, end := paginate(rw, r, len(addOns))
+	writeXML(rw, http.StatusOK, addOnsXML{AddOns: addOns[start:end]})
+}
+
+func (s *Server) createAddOn(rw http.ResponseWriter, r *http.Request) {
+	p := s.findPlan(rw, r)
+	if p == nil {
+		return
+	}
+
+	var a recurly.AddOn
+	if !decode(rw, r, &a) {
+		return
+	}
+
+	if a.Code == "" {
+		invalid(rw, "add_on.add_on_code", "blank", "can't be blank")
+		return
+	} else if s.addOn(p.Code, a.Code) != nil {
+		invalid(rw, "add_on.add_on_code", "taken", "has already been taken")
+		return
+	}
+
+	if a.AddOnType == "" {
+		a.AddOnType = recurly.AddOnTypeFixed
+	}
+	if !s.usageAddOn(rw, &a) {
+		return
+	}
+
+	if !a.DefaultQuantity.Valid {
+		a.DefaultQuantity = recurly.NewInt(1)
+	}
+	a.CreatedAt = s.now()
+	s.addOns[p.Code] = append(s.addOns[p.Code], &a)
+
+	writeXML(rw, http.StatusCreated, s.addOnXML(&a))
+}
+
+func (s *Server) getAddOn(rw http.ResponseWriter, r *http.Request) {
+	if a := s.findAddOn(rw, r); a != nil {
+		writeXML(rw, http.StatusOK, s.addOnXML(a))
+	}
+}
+
+func (s *Server) updateAddOn(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAddOn(rw, r)
+	if a == nil {
+		return
+	}
+
+	var update recurly.AddOn
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	// The code, type and creation time can't be updated.
+	update.Code, update.AddOnType, update.CreatedAt = "", "", recurly.NullTime{}
+	merged := *a
+	merge(&merged, update)
+	if !s.usageAddOn(rw, &merged) {
+		return
+	}
+	*a = merged
+
+	writeXML(rw, http.StatusOK, s.addOnXML(a))
+}
+
+// usageAddOn checks the usage fields of an add on and links a usage add on
+// to its measured unit. It writes a 422 response and returns false if they
+// aren't valid.
+func (s *Server) usageAddOn(rw http.ResponseWriter, a *recurly.AddOn) bool {
+	switch a.AddOnType {
+	case recurly.AddOnTypeFixed:
+		return true
+	case recurly.AddOnTypeUsage:
+	default:
+		invalid(rw, "add_on.add_on_type", "inclusion", "is not included in the list")
+		return false
+	}
+
+	switch a.UsageType {
+	case recurly.UsageTypePrice:
+	case recurly.UsageTypePercentage:
+		if a.UsagePercentage == "" {
+			break
+		}
+		if pct, err := strconv.ParseFloat(a.UsagePercentage, 64); err != nil || pct < 0 || pct > 100 {
+			invalid(rw, "add_on.usage_percentage", "inclusion", "must be between 0 and 100")
+			return false
+		}
+	default:
+		invalid(rw, "add_on.usage_type", "inclusion", "is not included in the list")
+		return false
+	}
+
+	if a.MeasuredUnitID != 0 {
+		if s.measuredUnit(a.MeasuredUnitID) == nil {
+			invalid(rw, "add_on.measured_unit_id", "invalid", "is invalid")
+			return false
+		}
+		a.MeasuredUnit.Code = strconv.Itoa(a.MeasuredUnitID)
+		a.MeasuredUnitID = 0
+	}
+	if a.MeasuredUnit.Code == "" {
+		invalid(rw, "add_on.measured_unit_id", "blank", "can't be blank")
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) deleteAddOn(rw http.ResponseWriter, r *http.Request) {
+	a := s.findAddOn(rw, r)
+	if a == nil {
+		return
+	}
+
+	code := r.PathValue("code")
+	for i, v := range s.addOns[code] {
+		if v == a {
+			s.addOns[code] = append(s.addOns[code][:i], s.addOns[code][i+1:]...)
+			break
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
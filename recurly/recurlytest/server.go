@@ -0,0 +1,435 @@
+// Package recurlytest provides an in-memory fake of the Recurly v2 API for
+// testing code that uses the recurly package.
+//
+// The fake is stateful: creating a subscription for a new account creates the
+// account, its billing info, an invoice and a transaction, all of which can
+// then be read back through the client. It covers accounts, billing info,
+// shipping addresses, plans, add ons, measured units, subscriptions, usage,
+// adjustments, invoices, transactions, coupons and redemptions.
+//
+//	srv := recurlytest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	client.Plans.Create(recurly.Plan{Code: "gold", ...})
+//
+// Failures can be injected with FailNext and DeclineNext, and account notes,
+// which the API can only read, added with AddNote.
+package recurlytest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+type (
+	// Server is a fake Recurly API server.
+	Server struct {
+		// URL is the base URL of the server, without the trailing /v2/.
+		URL string
+
+		// Now returns the current time used for timestamps. Defaults to
+		// time.Now. Set it before making requests for deterministic times.
+		Now func() time.Time
+
+		server *httptest.Server
+
+		mu            sync.Mutex
+		seq           int
+		invoiceNumber int
+		failures      []failure
+
+		accounts          []*recurly.Account
+		billing           map[string]*recurly.Billing
+		acquisitions      map[string]*recurly.AccountAcquisition
+		shippingAddresses []*recurly.ShippingAddress
+		plans             []*recurly.Plan
+		addOns            map[string][]*recurly.AddOn
+		subscriptions     []*recurly.Subscription
+		adjustments       []*recurly.Adjustment
+		invoices          []*recurly.Invoice
+		transactions      []*recurly.Transaction
+		refunded          map[string]int
+		coupons           []*recurly.Coupon
+		redemptions       []*redemption
+		notes             map[string][]noteXML
+		measuredUnits     []*recurly.MeasuredUnit
+		usage             []*usageRecord
+	}
+
+	// failure is an injected error for the next matching request.
+	failure struct {
+		method string
+		path   string
+		errors []recurly.Error
+		txnErr *recurly.TransactionError
+	}
+
+	// router matches requests against patterns like "GET /v2/accounts/{code}"
+	// and sets the path values for the handler. It is used in place of
+	// http.ServeMux patterns, which depend on the GODEBUG settings of the
+	// program using this package.
+	router []route
+
+	route struct {
+		method   string
+		segments []string
+		handler  http.HandlerFunc
+	}
+
+	// redemption is a coupon redeemed on an account, along with the invoice
+	// it was applied to.
+	redemption struct {
+		recurly.Redemption
+		invoiceNumber int
+	}
+)
+
+// NewServer starts a fake Recurly server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Now:           time.Now,
+		invoiceNumber: 1000,
+		billing:       make(map[string]*recurly.Billing),
+		acquisitions:  make(map[string]*recurly.AccountAcquisition),
+		addOns:        make(map[string][]*recurly.AddOn),
+		refunded:      make(map[string]int),
+		notes:         make(map[string][]noteXML),
+	}
+
+	mux := &router{}
+	s.routes(mux)
+	s.server = httptest.NewServer(s.handler(mux))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Client returns a recurly.Client configured to talk to the fake server.
+func (s *Server) Client() *recurly.Client {
+	c := recurly.NewClient("test", "fake-api-key", s.server.Client())
+	c.BaseURL = s.URL + "/"
+	return c
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddNote adds a note to an account. Recurly only adds notes through its
+// web interface, so the API has no way to create them.
+func (s *Server) AddNote(accountCode string, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notes[accountCode] = append(s.notes[accountCode], noteXML{Message: message, CreatedAt: s.now()})
+}
+
+// FailNext makes the next request with the given method and path fail with
+// a 422 response holding the validation errors. The path is the API path
+// without the /v2/ prefix, such as "subscriptions" or "accounts/1".
+func (s *Server) FailNext(method string, path string, errs ...recurly.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{
+		method: strings.ToUpper(method),
+		path:   strings.Trim(path, "/"),
+		errors: errs,
+	})
+}
+
+// DeclineNext makes the next request with the given method and path fail
+// with a 422 response holding the gateway transaction error, as when a card
+// is declined. The path is the API path without the /v2/ prefix.
+func (s *Server) DeclineNext(method string, path string, te recurly.TransactionError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{
+		method: strings.ToUpper(method),
+		path:   strings.Trim(path, "/"),
+		errors: []recurly.Error{
+			recurly.Error{
+				Field:   "transaction.account.base",
+				Symbol:  te.ErrorCode,
+				Message: te.CustomerMessage,
+			},
+		},
+		txnErr: &te,
+	})
+}
+
+// handler wraps mux so that every request is authenticated, serialized and
+// checked against injected failures.
+func (s *Server) handler(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user == "" {
+			writeXML(rw, http.StatusUnauthorized, errorXML{Symbol: "unauthorized", Description: "Please provide a valid API key."})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2/"), "/")
+		for i, f := range s.failures {
+			if f.method == r.Method && f.path == path {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+				writeXML(rw, 422, errorsXML{Errors: f.errors, TransactionError: f.txnErr})
+				return
+			}
+		}
+
+		mux.ServeHTTP(rw, r)
+	})
+}
+
+// routes registers the API endpoints.
+func (s *Server) routes(mux *router) {
+	// Accounts
+	mux.HandleFunc("GET /v2/accounts", s.listAccounts)
+	mux.HandleFunc("POST /v2/accounts", s.createAccount)
+	mux.HandleFunc("GET /v2/accounts/{code}", s.getAccount)
+	mux.HandleFunc("PUT /v2/accounts/{code}", s.updateAccount)
+	mux.HandleFunc("DELETE /v2/accounts/{code}", s.closeAccount)
+	mux.HandleFunc("PUT /v2/accounts/{code}/reopen", s.reopenAccount)
+	mux.HandleFunc("GET /v2/accounts/{code}/notes", s.listNotes)
+	mux.HandleFunc("GET /v2/accounts/{code}/child_accounts", s.listChildAccounts)
+	mux.HandleFunc("GET /v2/accounts/{code}/balance", s.getBalance)
+	mux.HandleFunc("GET /v2/accounts/{code}/acquisition", s.getAcquisition)
+	mux.HandleFunc("POST /v2/accounts/{code}/acquisition", s.saveAcquisition)
+	mux.HandleFunc("PUT /v2/accounts/{code}/acquisition", s.saveAcquisition)
+	mux.HandleFunc("DELETE /v2/accounts/{code}/acquisition", s.deleteAcquisition)
+
+	// Shipping addresses
+	mux.HandleFunc("GET /v2/accounts/{code}/shipping_addresses", s.listShippingAddresses)
+	mux.HandleFunc("POST /v2/accounts/{code}/shipping_addresses", s.createShippingAddress)
+	mux.HandleFunc("GET /v2/accounts/{code}/shipping_addresses/{id}", s.getShippingAddress)
+	mux.HandleFunc("PUT /v2/accounts/{code}/shipping_addresses/{id}", s.updateShippingAddress)
+	mux.HandleFunc("DELETE /v2/accounts/{code}/shipping_addresses/{id}", s.deleteShippingAddress)
+
+	// Billing info
+	mux.HandleFunc("GET /v2/accounts/{code}/billing_info", s.getBilling)
+	mux.HandleFunc("POST /v2/accounts/{code}/billing_info", s.saveBilling)
+	mux.HandleFunc("PUT /v2/accounts/{code}/billing_info", s.saveBilling)
+	mux.HandleFunc("DELETE /v2/accounts/{code}/billing_info", s.clearBilling)
+
+	// Plans and add ons
+	mux.HandleFunc("GET /v2/plans", s.listPlans)
+	mux.HandleFunc("POST /v2/plans", s.createPlan)
+	mux.HandleFunc("GET /v2/plans/{code}", s.getPlan)
+	mux.HandleFunc("PUT /v2/plans/{code}", s.updatePlan)
+	mux.HandleFunc("DELETE /v2/plans/{code}", s.deletePlan)
+	mux.HandleFunc("GET /v2/plans/{code}/add_ons", s.listAddOns)
+	mux.HandleFunc("POST /v2/plans/{code}/add_ons", s.createAddOn)
+	mux.HandleFunc("GET /v2/plans/{code}/add_ons/{addOn}", s.getAddOn)
+	mux.HandleFunc("PUT /v2/plans/{code}/add_ons/{addOn}", s.updateAddOn)
+	mux.HandleFunc("DELETE /v2/plans/{code}/add_ons/{addOn}", s.deleteAddOn)
+	mux.HandleFunc("GET /v2/measured_units", s.listMeasuredUnits)
+	mux.HandleFunc("POST /v2/measured_units", s.createMeasuredUnit)
+	mux.HandleFunc("GET /v2/measured_units/{id}", s.getMeasuredUnit)
+	mux.HandleFunc("PUT /v2/measured_units/{id}", s.updateMeasuredUnit)
+	mux.HandleFunc("DELETE /v2/measured_units/{id}", s.deleteMeasuredUnit)
+
+	// Subscriptions
+	mux.HandleFunc("GET /v2/subscriptions", s.listSubscriptions)
+	mux.HandleFunc("GET /v2/accounts/{code}/subscriptions", s.listSubscriptions)
+	mux.HandleFunc("POST /v2/subscriptions", s.createSubscription)
+	mux.HandleFunc("POST /v2/subscriptions/preview", s.previewSubscription)
+	mux.HandleFunc("GET /v2/subscriptions/{uuid}", s.getSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}", s.updateSubscription)
+	mux.HandleFunc("POST /v2/subscriptions/{uuid}/preview", s.previewSubscriptionChange)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/notes", s.updateSubscriptionNotes)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/cancel", s.cancelSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/reactivate", s.reactivateSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/terminate", s.terminateSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/postpone", s.postponeSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/pause", s.pauseSubscription)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/resume", s.resumeSubscription)
+	mux.HandleFunc("DELETE /v2/subscriptions/{uuid}/pending", s.cancelPendingChange)
+	mux.HandleFunc("GET /v2/subscriptions/{uuid}/add_ons/{addOn}/usage", s.listUsage)
+	mux.HandleFunc("POST /v2/subscriptions/{uuid}/add_ons/{addOn}/usage", s.createUsage)
+	mux.HandleFunc("GET /v2/subscriptions/{uuid}/add_ons/{addOn}/usage/{id}", s.getUsage)
+	mux.HandleFunc("PUT /v2/subscriptions/{uuid}/add_ons/{addOn}/usage/{id}", s.updateUsage)
+	mux.HandleFunc("DELETE /v2/subscriptions/{uuid}/add_ons/{addOn}/usage/{id}", s.deleteUsage)
+
+	// Adjustments
+	mux.HandleFunc("GET /v2/accounts/{code}/adjustments", s.listAdjustments)
+	mux.HandleFunc("POST /v2/accounts/{code}/adjustments", s.createAdjustment)
+	mux.HandleFunc("GET /v2/adjustments/{uuid}", s.getAdjustment)
+	mux.HandleFunc("DELETE /v2/adjustments/{uuid}", s.deleteAdjustment)
+
+	// Invoices
+	mux.HandleFunc("GET /v2/invoices", s.listInvoices)
+	mux.HandleFunc("GET /v2/accounts/{code}/invoices", s.listInvoices)
+	mux.HandleFunc("POST /v2/accounts/{code}/invoices", s.createInvoice)
+	mux.HandleFunc("POST /v2/accounts/{code}/invoices/preview", s.previewInvoice)
+	mux.HandleFunc("GET /v2/invoices/{number}", s.getInvoice)
+	mux.HandleFunc("PUT /v2/invoices/{number}/mark_successful", s.markInvoiceSuccessful)
+	mux.HandleFunc("PUT /v2/invoices/{number}/mark_failed", s.markInvoiceFailed)
+	mux.HandleFunc("POST /v2/invoices/{number}/refund", s.refundInvoice)
+	mux.HandleFunc("PUT /v2/invoices/{number}/void", s.voidInvoice)
+
+	// Transactions
+	mux.HandleFunc("GET /v2/transactions", s.listTransactions)
+	mux.HandleFunc("GET /v2/accounts/{code}/transactions", s.listTransactions)
+	mux.HandleFunc("POST /v2/transactions", s.createTransaction)
+	mux.HandleFunc("GET /v2/transactions/{uuid}", s.getTransaction)
+	mux.HandleFunc("DELETE /v2/transactions/{uuid}", s.refundTransaction)
+
+	// Coupons and redemptions
+	mux.HandleFunc("GET /v2/coupons", s.listCoupons)
+	mux.HandleFunc("POST /v2/coupons", s.createCoupon)
+	mux.HandleFunc("GET /v2/coupons/{code}", s.getCoupon)
+	mux.HandleFunc("DELETE /v2/coupons/{code}", s.deleteCoupon)
+	mux.HandleFunc("POST /v2/coupons/{code}/redeem", s.redeemCoupon)
+	mux.HandleFunc("GET /v2/accounts/{code}/redemption", s.getAccountRedemption)
+	mux.HandleFunc("DELETE /v2/accounts/{code}/redemption", s.deleteRedemption)
+	mux.HandleFunc("GET /v2/invoices/{number}/redemption", s.getInvoiceRedemption)
+}
+
+// HandleFunc registers the handler for the pattern.
+func (mux *router) HandleFunc(pattern string, handler http.HandlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	*mux = append(*mux, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// ServeHTTP calls the handler of the first route matching the request.
+func (mux *router) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, route := range *mux {
+		if route.method == r.Method && route.match(r, segments) {
+			route.handler(rw, r)
+			return
+		}
+	}
+
+	writeXML(rw, http.StatusNotFound, errorXML{Symbol: "not_found", Description: "The requested URL was not found"})
+}
+
+// match reports whether the path segments match the route, setting the path
+// values on r if they do.
+func (route route) match(r *http.Request, segments []string) bool {
+	if len(segments) != len(route.segments) {
+		return false
+	}
+
+	values := make(map[string]string)
+	for i, v := range route.segments {
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			values[v[1:len(v)-1]] = segments[i]
+		} else if v != segments[i] {
+			return false
+		}
+	}
+
+	for k, v := range values {
+		r.SetPathValue(k, v)
+	}
+	return true
+}
+
+// newID returns a new 32 character hex identifier. IDs are sequential so
+// tests are deterministic.
+func (s *Server) newID() string {
+	s.seq++
+	return fmt.Sprintf("%032x", s.seq)
+}
+
+// now returns the current time in UTC.
+func (s *Server) now() recurly.NullTime {
+	return recurly.NewTime(s.Now().Truncate(time.Second))
+}
+
+// link returns the API URL for a resource path.
+func (s *Server) link(format string, args ...interface{}) link {
+	return link{HREF: s.URL + "/v2/" + fmt.Sprintf(format, args...)}
+}
+
+// decode reads the XML request body into v. It writes a 400 response and
+// returns false if the body can't be decoded.
+func decode(rw http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := xml.NewDecoder(r.Body).Decode(v); err != nil {
+		writeXML(rw, http.StatusBadRequest, errorXML{Symbol: "invalid_xml", Description: err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeXML writes v as the XML response body with the given status code.
+func writeXML(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	rw.WriteHeader(status)
+	fmt.Fprint(rw, xml.Header)
+	xml.NewEncoder(rw).Encode(v)
+}
+
+// notFound writes a 404 response for a missing resource.
+func notFound(rw http.ResponseWriter, resource string, field string, value string) {
+	writeXML(rw, http.StatusNotFound, errorXML{
+		Symbol:      "not_found",
+		Description: fmt.Sprintf("Couldn't find %s with %s = %s", resource, field, value),
+	})
+}
+
+// invalid writes a 422 response with a single validation error.
+func invalid(rw http.ResponseWriter, field string, symbol string, message string) {
+	writeXML(rw, 422, errorsXML{Errors: []recurly.Error{
+		recurly.Error{Field: field, Symbol: symbol, Message: message},
+	}})
+}
+
+// paginate returns the range of n items to list for the request, based on
+// the per_page and cursor params. It sets the X-Records and Link headers.
+// Cursors are offsets into the list.
+func paginate(rw http.ResponseWriter, r *http.Request, n int) (int, int) {
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 50
+	}
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+	if start < 0 || start > n {
+		start = n
+	}
+
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+
+	rw.Header().Set("X-Records", strconv.Itoa(n))
+	if end < n {
+		rw.Header().Set("Link", fmt.Sprintf(`<http://%s%s?cursor=%d>; rel="next"`, r.Host, r.URL.Path, end))
+	}
+
+	return start, end
+}
+
+// merge copies every non-zero field of src into dst, which must be a pointer
+// to a struct of the same type. It is used to apply partial updates.
+func merge(dst interface{}, src interface{}) {
+	d := reflect.ValueOf(dst).Elem()
+	v := reflect.ValueOf(src)
+	for i := 0; i < v.NumField(); i++ {
+		if d.Type().Field(i).Name == "XMLName" || v.Field(i).IsZero() {
+			continue
+		}
+		d.Field(i).Set(v.Field(i))
+	}
+}
@@ -0,0 +1,302 @@
+package recurlytest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var now = time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newServer starts a server with a fixed time and a "gold" plan billed at
+// $10 a month.
+func newServer(t *testing.T) (*Server, *recurly.Client) {
+	srv := NewServer()
+	srv.Now = func() time.Time { return now }
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	if _, _, err := client.Plans.Create(recurly.Plan{
+		Code:              "gold",
+		Name:              "Gold",
+		UnitAmountInCents: recurly.UnitAmount{USD: 1000},
+	}); err != nil {
+		t.Fatalf("Error creating plan: %s", err)
+	}
+
+	return srv, client
+}
+
+func TestAccounts(t *testing.T) {
+	_, client := newServer(t)
+
+	r, a, err := client.Accounts.Create(recurly.Account{Code: "1", Email: "verena@example.com", FirstName: "Verena"})
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestAccounts Error: Expected account to be created, given %v (%+v)", err, r)
+	}
+
+	if a.State != "active" || a.HostedLoginToken == "" || !a.CreatedAt.Equal(now) {
+		t.Errorf("TestAccounts Error: Expected active account with login token created at %s, given %#v", now, a)
+	}
+
+	if r, _, _ := client.Accounts.Create(recurly.Account{Code: "1"}); r.StatusCode != 422 || r.Errors[0].Symbol != "taken" {
+		t.Errorf("TestAccounts Error: Expected duplicate account code to be taken, given %+v", r)
+	}
+
+	if _, _, err := client.Accounts.Update("1", recurly.Account{LastName: "Example"}); err != nil {
+		t.Fatalf("TestAccounts Error: Error updating account. Err: %s", err)
+	}
+
+	_, a, _ = client.Accounts.Get("1")
+	if a.FirstName != "Verena" || a.LastName != "Example" || a.Email != "verena@example.com" {
+		t.Errorf("TestAccounts Error: Expected update to keep unchanged fields, given %#v", a)
+	}
+
+	if _, err := client.Accounts.Close("1"); err != nil {
+		t.Fatalf("TestAccounts Error: Error closing account. Err: %s", err)
+	}
+
+	_, accounts, _ := client.Accounts.List(recurly.Params{"state": "closed"})
+	if len(accounts) != 1 || accounts[0].Code != "1" {
+		t.Errorf("TestAccounts Error: Expected one closed account, given %#v", accounts)
+	}
+}
+
+func TestAccountNotes(t *testing.T) {
+	srv, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+	client.Accounts.Create(recurly.Account{Code: "2"})
+
+	srv.AddNote("1", "Called about an upgrade.")
+	srv.AddNote("2", "Prefers email.")
+	srv.AddNote("1", "Upgraded to platinum.")
+
+	_, notes, err := client.Accounts.ListNotes("1")
+	if err != nil {
+		t.Fatalf("TestAccountNotes Error: Error listing notes. Err: %s", err)
+	} else if len(notes) != 2 || notes[0].Message != "Upgraded to platinum." || notes[1].Account.Code != "1" || !notes[1].CreatedAt.Equal(now) {
+		t.Errorf("TestAccountNotes Error: Expected the account's notes newest first, given %#v", notes)
+	}
+}
+
+func TestAccountBalanceAndAcquisition(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+
+	if _, _, err := client.Adjustments.Create("1", recurly.Adjustment{UnitAmountInCents: 1500, Currency: "USD"}); err != nil {
+		t.Fatalf("TestAccountBalanceAndAcquisition Error: Error creating adjustment. Err: %s", err)
+	}
+
+	_, b, err := client.Accounts.GetBalance("1")
+	if err != nil {
+		t.Fatalf("TestAccountBalanceAndAcquisition Error: Error getting balance. Err: %s", err)
+	} else if v, _ := b.Balance.Get("USD"); v != 1500 || b.PastDue != recurly.NewBool(false) {
+		t.Errorf("TestAccountBalanceAndAcquisition Error: Expected balance of 1500 USD not past due, given %#v", b)
+	}
+
+	if r, _, _ := client.Accounts.GetAcquisition("1"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestAccountBalanceAndAcquisition Error: Expected no acquisition data, given %+v", r)
+	}
+
+	r, acq, err := client.Accounts.CreateAcquisition("1", recurly.AccountAcquisition{CostInCents: 199, Currency: "USD", Channel: recurly.AcquisitionChannelBlog})
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestAccountBalanceAndAcquisition Error: Expected acquisition to be created, given %v (%+v)", err, r)
+	} else if !acq.CreatedAt.Equal(now) {
+		t.Errorf("TestAccountBalanceAndAcquisition Error: Expected acquisition created at %s, given %#v", now, acq)
+	}
+
+	client.Accounts.UpdateAcquisition("1", recurly.AccountAcquisition{Campaign: "spring"})
+	_, acq, _ = client.Accounts.GetAcquisition("1")
+	if acq.Channel != recurly.AcquisitionChannelBlog || acq.Campaign != "spring" || acq.CostInCents != 199 {
+		t.Errorf("TestAccountBalanceAndAcquisition Error: Expected update to keep unchanged fields, given %#v", acq)
+	}
+
+	if _, err := client.Accounts.DeleteAcquisition("1"); err != nil {
+		t.Fatalf("TestAccountBalanceAndAcquisition Error: Error deleting acquisition. Err: %s", err)
+	}
+
+	if r, _, _ := client.Accounts.GetAcquisition("1"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestAccountBalanceAndAcquisition Error: Expected acquisition data to be deleted, given %+v", r)
+	}
+}
+
+func TestChildAccounts(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "reseller"})
+
+	if r, _, _ := client.Accounts.Create(recurly.Account{Code: "1", ParentAccountCode: "missing"}); r.StatusCode != 422 || r.Errors[0].Field != "account.parent_account_code" {
+		t.Errorf("TestChildAccounts Error: Expected unknown parent to be invalid, given %+v", r)
+	}
+
+	_, a, err := client.Accounts.Create(recurly.Account{Code: "1", ParentAccountCode: "reseller"})
+	if err != nil {
+		t.Fatalf("TestChildAccounts Error: Error creating child account. Err: %s", err)
+	} else if a.ParentAccount.Code != "reseller" || a.ParentAccountCode != "" {
+		t.Errorf("TestChildAccounts Error: Expected parent account reseller, given %#v", a)
+	}
+
+	if r, _, _ := client.Accounts.Update("reseller", recurly.Account{ParentAccountCode: "1"}); r.StatusCode != 422 {
+		t.Errorf("TestChildAccounts Error: Expected account to be unable to become its own ancestor, given %+v", r)
+	}
+
+	_, children, err := client.Accounts.ListChildAccounts("reseller", nil)
+	if err != nil {
+		t.Fatalf("TestChildAccounts Error: Error listing child accounts. Err: %s", err)
+	} else if len(children) != 1 || children[0].Code != "1" {
+		t.Errorf("TestChildAccounts Error: Expected one child account, given %#v", children)
+	}
+
+	client.Accounts.Close("1")
+	if _, a, _ = client.Accounts.Get("1"); !a.ClosedAt.Equal(now) {
+		t.Errorf("TestChildAccounts Error: Expected account closed at %s, given %#v", now, a)
+	}
+}
+
+func TestBilling(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+
+	if r, _, _ := client.Billing.Get("1"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestBilling Error: Expected status code %d before billing info is added, given %d", http.StatusNotFound, r.StatusCode)
+	}
+
+	_, b, err := client.Billing.Create("1", recurly.Billing{FirstName: "Verena", Number: "4111111111111111", Month: 10, Year: 2020, VerificationValue: "111"})
+	if err != nil {
+		t.Fatalf("TestBilling Error: Error creating billing info. Err: %s", err)
+	}
+
+	if b.Type() != "card" || b.FirstSix != "411111" || b.LastFour != "1111" || b.CardType != "Visa" || b.Number != "" || b.VerificationValue != "" {
+		t.Errorf("TestBilling Error: Expected masked Visa card, given %#v", b)
+	}
+
+	_, b, _ = client.Billing.UpdateWithToken("1", "tok")
+	if b.Type() != "card" || b.Token != "" || b.Year != now.Year()+1 {
+		t.Errorf("TestBilling Error: Expected token to be exchanged for a test card, given %#v", b)
+	}
+
+	if _, err := client.Billing.Clear("1"); err != nil {
+		t.Fatalf("TestBilling Error: Error clearing billing info. Err: %s", err)
+	}
+
+	if r, _, _ := client.Billing.Get("1"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestBilling Error: Expected status code %d after billing info is cleared, given %d", http.StatusNotFound, r.StatusCode)
+	}
+}
+
+func TestPlansAndAddOns(t *testing.T) {
+	_, client := newServer(t)
+
+	if _, _, err := client.AddOns.Create("gold", recurly.AddOn{Code: "ip", Name: "IP Addresses", UnitAmountInCents: recurly.UnitAmount{USD: 200}}); err != nil {
+		t.Fatalf("TestPlansAndAddOns Error: Error creating add on. Err: %s", err)
+	}
+
+	_, addOns, _ := client.AddOns.List("gold", nil)
+	if len(addOns) != 1 || addOns[0].UnitAmountInCents.USD != 200 || addOns[0].DefaultQuantity.Int != 1 {
+		t.Errorf("TestPlansAndAddOns Error: Expected one add on, given %#v", addOns)
+	}
+
+	if r, _, _ := client.AddOns.Get("silver", "ip"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestPlansAndAddOns Error: Expected status code %d for add on of unknown plan, given %d", http.StatusNotFound, r.StatusCode)
+	}
+
+	if _, err := client.Plans.Delete("gold"); err != nil {
+		t.Fatalf("TestPlansAndAddOns Error: Error deleting plan. Err: %s", err)
+	}
+
+	if r, _, _ := client.Plans.Get("gold"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestPlansAndAddOns Error: Expected status code %d for deleted plan, given %d", http.StatusNotFound, r.StatusCode)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	srv, client := newServer(t)
+	client.TypedErrors = true
+
+	srv.FailNext("POST", "accounts", recurly.Error{Field: "account.email", Symbol: "invalid_email", Message: "is not a valid email address"})
+
+	_, _, err := client.Accounts.Create(recurly.Account{Code: "1"})
+	var ve *recurly.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("TestFailNext Error: Expected *recurly.ValidationError, given %#v", err)
+	}
+
+	if len(ve.Errors) != 1 || ve.Errors[0].Field != "account.email" || ve.Errors[0].Symbol != "invalid_email" {
+		t.Errorf("TestFailNext Error: Expected injected validation error, given %#v", ve.Errors)
+	}
+
+	if _, _, err := client.Accounts.Get("1"); err == nil {
+		t.Error("TestFailNext Error: Expected failed request to not create the account")
+	}
+
+	if _, _, err := client.Accounts.Create(recurly.Account{Code: "1"}); err != nil {
+		t.Errorf("TestFailNext Error: Expected only the next request to fail, given %v", err)
+	}
+}
+
+func TestDeclineNext(t *testing.T) {
+	srv, client := newServer(t)
+	client.TypedErrors = true
+
+	srv.DeclineNext("POST", "/subscriptions/", recurly.TransactionError{
+		ErrorCode:       "insufficient_funds",
+		ErrorCategory:   recurly.TransactionErrorCategorySoft,
+		MerchantMessage: "The card has insufficient funds to cover the cost of the transaction.",
+		CustomerMessage: "The card has insufficient funds to cover the cost of the transaction.",
+	})
+
+	_, _, err := client.Subscriptions.Create(signup("1"))
+	var te *recurly.TransactionFailedError
+	if !errors.As(err, &te) {
+		t.Fatalf("TestDeclineNext Error: Expected *recurly.TransactionFailedError, given %#v", err)
+	}
+
+	if !te.IsSoftDecline() || te.ErrorCode != "insufficient_funds" {
+		t.Errorf("TestDeclineNext Error: Expected soft decline, given %#v", te.TransactionError)
+	}
+
+	if len(te.Errors) != 1 || te.Errors[0].Symbol != "insufficient_funds" {
+		t.Errorf("TestDeclineNext Error: Expected insufficient_funds error, given %#v", te.Errors)
+	}
+
+	var nf *recurly.NotFoundError
+	if _, _, err := client.Accounts.Get("1"); !errors.As(err, &nf) {
+		t.Errorf("TestDeclineNext Error: Expected declined subscription to not create the account, given %v", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	_, client := newServer(t)
+	for _, code := range []string{"1", "2", "3", "4", "5"} {
+		client.Accounts.Create(recurly.Account{Code: code})
+	}
+
+	r, accounts, _ := client.Accounts.List(recurly.Params{"per_page": 2})
+	if len(accounts) != 2 || r.TotalRecords() != 5 || r.Next() != "2" {
+		t.Errorf("TestPagination Error: Expected first page of 2 out of 5 accounts with next cursor 2, given %d out of %d with next cursor %q", len(accounts), r.TotalRecords(), r.Next())
+	}
+
+	var codes []string
+	pager := client.Accounts.ListPager(recurly.Params{"per_page": 2})
+	for pager.Next(context.Background()) {
+		codes = append(codes, pager.Value().Code)
+	}
+
+	if pager.Err() != nil || !reflect.DeepEqual(codes, []string{"1", "2", "3", "4", "5"}) {
+		t.Errorf("TestPagination Error: Expected pager to walk every account, given %v (%v)", codes, pager.Err())
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	srv, _ := newServer(t)
+
+	client := recurly.NewClient("test", "", nil)
+	client.BaseURL = srv.URL + "/"
+	if r, _, _ := client.Accounts.List(nil); r.StatusCode != http.StatusUnauthorized {
+		t.Errorf("TestUnauthorized Error: Expected status code %d without an API key, given %d", http.StatusUnauthorized, r.StatusCode)
+	}
+}
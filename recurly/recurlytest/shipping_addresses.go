@@ -0,0 +1,156 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// shippingAddress returns the shipping address with the given ID on the
+// account, or nil.
+func (s *Server) shippingAddress(accountCode string, id int) *recurly.ShippingAddress {
+	for _, a := range s.shippingAddresses {
+		if a.ID == id && a.Account.Code == accountCode {
+			return a
+		}
+	}
+	return nil
+}
+
+// findShippingAddress returns the shipping address named in the request
+// path. It writes a 404 response and returns nil if the account or shipping
+// address does not exist.
+func (s *Server) findShippingAddress(rw http.ResponseWriter, r *http.Request) *recurly.ShippingAddress {
+	acct := s.findAccount(rw, r)
+	if acct == nil {
+		return nil
+	}
+
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	a := s.shippingAddress(acct.Code, id)
+	if a == nil {
+		notFound(rw, "ShippingAddress", "id", r.PathValue("id"))
+	}
+	return a
+}
+
+// validShippingAddress checks that a new shipping address has the fields
+// Recurly requires. Validation errors are reported on fields under prefix,
+// such as "shipping_address". It writes a 422 response and returns false if
+// the address is invalid.
+func validShippingAddress(rw http.ResponseWriter, prefix string, a recurly.ShippingAddress) bool {
+	required := []struct{ field, value string }{
+		{"first_name", a.FirstName},
+		{"last_name", a.LastName},
+		{"address1", a.Address},
+		{"city", a.City},
+		{"zip", a.Zip},
+		{"country", a.Country},
+	}
+	for _, v := range required {
+		if v.value == "" {
+			invalid(rw, prefix+"."+v.field, "blank", "can't be blank")
+			return false
+		}
+	}
+	return true
+}
+
+// addShippingAddress stores a new shipping address on the account.
+func (s *Server) addShippingAddress(accountCode string, a recurly.ShippingAddress) *recurly.ShippingAddress {
+	s.seq++
+	a.XMLName.Local = "shipping_address"
+	a.Account.Code = accountCode
+	a.ID = s.seq
+	a.CreatedAt = s.now()
+	a.UpdatedAt = a.CreatedAt
+	s.shippingAddresses = append(s.shippingAddresses, &a)
+
+	return &a
+}
+
+// validShipping checks the shipping address given with a purchase: either
+// the ID of one of the account's shipping addresses, or a new address. It
+// writes a 422 response and returns false if it is invalid.
+func (s *Server) validShipping(rw http.ResponseWriter, prefix string, accountCode string, id int, a *recurly.ShippingAddress) bool {
+	if id != 0 && s.shippingAddress(accountCode, id) == nil {
+		invalid(rw, prefix+".shipping_address_id", "invalid", "is invalid")
+		return false
+	}
+	return a == nil || validShippingAddress(rw, prefix+".shipping_address", *a)
+}
+
+func (s *Server) listShippingAddresses(rw http.ResponseWriter, r *http.Request) {
+	acct := s.findAccount(rw, r)
+	if acct == nil {
+		return
+	}
+
+	var addresses []shippingAddressXML
+	for _, a := range s.shippingAddresses {
+		if a.Account.Code == acct.Code {
+			addresses = append(addresses, s.shippingAddressXML(a))
+		}
+	}
+
+	start, end := paginate(rw, r, len(addresses))
+	writeXML(rw, http.StatusOK, shippingAddressesXML{ShippingAddresses: addresses[start:end]})
+}
+
+func (s *Server) createShippingAddress(rw http.ResponseWriter, r *http.Request) {
+	acct := s.findAccount(rw, r)
+	if acct == nil {
+		return
+	}
+
+	var a recurly.ShippingAddress
+	if !decode(rw, r, &a) {
+		return
+	}
+
+	if validShippingAddress(rw, "shipping_address", a) {
+		writeXML(rw, http.StatusCreated, s.shippingAddressXML(s.addShippingAddress(acct.Code, a)))
+	}
+}
+
+func (s *Server) getShippingAddress(rw http.ResponseWriter, r *http.Request) {
+	if a := s.findShippingAddress(rw, r); a != nil {
+		writeXML(rw, http.StatusOK, s.shippingAddressXML(a))
+	}
+}
+
+func (s *Server) updateShippingAddress(rw http.ResponseWriter, r *http.Request) {
+	a := s.findShippingAddress(rw, r)
+	if a == nil {
+		return
+	}
+
+	var update recurly.ShippingAddress
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	// The ID, account and timestamps can't be updated.
+	update.ID, update.CreatedAt, update.UpdatedAt = 0, recurly.NullTime{}, recurly.NullTime{}
+	merge(a, update)
+	a.UpdatedAt = s.now()
+
+	writeXML(rw, http.StatusOK, s.shippingAddressXML(a))
+}
+
+func (s *Server) deleteShippingAddress(rw http.ResponseWriter, r *http.Request) {
+	a := s.findShippingAddress(rw, r)
+	if a == nil {
+		return
+	}
+
+	for i, v := range s.shippingAddresses {
+		if v == a {
+			s.shippingAddresses = append(s.shippingAddresses[:i], s.shippingAddresses[i+1:]...)
+			break
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
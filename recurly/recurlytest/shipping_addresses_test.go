@@ -0,0 +1,77 @@
+package recurlytest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+var home = recurly.ShippingAddress{
+	Nickname:  "Home",
+	FirstName: "Verena",
+	LastName:  "Example",
+	Address:   "123 Main St.",
+	City:      "San Francisco",
+	Zip:       "94105",
+	Country:   "US",
+}
+
+func TestShippingAddresses(t *testing.T) {
+	_, client := newServer(t)
+	client.Accounts.Create(recurly.Account{Code: "1"})
+
+	if r, _, _ := client.ShippingAddresses.Create("1", recurly.ShippingAddress{Nickname: "Home"}); r.StatusCode != 422 || r.Errors[0].Field != "shipping_address.first_name" {
+		t.Errorf("TestShippingAddresses Error: Expected first name to be required, given %+v", r)
+	}
+
+	r, a, err := client.ShippingAddresses.Create("1", home)
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestShippingAddresses Error: Expected shipping address to be created, given %v (%+v)", err, r)
+	} else if a.ID == 0 || a.Account.Code != "1" || !a.CreatedAt.Equal(now) {
+		t.Errorf("TestShippingAddresses Error: Expected shipping address with an ID on account 1, given %#v", a)
+	}
+
+	if _, _, err := client.ShippingAddresses.Update("1", a.ID, recurly.ShippingAddress{Address2: "Apt 2"}); err != nil {
+		t.Fatalf("TestShippingAddresses Error: Error updating shipping address. Err: %s", err)
+	}
+
+	_, a, _ = client.ShippingAddresses.Get("1", a.ID)
+	if a.Address != "123 Main St." || a.Address2 != "Apt 2" {
+		t.Errorf("TestShippingAddresses Error: Expected update to keep unchanged fields, given %#v", a)
+	}
+
+	if _, err := client.ShippingAddresses.Delete("1", a.ID); err != nil {
+		t.Fatalf("TestShippingAddresses Error: Error deleting shipping address. Err: %s", err)
+	}
+
+	if r, _, _ := client.ShippingAddresses.Get("1", a.ID); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestShippingAddresses Error: Expected shipping address to be deleted, given %+v", r)
+	}
+}
+
+func TestSubscriptionShippingAddress(t *testing.T) {
+	_, client := newServer(t)
+
+	ns := signup("1")
+	ns.ShippingAddressID = 12345
+	if r, _, _ := client.Subscriptions.Create(ns); r.StatusCode != 422 || r.Errors[0].Field != "subscription.shipping_address_id" {
+		t.Errorf("TestSubscriptionShippingAddress Error: Expected unknown shipping address to be invalid, given %+v", r)
+	}
+
+	ns = signup("1")
+	ns.ShippingAddress = &home
+	if _, _, err := client.Subscriptions.Create(ns); err != nil {
+		t.Fatalf("TestSubscriptionShippingAddress Error: Error creating subscription. Err: %s", err)
+	}
+
+	_, addresses, _ := client.ShippingAddresses.List("1", nil)
+	if len(addresses) != 1 || addresses[0].Nickname != "Home" {
+		t.Fatalf("TestSubscriptionShippingAddress Error: Expected new shipping address to be added to the account, given %#v", addresses)
+	}
+
+	nt := recurly.NewTransaction{AmountInCents: 500, Currency: "USD", Account: recurly.Account{Code: "1"}, ShippingAddressID: addresses[0].ID}
+	if _, _, err := client.Transactions.Create(nt); err != nil {
+		t.Errorf("TestSubscriptionShippingAddress Error: Expected transaction to ship to existing address. Err: %s", err)
+	}
+}
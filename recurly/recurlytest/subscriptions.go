@@ -0,0 +1,621 @@
+package recurlytest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// subscription returns the subscription with the given UUID, or nil.
+func (s *Server) subscription(uuid string) *recurly.Subscription {
+	for _, sub := range s.subscriptions {
+		if sub.UUID == uuid {
+			return sub
+		}
+	}
+	return nil
+}
+
+// findSubscription returns the subscription named in the request path. It
+// writes a 404 response and returns nil if the subscription does not exist.
+func (s *Server) findSubscription(rw http.ResponseWriter, r *http.Request) *recurly.Subscription {
+	uuid := r.PathValue("uuid")
+	sub := s.subscription(uuid)
+	if sub == nil {
+		notFound(rw, "Subscription", "uuid", uuid)
+		return nil
+	}
+
+	s.settle(sub)
+	return sub
+}
+
+// settle starts and ends scheduled pauses whose time has come. The fake
+// doesn't renew subscriptions, so this is the only change that happens
+// with the passing of time.
+func (s *Server) settle(sub *recurly.Subscription) {
+	now := s.Now()
+	if sub.State == recurly.SubscriptionStateActive && sub.PausedAt.Time != nil && !sub.PausedAt.After(now) {
+		sub.State = recurly.SubscriptionStatePaused
+	}
+	if sub.State == recurly.SubscriptionStatePaused && sub.ResumeAt.Time != nil && !sub.ResumeAt.After(now) {
+		s.resume(sub, *sub.ResumeAt.Time)
+	}
+}
+
+// resume makes a paused subscription active again, starting a new billing
+// cycle at t.
+func (s *Server) resume(sub *recurly.Subscription, t time.Time) {
+	sub.State = recurly.SubscriptionStateActive
+	sub.PausedAt, sub.ResumeAt = recurly.NullTime{}, recurly.NullTime{}
+	sub.RemainingPauseCycles = 0
+	sub.CurrentPeriodStartedAt = recurly.NewTime(t)
+	if plan := s.plan(sub.Plan.Code); plan != nil {
+		sub.CurrentPeriodEndsAt = recurly.NewTime(addInterval(t, plan.IntervalUnit, plan.IntervalLength))
+	}
+}
+
+// addInterval returns t moved forward by length units, where unit is "days"
+// or "months".
+func addInterval(t time.Time, unit string, length int) time.Time {
+	if unit == "days" {
+		return t.AddDate(0, 0, length)
+	}
+	return t.AddDate(0, length, 0)
+}
+
+// newSubscription validates ns and returns the subscription it describes
+// along with the charges for its first invoice. Nothing is stored, so
+// previews can use it too. It writes a 422 response and returns nil if the
+// subscription is invalid.
+func (s *Server) newSubscription(rw http.ResponseWriter, ns recurly.NewSubscription) (*recurly.Subscription, []*recurly.Adjustment) {
+	plan := s.plan(ns.PlanCode)
+	if plan == nil {
+		invalid(rw, "subscription.plan_code", "invalid", "is invalid")
+		return nil, nil
+	} else if ns.Currency == "" {
+		invalid(rw, "subscription.currency", "blank", "can't be blank")
+		return nil, nil
+	} else if ns.Account.Code == "" {
+		invalid(rw, "subscription.account.account_code", "blank", "can't be blank")
+		return nil, nil
+	}
+
+	if !s.validShipping(rw, "subscription", ns.Account.Code, ns.ShippingAddressID, ns.ShippingAddress) {
+		return nil, nil
+	}
+
+	var coupon *recurly.Coupon
+	if ns.CouponCode != "" {
+		if coupon = s.coupon(ns.CouponCode); coupon == nil || coupon.State != "redeemable" {
+			invalid(rw, "subscription.coupon_code", "invalid", "is invalid")
+			return nil, nil
+		}
+	}
+
+	now := *s.now().Time
+	sub := &recurly.Subscription{
+		UUID:              s.newID(),
+		State:             recurly.SubscriptionStateActive,
+		UnitAmountInCents: ns.UnitAmountInCents,
+		Currency:          ns.Currency,
+		Quantity:          ns.Quantity,
+		PONumber:          ns.PONumber,
+		NetTerms:          ns.NetTerms,
+
+		TermsAndConditions:    ns.TermsAndConditions,
+		CustomerNotes:         ns.CustomerNotes,
+		VATReverseChargeNotes: ns.VATReverseChargeNotes,
+	}
+	sub.XMLName.Local = "subscription"
+	sub.Plan.Code, sub.Plan.Name = plan.Code, plan.Name
+	sub.Account.Code = ns.Account.Code
+	if sub.UnitAmountInCents == 0 {
+		sub.UnitAmountInCents = unitAmount(plan.UnitAmountInCents, ns.Currency)
+	}
+	if sub.Quantity == 0 {
+		sub.Quantity = 1
+	}
+
+	if ns.SubscriptionAddOns != nil {
+		for _, v := range *ns.SubscriptionAddOns {
+			a := s.addOn(plan.Code, v.Code)
+			if a == nil {
+				invalid(rw, "subscription.subscription_add_ons.add_on_code", "invalid", "is invalid")
+				return nil, nil
+			}
+
+			if v.UnitAmountInCents == 0 {
+				v.UnitAmountInCents = unitAmount(a.UnitAmountInCents, ns.Currency)
+			}
+			if v.UsagePercentage == "" {
+				v.UsagePercentage = a.UsagePercentage
+			}
+			if v.Quantity == 0 {
+				v.Quantity = 1
+			}
+			sub.SubscriptionAddOns = append(sub.SubscriptionAddOns, v)
+		}
+	}
+
+	start := now
+	if ns.StartsAt.Time != nil && ns.StartsAt.After(now) {
+		start = *ns.StartsAt.Time
+		sub.State = recurly.SubscriptionStateFuture
+	} else {
+		sub.ActivatedAt = recurly.NewTime(now)
+	}
+	sub.CurrentPeriodStartedAt = recurly.NewTime(start)
+
+	trial := ns.TrialEndsAt.Time != nil || plan.TrialIntervalLength > 0
+	if trial {
+		sub.TrialStartedAt = recurly.NewTime(start)
+		sub.TrialEndsAt = ns.TrialEndsAt
+		if ns.TrialEndsAt.Time == nil {
+			sub.TrialEndsAt = recurly.NewTime(addInterval(start, plan.TrialIntervalUnit, plan.TrialIntervalLength))
+		}
+		sub.CurrentPeriodEndsAt = sub.TrialEndsAt
+	} else {
+		sub.CurrentPeriodEndsAt = recurly.NewTime(addInterval(start, plan.IntervalUnit, plan.IntervalLength))
+	}
+	if ns.FirstRenewalDate.Time != nil {
+		sub.CurrentPeriodEndsAt = ns.FirstRenewalDate
+	}
+
+	if sub.State == recurly.SubscriptionStateFuture {
+		return sub, nil
+	}
+
+	var charges []*recurly.Adjustment
+	charge := func(description string, origin string, amount int, quantity int) {
+		if amount <= 0 {
+			return
+		}
+
+		a := &recurly.Adjustment{
+			UUID:              s.newID(),
+			Description:       description,
+			Origin:            origin,
+			UnitAmountInCents: amount,
+			Quantity:          quantity,
+			TotalInCents:      amount * quantity,
+			Currency:          ns.Currency,
+			StartDate:         sub.CurrentPeriodStartedAt,
+			EndDate:           sub.CurrentPeriodEndsAt,
+			CreatedAt:         recurly.NewTime(now),
+		}
+		a.XMLName.Local = "adjustment"
+		a.Account.Code = ns.Account.Code
+		charges = append(charges, a)
+	}
+
+	// Trials only charge the setup fee up front.
+	charge(plan.Name+" Setup Fee", "setup_fee", unitAmount(plan.SetupFeeInCents, ns.Currency), 1)
+	if !trial {
+		charge(plan.Name, "plan", sub.UnitAmountInCents, sub.Quantity)
+		for _, v := range sub.SubscriptionAddOns {
+			// Usage add ons are billed for the usage logged against them.
+			if a := s.addOn(plan.Code, v.Code); a.AddOnType != recurly.AddOnTypeUsage {
+				charge(v.Code, "add_on", v.UnitAmountInCents, v.Quantity)
+			}
+		}
+	}
+
+	if coupon != nil {
+		discount(coupon, charges)
+	}
+
+	return sub, charges
+}
+
+// subscribe validates and stores a new subscription, creating the account
+// and billing info given with it if needed, and invoicing the first charges.
+// It writes a 422 response and returns nil if the subscription is invalid.
+func (s *Server) subscribe(rw http.ResponseWriter, ns recurly.NewSubscription) *recurly.Subscription {
+	sub, charges := s.newSubscription(rw, ns)
+	if sub == nil {
+		return nil
+	}
+
+	manual := ns.CollectionMethod == "manual"
+	_, hasBilling := s.billing[ns.Account.Code]
+	if !manual && !hasBilling && ns.Account.BillingInfo == nil && total(charges) > 0 {
+		invalid(rw, "subscription.account.billing_info", "blank", "can't be blank")
+		return nil
+	}
+
+	if a := s.account(ns.Account.Code); a == nil {
+		if s.addAccount(rw, ns.Account) == nil {
+			return nil
+		}
+	} else if ns.Account.BillingInfo != nil {
+		s.billing[a.Code] = s.newBilling(*ns.Account.BillingInfo)
+	}
+
+	if ns.ShippingAddress != nil {
+		s.addShippingAddress(ns.Account.Code, *ns.ShippingAddress)
+	}
+
+	var redeemed *redemption
+	if ns.CouponCode != "" {
+		redeemed = s.redeem(s.coupon(ns.CouponCode), ns.Account.Code, ns.Currency)
+	}
+
+	s.adjustments = append(s.adjustments, charges...)
+	if len(charges) > 0 {
+		inv := s.invoice(ns.Account.Code, charges, manual, ns.NetTerms, ns.PONumber)
+		if redeemed != nil {
+			redeemed.invoiceNumber = inv.InvoiceNumber
+			for _, a := range charges {
+				redeemed.TotalDiscountedInCents += a.DiscountInCents
+			}
+		}
+		inv.Subscription.Code = sub.UUID
+		sub.Invoice.Code = strconv.Itoa(inv.InvoiceNumber)
+		for _, t := range s.transactions {
+			if t.Invoice.Code == sub.Invoice.Code {
+				t.Subscription.Code = sub.UUID
+				t.Source = "subscription"
+				t.Recurring = recurly.NewBool(false)
+			}
+		}
+	}
+
+	s.subscriptions = append(s.subscriptions, sub)
+
+	return sub
+}
+
+// cancel cancels an active subscription at the end of its current term.
+func (s *Server) cancel(sub *recurly.Subscription) {
+	sub.State = recurly.SubscriptionStateCanceled
+	sub.CanceledAt = s.now()
+	sub.ExpiresAt = sub.CurrentPeriodEndsAt
+}
+
+func (s *Server) listSubscriptions(rw http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code != "" && s.findAccount(rw, r) == nil {
+		return
+	}
+
+	var subs []subscriptionXML
+	state := r.URL.Query().Get("state")
+	for _, sub := range s.subscriptions {
+		if code != "" && sub.Account.Code != code {
+			continue
+		}
+		s.settle(sub)
+
+		switch state {
+		case "", recurly.SubscriptionStateLive:
+			if state != "" && sub.State == recurly.SubscriptionStateExpired {
+				continue
+			}
+		case recurly.SubscriptionStateInTrial:
+			if sub.TrialEndsAt.Time == nil || !sub.TrialEndsAt.After(s.Now()) {
+				continue
+			}
+		default:
+			if sub.State != state {
+				continue
+			}
+		}
+
+		subs = append(subs, s.subscriptionXML(sub))
+	}
+
+	start, end := paginate(rw, r, len(subs))
+	writeXML(rw, http.StatusOK, subscriptionsXML{Subscriptions: subs[start:end]})
+}
+
+func (s *Server) createSubscription(rw http.ResponseWriter, r *http.Request) {
+	var ns recurly.NewSubscription
+	if !decode(rw, r, &ns) {
+		return
+	}
+
+	if sub := s.subscribe(rw, ns); sub != nil {
+		writeXML(rw, http.StatusCreated, s.subscriptionXML(sub))
+	}
+}
+
+func (s *Server) previewSubscription(rw http.ResponseWriter, r *http.Request) {
+	var ns recurly.NewSubscription
+	if !decode(rw, r, &ns) {
+		return
+	}
+
+	if sub, _ := s.newSubscription(rw, ns); sub != nil {
+		writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+	}
+}
+
+func (s *Server) getSubscription(rw http.ResponseWriter, r *http.Request) {
+	if sub := s.findSubscription(rw, r); sub != nil {
+		writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+	}
+}
+
+// updateSubscriptionNotes replaces the notes that are given, leaving the
+// others unchanged.
+func (s *Server) updateSubscriptionNotes(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var n recurly.SubscriptionNotes
+	if !decode(rw, r, &n) {
+		return
+	}
+
+	if n.TermsAndConditions != "" {
+		sub.TermsAndConditions = n.TermsAndConditions
+	}
+	if n.CustomerNotes != "" {
+		sub.CustomerNotes = n.CustomerNotes
+	}
+	if n.VATReverseChargeNotes != "" {
+		sub.VATReverseChargeNotes = n.VATReverseChargeNotes
+	}
+
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+// change applies an update to a copy of sub and returns it. It writes a 422
+// response and returns nil if the update is invalid.
+func (s *Server) change(rw http.ResponseWriter, sub *recurly.Subscription, u recurly.UpdateSubscription) *recurly.Subscription {
+	if sub.State == recurly.SubscriptionStateExpired {
+		invalid(rw, "subscription.base", "invalid_state", "is expired and can't be changed")
+		return nil
+	}
+
+	changed := *sub
+	if u.PlanCode != "" && u.PlanCode != sub.Plan.Code {
+		plan := s.plan(u.PlanCode)
+		if plan == nil {
+			invalid(rw, "subscription.plan_code", "invalid", "is invalid")
+			return nil
+		}
+
+		changed.Plan.Code, changed.Plan.Name = plan.Code, plan.Name
+		changed.UnitAmountInCents = unitAmount(plan.UnitAmountInCents, sub.Currency)
+	}
+
+	if u.UnitAmountInCents > 0 {
+		changed.UnitAmountInCents = u.UnitAmountInCents
+	}
+	if u.Quantity > 0 {
+		changed.Quantity = u.Quantity
+	}
+	if u.NetTerms.Valid {
+		changed.NetTerms = u.NetTerms
+	}
+	if u.PONumber != "" {
+		changed.PONumber = u.PONumber
+	}
+
+	if u.SubscriptionAddOns != nil {
+		changed.SubscriptionAddOns = nil
+		for _, v := range *u.SubscriptionAddOns {
+			a := s.addOn(changed.Plan.Code, v.Code)
+			if a == nil {
+				invalid(rw, "subscription.subscription_add_ons.add_on_code", "invalid", "is invalid")
+				return nil
+			}
+
+			if v.UnitAmountInCents == 0 {
+				v.UnitAmountInCents = unitAmount(a.UnitAmountInCents, sub.Currency)
+			}
+			if v.UsagePercentage == "" {
+				v.UsagePercentage = a.UsagePercentage
+			}
+			if v.Quantity == 0 {
+				v.Quantity = 1
+			}
+			changed.SubscriptionAddOns = append(changed.SubscriptionAddOns, v)
+		}
+	}
+
+	return &changed
+}
+
+func (s *Server) updateSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var u recurly.UpdateSubscription
+	if !decode(rw, r, &u) {
+		return
+	}
+
+	changed := s.change(rw, sub, u)
+	if changed == nil {
+		return
+	}
+
+	// Changes that take effect at renewal leave the current subscription
+	// as it is, and replace any change already pending.
+	if u.Timeframe == "renewal" {
+		sub.PendingSubscription = &recurly.PendingSubscription{
+			XMLName:            xml.Name{Local: "pending_subscription"},
+			UnitAmountInCents:  changed.UnitAmountInCents,
+			Quantity:           changed.Quantity,
+			SubscriptionAddOns: changed.SubscriptionAddOns,
+		}
+		sub.PendingSubscription.Plan.Code = changed.Plan.Code
+		sub.PendingSubscription.Plan.Name = changed.Plan.Name
+	} else {
+		*sub = *changed
+		sub.PendingSubscription = nil
+	}
+
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) previewSubscriptionChange(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var u recurly.UpdateSubscription
+	if !decode(rw, r, &u) {
+		return
+	}
+
+	if changed := s.change(rw, sub, u); changed != nil {
+		writeXML(rw, http.StatusOK, s.subscriptionXML(changed))
+	}
+}
+
+func (s *Server) cancelSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	if sub.State != recurly.SubscriptionStateActive {
+		invalid(rw, "subscription.base", "invalid_state", "is not active and can't be canceled")
+		return
+	}
+
+	s.cancel(sub)
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) reactivateSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	if sub.State != recurly.SubscriptionStateCanceled {
+		invalid(rw, "subscription.base", "invalid_state", "is not canceled and can't be reactivated")
+		return
+	}
+
+	sub.State = recurly.SubscriptionStateActive
+	sub.CanceledAt = recurly.NullTime{}
+	sub.ExpiresAt = recurly.NullTime{}
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) terminateSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	if sub.State == recurly.SubscriptionStateExpired {
+		invalid(rw, "subscription.base", "invalid_state", "is already expired")
+		return
+	}
+
+	now := s.now()
+	sub.State = recurly.SubscriptionStateExpired
+	if sub.CanceledAt.Time == nil {
+		sub.CanceledAt = now
+	}
+	sub.ExpiresAt = now
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) postponeSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	t, err := time.Parse(time.RFC3339, r.URL.Query().Get("next_renewal_date"))
+	if err != nil {
+		invalid(rw, "subscription.next_renewal_date", "invalid", "is invalid")
+		return
+	}
+
+	if sub.TrialEndsAt.Time != nil && sub.TrialEndsAt.After(s.Now()) {
+		sub.TrialEndsAt = recurly.NewTime(t)
+	}
+	sub.CurrentPeriodEndsAt = recurly.NewTime(t)
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+// pauseSubscription schedules a pause at the end of the current billing
+// cycle, changes the cycles left on a paused subscription, or removes a
+// scheduled pause when remaining_pause_cycles is 0.
+func (s *Server) pauseSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var v struct {
+		RemainingPauseCycles int `xml:"remaining_pause_cycles"`
+	}
+	if !decode(rw, r, &v) {
+		return
+	}
+
+	switch {
+	case v.RemainingPauseCycles < 0:
+		invalid(rw, "subscription.remaining_pause_cycles", "greater_than_or_equal_to", "must be greater than or equal to 0")
+		return
+	case sub.State == recurly.SubscriptionStatePaused && v.RemainingPauseCycles == 0:
+		invalid(rw, "subscription.base", "invalid_state", "is paused and must be resumed")
+		return
+	case sub.State != recurly.SubscriptionStateActive && sub.State != recurly.SubscriptionStatePaused:
+		invalid(rw, "subscription.base", "invalid_state", "is not active and can't be paused")
+		return
+	case v.RemainingPauseCycles == 0:
+		sub.PausedAt, sub.ResumeAt = recurly.NullTime{}, recurly.NullTime{}
+		sub.RemainingPauseCycles = 0
+		writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+		return
+	}
+
+	if sub.State == recurly.SubscriptionStateActive {
+		sub.PausedAt = sub.CurrentPeriodEndsAt
+	}
+	sub.RemainingPauseCycles = v.RemainingPauseCycles
+	if plan := s.plan(sub.Plan.Code); plan != nil && sub.PausedAt.Time != nil {
+		sub.ResumeAt = recurly.NewTime(addInterval(*sub.PausedAt.Time, plan.IntervalUnit, plan.IntervalLength*v.RemainingPauseCycles))
+	}
+
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) resumeSubscription(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	if sub.State != recurly.SubscriptionStatePaused {
+		invalid(rw, "subscription.base", "invalid_state", "is not paused and can't be resumed")
+		return
+	}
+
+	s.resume(sub, *s.now().Time)
+	writeXML(rw, http.StatusOK, s.subscriptionXML(sub))
+}
+
+func (s *Server) cancelPendingChange(rw http.ResponseWriter, r *http.Request) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return
+	}
+
+	if sub.PendingSubscription == nil {
+		invalid(rw, "subscription.base", "invalid_state", "has no pending changes")
+		return
+	}
+
+	sub.PendingSubscription = nil
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,410 @@
+package recurlytest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// signup returns a gold plan subscription for a new account with a card.
+func signup(code string) recurly.NewSubscription {
+	return recurly.NewSubscription{
+		PlanCode: "gold",
+		Currency: "USD",
+		Account: recurly.Account{
+			Code:  code,
+			Email: "verena@example.com",
+			BillingInfo: &recurly.Billing{
+				FirstName: "Verena",
+				LastName:  "Example",
+				Number:    "4111111111111111",
+				Month:     10,
+				Year:      2020,
+			},
+		},
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	_, client := newServer(t)
+
+	r, sub, err := client.Subscriptions.Create(signup("1"))
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestCreateSubscription Error: Expected subscription to be created, given %v (%+v)", err, r)
+	}
+
+	if sub.State != recurly.SubscriptionStateActive || sub.Plan.Code != "gold" || sub.UnitAmountInCents != 1000 || sub.Quantity != 1 {
+		t.Errorf("TestCreateSubscription Error: Expected active gold subscription for 1000 cents, given %#v", sub)
+	}
+
+	if sub.Account.Code != "1" || sub.Invoice.Code != "1000" {
+		t.Errorf("TestCreateSubscription Error: Expected links to account 1 and invoice 1000, given %#v and %#v", sub.Account, sub.Invoice)
+	}
+
+	if !sub.ActivatedAt.Equal(now) || !sub.CurrentPeriodEndsAt.Equal(now.AddDate(0, 1, 0)) {
+		t.Errorf("TestCreateSubscription Error: Expected one month term from %s, given %s to %s", now, sub.ActivatedAt, sub.CurrentPeriodEndsAt)
+	}
+
+	// The account and billing info given with the subscription are created.
+	if _, a, err := client.Accounts.Get("1"); err != nil || a.Email != "verena@example.com" {
+		t.Errorf("TestCreateSubscription Error: Expected account to be created, given %#v (%v)", a, err)
+	}
+
+	if _, b, err := client.Billing.Get("1"); err != nil || b.LastFour != "1111" {
+		t.Errorf("TestCreateSubscription Error: Expected billing info to be created, given %#v (%v)", b, err)
+	}
+
+	_, subs, _ := client.Subscriptions.ListAccount("1", nil)
+	if len(subs) != 1 || subs[0].UUID != sub.UUID {
+		t.Errorf("TestCreateSubscription Error: Expected account to list the subscription, given %#v", subs)
+	}
+
+	// The first term is invoiced and collected.
+	_, inv, err := client.Invoices.Get(1000)
+	if err != nil {
+		t.Fatalf("TestCreateSubscription Error: Error getting invoice. Err: %s", err)
+	}
+
+	if inv.State != recurly.InvoiceStateCollected || inv.TotalInCents != 1000 || inv.Subscription.Code != sub.UUID {
+		t.Errorf("TestCreateSubscription Error: Expected collected invoice for 1000 cents, given %#v", inv)
+	}
+
+	if len(inv.LineItems) != 1 || inv.LineItems[0].Origin != "plan" || len(inv.Transactions) != 1 {
+		t.Errorf("TestCreateSubscription Error: Expected one plan charge and one transaction, given %#v and %#v", inv.LineItems, inv.Transactions)
+	}
+
+	_, txns, _ := client.Transactions.ListAccount("1", nil)
+	if len(txns) != 1 || txns[0].Status != recurly.TransactionStatusSuccess || txns[0].AmountInCents != 1000 || txns[0].Subscription.Code != sub.UUID {
+		t.Errorf("TestCreateSubscription Error: Expected one successful transaction for the subscription, given %#v", txns)
+	}
+}
+
+func TestCreateSubscriptionErrors(t *testing.T) {
+	_, client := newServer(t)
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"plan": "silver", "currency": "USD", "account": "1", "field": "subscription.plan_code"},
+		map[string]interface{}{"plan": "gold", "currency": "", "account": "1", "field": "subscription.currency"},
+		map[string]interface{}{"plan": "gold", "currency": "USD", "account": "", "field": "subscription.account.account_code"},
+	}
+
+	for _, s := range suite {
+		ns := signup(s["account"].(string))
+		ns.PlanCode = s["plan"].(string)
+		ns.Currency = s["currency"].(string)
+
+		r, _, _ := client.Subscriptions.Create(ns)
+		if r.StatusCode != 422 || len(r.Errors) != 1 || r.Errors[0].Field != s["field"].(string) {
+			t.Errorf("TestCreateSubscriptionErrors Error: Expected validation error for %s, given %d %#v", s["field"], r.StatusCode, r.Errors)
+		}
+	}
+
+	// Automatic collection needs billing info.
+	ns := signup("1")
+	ns.Account.BillingInfo = nil
+	if r, _, _ := client.Subscriptions.Create(ns); r.StatusCode != 422 || r.Errors[0].Field != "subscription.account.billing_info" {
+		t.Errorf("TestCreateSubscriptionErrors Error: Expected billing info to be required, given %d %#v", r.StatusCode, r.Errors)
+	}
+
+	ns.CollectionMethod = "manual"
+	if _, _, err := client.Subscriptions.Create(ns); err != nil {
+		t.Fatalf("TestCreateSubscriptionErrors Error: Error creating manual subscription. Err: %s", err)
+	}
+
+	_, inv, _ := client.Invoices.Get(1000)
+	if inv.State != recurly.InvoiceStateOpen || inv.CollectionMethod != "manual" || len(inv.Transactions) != 0 {
+		t.Errorf("TestCreateSubscriptionErrors Error: Expected open manual invoice without transactions, given %#v", inv)
+	}
+}
+
+func TestSubscriptionTrial(t *testing.T) {
+	_, client := newServer(t)
+	client.Plans.Create(recurly.Plan{
+		Code:                "trial",
+		Name:                "Trial",
+		TrialIntervalUnit:   "days",
+		TrialIntervalLength: 14,
+		UnitAmountInCents:   recurly.UnitAmount{USD: 1000},
+		SetupFeeInCents:     recurly.UnitAmount{USD: 500},
+	})
+
+	ns := signup("1")
+	ns.PlanCode = "trial"
+	_, sub, err := client.Subscriptions.Create(ns)
+	if err != nil {
+		t.Fatalf("TestSubscriptionTrial Error: Error creating subscription. Err: %s", err)
+	}
+
+	trialEnd := now.AddDate(0, 0, 14)
+	if !sub.TrialEndsAt.Equal(trialEnd) || !sub.CurrentPeriodEndsAt.Equal(trialEnd) {
+		t.Errorf("TestSubscriptionTrial Error: Expected trial and term to end at %s, given %s and %s", trialEnd, sub.TrialEndsAt, sub.CurrentPeriodEndsAt)
+	}
+
+	// Only the setup fee is charged during the trial.
+	_, inv, _ := client.Invoices.Get(1000)
+	if inv.TotalInCents != 500 || len(inv.LineItems) != 1 || inv.LineItems[0].Origin != "setup_fee" {
+		t.Errorf("TestSubscriptionTrial Error: Expected setup fee invoice for 500 cents, given %#v", inv)
+	}
+
+	_, subs, _ := client.Subscriptions.List(recurly.Params{"state": recurly.SubscriptionStateInTrial})
+	if len(subs) != 1 {
+		t.Errorf("TestSubscriptionTrial Error: Expected one subscription in trial, given %d", len(subs))
+	}
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	_, client := newServer(t)
+	_, sub, _ := client.Subscriptions.Create(signup("1"))
+
+	_, sub, err := client.Subscriptions.Cancel(sub.UUID)
+	if err != nil || sub.State != recurly.SubscriptionStateCanceled || !sub.ExpiresAt.Equal(now.AddDate(0, 1, 0)) {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected canceled subscription expiring at term end, given %#v (%v)", sub, err)
+	}
+
+	if r, _, _ := client.Subscriptions.Cancel(sub.UUID); r.StatusCode != 422 {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected status code 422 canceling twice, given %d", r.StatusCode)
+	}
+
+	_, sub, _ = client.Subscriptions.Reactivate(sub.UUID)
+	if sub.State != recurly.SubscriptionStateActive || sub.CanceledAt.Time != nil || sub.ExpiresAt.Time != nil {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected reactivated subscription, given %#v", sub)
+	}
+
+	renewal := now.AddDate(0, 2, 0)
+	_, sub, _ = client.Subscriptions.Postpone(sub.UUID, renewal, false)
+	if !sub.CurrentPeriodEndsAt.Equal(renewal) {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected term to end at %s, given %s", renewal, sub.CurrentPeriodEndsAt)
+	}
+
+	_, sub, _ = client.Subscriptions.TerminateWithoutRefund(sub.UUID)
+	if sub.State != recurly.SubscriptionStateExpired || !sub.ExpiresAt.Equal(now) {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected expired subscription, given %#v", sub)
+	}
+
+	_, subs, _ := client.Subscriptions.List(recurly.Params{"state": recurly.SubscriptionStateLive})
+	if len(subs) != 0 {
+		t.Errorf("TestSubscriptionLifecycle Error: Expected no live subscriptions, given %d", len(subs))
+	}
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	_, client := newServer(t)
+	client.Plans.Create(recurly.Plan{Code: "platinum", Name: "Platinum", UnitAmountInCents: recurly.UnitAmount{USD: 5000}})
+	_, sub, _ := client.Subscriptions.Create(signup("1"))
+
+	_, preview, _ := client.Subscriptions.PreviewChange(sub.UUID, recurly.UpdateSubscription{PlanCode: "platinum"})
+	if preview.Plan.Code != "platinum" || preview.UnitAmountInCents != 5000 {
+		t.Errorf("TestUpdateSubscription Error: Expected preview of platinum plan, given %#v", preview)
+	}
+
+	_, sub, _ = client.Subscriptions.Update(sub.UUID, recurly.UpdateSubscription{Timeframe: "renewal", PlanCode: "platinum"})
+	if sub.Plan.Code != "gold" {
+		t.Errorf("TestUpdateSubscription Error: Expected renewal change to leave the current plan, given %s", sub.Plan.Code)
+	} else if p := sub.PendingSubscription; p == nil || p.Plan.Code != "platinum" || p.UnitAmountInCents != 5000 {
+		t.Errorf("TestUpdateSubscription Error: Expected pending change to platinum, given %#v", p)
+	}
+
+	if _, err := client.Subscriptions.CancelPendingChange(sub.UUID); err != nil {
+		t.Errorf("TestUpdateSubscription Error: Error canceling pending change. Err: %s", err)
+	}
+	if _, sub, _ = client.Subscriptions.Get(sub.UUID); sub.PendingSubscription != nil {
+		t.Errorf("TestUpdateSubscription Error: Expected no pending change, given %#v", sub.PendingSubscription)
+	}
+	if r, _ := client.Subscriptions.CancelPendingChange(sub.UUID); r.StatusCode != 422 {
+		t.Errorf("TestUpdateSubscription Error: Expected status code 422 without a pending change, given %d", r.StatusCode)
+	}
+
+	_, sub, _ = client.Subscriptions.Update(sub.UUID, recurly.UpdateSubscription{Timeframe: "now", PlanCode: "platinum", Quantity: 2})
+	if sub.Plan.Code != "platinum" || sub.UnitAmountInCents != 5000 || sub.Quantity != 2 {
+		t.Errorf("TestUpdateSubscription Error: Expected two platinum seats, given %#v", sub)
+	}
+
+	if r, _, _ := client.Subscriptions.Update(sub.UUID, recurly.UpdateSubscription{PlanCode: "silver"}); r.StatusCode != 422 {
+		t.Errorf("TestUpdateSubscription Error: Expected status code 422 for unknown plan, given %d", r.StatusCode)
+	}
+}
+
+func TestUpdateSubscriptionNotes(t *testing.T) {
+	_, client := newServer(t)
+	ns := signup("1")
+	ns.CustomerNotes = "Thanks for your business."
+	_, sub, _ := client.Subscriptions.Create(ns)
+
+	_, sub, err := client.Subscriptions.UpdateNotes(sub.UUID, recurly.SubscriptionNotes{
+		TermsAndConditions:    "Net 30.",
+		VATReverseChargeNotes: "Reverse charge applies.",
+	})
+	if err != nil {
+		t.Fatalf("TestUpdateSubscriptionNotes Error: Error updating notes. Err: %s", err)
+	}
+
+	_, sub, _ = client.Subscriptions.Get(sub.UUID)
+	if sub.TermsAndConditions != "Net 30." || sub.VATReverseChargeNotes != "Reverse charge applies." || sub.CustomerNotes != "Thanks for your business." {
+		t.Errorf("TestUpdateSubscriptionNotes Error: Expected notes to be saved, given %#v", sub)
+	}
+}
+
+func TestFutureSubscription(t *testing.T) {
+	_, client := newServer(t)
+
+	ns := signup("1")
+	ns.StartsAt = recurly.NewTime(now.Add(48 * time.Hour))
+	_, sub, err := client.Subscriptions.Create(ns)
+	if err != nil {
+		t.Fatalf("TestFutureSubscription Error: Error creating subscription. Err: %s", err)
+	}
+
+	if sub.State != recurly.SubscriptionStateFuture || sub.ActivatedAt.Time != nil || sub.Invoice.Code != "" {
+		t.Errorf("TestFutureSubscription Error: Expected uninvoiced future subscription, given %#v", sub)
+	}
+}
+
+func TestPauseSubscription(t *testing.T) {
+	srv, client := newServer(t)
+	_, sub, _ := client.Subscriptions.Create(signup("1"))
+	renewal := now.AddDate(0, 1, 0)
+
+	if r, _, _ := client.Subscriptions.Resume(sub.UUID); r.StatusCode != 422 {
+		t.Errorf("TestPauseSubscription Error: Expected status code 422 resuming an active subscription, given %d", r.StatusCode)
+	}
+
+	_, sub, err := client.Subscriptions.Pause(sub.UUID, 2)
+	if err != nil || sub.State != recurly.SubscriptionStateActive || !sub.PausedAt.Equal(renewal) || !sub.ResumeAt.Equal(now.AddDate(0, 3, 0)) || sub.RemainingPauseCycles != 2 {
+		t.Errorf("TestPauseSubscription Error: Expected pause scheduled at renewal, given %#v (%v)", sub, err)
+	}
+
+	_, sub, _ = client.Subscriptions.RemovePause(sub.UUID)
+	if sub.PausedAt.Time != nil || sub.ResumeAt.Time != nil || sub.RemainingPauseCycles != 0 {
+		t.Errorf("TestPauseSubscription Error: Expected scheduled pause to be removed, given %#v", sub)
+	}
+
+	// The pause starts at renewal.
+	client.Subscriptions.Pause(sub.UUID, 1)
+	srv.Now = func() time.Time { return renewal }
+	_, sub, _ = client.Subscriptions.Get(sub.UUID)
+	if sub.State != recurly.SubscriptionStatePaused {
+		t.Errorf("TestPauseSubscription Error: Expected paused subscription, given %s", sub.State)
+	}
+
+	if r, _, _ := client.Subscriptions.RemovePause(sub.UUID); r.StatusCode != 422 {
+		t.Errorf("TestPauseSubscription Error: Expected status code 422 removing a started pause, given %d", r.StatusCode)
+	}
+
+	resumed := renewal.AddDate(0, 0, 10)
+	srv.Now = func() time.Time { return resumed }
+	_, sub, _ = client.Subscriptions.Resume(sub.UUID)
+	if sub.State != recurly.SubscriptionStateActive || sub.PausedAt.Time != nil || !sub.CurrentPeriodStartedAt.Equal(resumed) || !sub.CurrentPeriodEndsAt.Equal(resumed.AddDate(0, 1, 0)) {
+		t.Errorf("TestPauseSubscription Error: Expected subscription resumed with a new term, given %#v", sub)
+	}
+
+	// Pauses end on their own at ResumeAt.
+	client.Subscriptions.Pause(sub.UUID, 1)
+	srv.Now = func() time.Time { return resumed.AddDate(0, 2, 0) }
+	_, subs, _ := client.Subscriptions.List(recurly.Params{"state": recurly.SubscriptionStateActive})
+	if len(subs) != 1 || !subs[0].CurrentPeriodStartedAt.Equal(resumed.AddDate(0, 2, 0)) {
+		t.Errorf("TestPauseSubscription Error: Expected subscription to resume at the end of the pause, given %#v", subs)
+	}
+}
+
+func TestProrateSubscription(t *testing.T) {
+	_, client := newServer(t)
+	client.AddOns.Create("gold", recurly.AddOn{Code: "ip", Name: "IP Addresses", UnitAmountInCents: recurly.UnitAmount{USD: 200}})
+	client.Plans.Create(recurly.Plan{Code: "platinum", Name: "Platinum", UnitAmountInCents: recurly.UnitAmount{USD: 3000}})
+	client.AddOns.Create("platinum", recurly.AddOn{Code: "ip", Name: "IP Addresses", UnitAmountInCents: recurly.UnitAmount{USD: 200}})
+	client.Plans.Create(recurly.Plan{Code: "annual", Name: "Annual", IntervalLength: 12, UnitAmountInCents: recurly.UnitAmount{USD: 10000}})
+
+	ns := signup("1")
+	ns.PlanCode = "platinum"
+	ns.SubscriptionAddOns = &[]recurly.SubscriptionAddOn{{Code: "ip", Quantity: 2}}
+	_, sub, _ := client.Subscriptions.Create(ns)
+	_, platinum, _ := client.Plans.Get("platinum")
+
+	// The change is made 10 days into a 31 day term, leaving 21 days.
+	at := now.AddDate(0, 0, 10)
+	end := *sub.CurrentPeriodEndsAt.Time
+	line := func(code string, origin string, unit int, quantity int, total int, from time.Time, to time.Time) recurly.Adjustment {
+		return recurly.Adjustment{
+			Description:       code,
+			ProductCode:       code,
+			Origin:            origin,
+			UnitAmountInCents: unit,
+			Quantity:          quantity,
+			TotalInCents:      total,
+			Currency:          "USD",
+			StartDate:         recurly.NewTime(from),
+			EndDate:           recurly.NewTime(to),
+		}
+	}
+
+	// renews returns true if the renewal lines of p are the plan and add ons
+	// of the subscription PreviewChange returns for u.
+	renews := func(p recurly.Proration, u recurly.UpdateSubscription) bool {
+		_, preview, err := client.Subscriptions.PreviewChange(sub.UUID, u)
+		if err != nil || len(p.Renewal) != len(preview.SubscriptionAddOns)+1 {
+			return false
+		}
+
+		plan := p.Renewal[0]
+		if plan.Description != preview.Plan.Code || plan.UnitAmountInCents != preview.UnitAmountInCents || plan.Quantity != preview.Quantity {
+			return false
+		}
+		for i, a := range preview.SubscriptionAddOns {
+			if l := p.Renewal[i+1]; l.Description != a.Code || l.UnitAmountInCents != a.UnitAmountInCents || l.Quantity != a.Quantity {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Moving to a plan with the same interval and adding an IP address
+	// credits the rest of the term and charges it again at the new prices.
+	_, gold, _ := client.Plans.Get("gold")
+	_, addOns, _ := client.AddOns.List("gold", nil)
+	u := recurly.UpdateSubscription{PlanCode: "gold", SubscriptionAddOns: &[]recurly.SubscriptionAddOn{{Code: "ip", Quantity: 3}}}
+	p, err := recurly.Prorate(sub, platinum, gold, addOns, u, at)
+	if err != nil {
+		t.Fatalf("TestProrateSubscription Error: %s", err)
+	}
+
+	expected := []recurly.Adjustment{
+		line("platinum", "credit", -2032, 1, -2032, at, end),
+		line("ip", "credit", -136, 2, -271, at, end),
+		line("gold", "plan", 677, 1, 677, at, end),
+		line("ip", "add_on", 135, 3, 406, at, end),
+	}
+	if !reflect.DeepEqual(expected, p.Now) {
+		t.Errorf("TestProrateSubscription Error: Expected %#v, given %#v", expected, p.Now)
+	} else if p.NowInCents() != -1220 || p.RenewalInCents() != 1600 {
+		t.Errorf("TestProrateSubscription Error: Expected totals of -1220 and 1600, given %d and %d", p.NowInCents(), p.RenewalInCents())
+	}
+	if !renews(p, u) {
+		t.Errorf("TestProrateSubscription Error: Expected renewal to match the preview, given %#v", p.Renewal)
+	}
+
+	// A yearly plan starts a new term at the change.
+	_, annual, _ := client.Plans.Get("annual")
+	u = recurly.UpdateSubscription{PlanCode: "annual"}
+	p, err = recurly.Prorate(sub, platinum, annual, nil, u, at)
+	if err != nil {
+		t.Fatalf("TestProrateSubscription Error: %s", err)
+	}
+
+	termEnd := at.AddDate(1, 0, 0)
+	expected = []recurly.Adjustment{
+		line("platinum", "credit", -2032, 1, -2032, at, end),
+		line("ip", "credit", -136, 2, -271, at, end),
+		line("annual", "plan", 10000, 1, 10000, at, termEnd),
+		line("ip", "add_on", 200, 2, 400, at, termEnd),
+	}
+	if !reflect.DeepEqual(expected, p.Now) {
+		t.Errorf("TestProrateSubscription Error: Expected %#v, given %#v", expected, p.Now)
+	} else if !p.Renewal[0].StartDate.Equal(termEnd) {
+		t.Errorf("TestProrateSubscription Error: Expected renewal at %s, given %#v", termEnd, p.Renewal)
+	}
+	if !renews(p, u) {
+		t.Errorf("TestProrateSubscription Error: Expected renewal to match the preview, given %#v", p.Renewal)
+	}
+}
@@ -0,0 +1,188 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// usageRecord is usage logged against an add on of a subscription.
+type usageRecord struct {
+	recurly.Usage
+	subscription string
+	addOn        string
+}
+
+// findUsageAddOn returns the subscription and usage add on named in the
+// request path. It writes a 404 response and returns nil if either does not
+// exist, and a 422 response if the add on isn't a usage add on.
+func (s *Server) findUsageAddOn(rw http.ResponseWriter, r *http.Request) (*recurly.Subscription, *recurly.SubscriptionAddOn, *recurly.AddOn) {
+	sub := s.findSubscription(rw, r)
+	if sub == nil {
+		return nil, nil, nil
+	}
+
+	code := r.PathValue("addOn")
+	for i, v := range sub.SubscriptionAddOns {
+		if v.Code != code {
+			continue
+		}
+
+		a := s.addOn(sub.Plan.Code, code)
+		if a == nil || a.AddOnType != recurly.AddOnTypeUsage {
+			invalid(rw, "usage.add_on", "invalid", "is not a usage add on")
+			return nil, nil, nil
+		}
+		return sub, &sub.SubscriptionAddOns[i], a
+	}
+
+	notFound(rw, "SubscriptionAddOn", "add_on_code", code)
+	return nil, nil, nil
+}
+
+// findUsage returns the usage named in the request path. It writes a 404
+// response and returns nil if it does not exist.
+func (s *Server) findUsage(rw http.ResponseWriter, r *http.Request) *usageRecord {
+	sub, addOn, _ := s.findUsageAddOn(rw, r)
+	if sub == nil {
+		return nil
+	}
+
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	for _, u := range s.usage {
+		if u.ID == id && u.subscription == sub.UUID && u.addOn == addOn.Code {
+			return u
+		}
+	}
+
+	notFound(rw, "Usage", "id", r.PathValue("id"))
+	return nil
+}
+
+func (s *Server) listUsage(rw http.ResponseWriter, r *http.Request) {
+	sub, addOn, _ := s.findUsageAddOn(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var usage []usageXML
+	for _, u := range s.usage {
+		if u.subscription == sub.UUID && u.addOn == addOn.Code {
+			usage = append(usage, s.usageXML(u))
+		}
+	}
+
+	start, end := paginate(rw, r, len(usage))
+	writeXML(rw, http.StatusOK, usagesXML{Usage: usage[start:end]})
+}
+
+// createUsage logs usage priced like the subscription add on: at its unit
+// amount for price add ons, or its usage percentage for percentage add ons.
+func (s *Server) createUsage(rw http.ResponseWriter, r *http.Request) {
+	sub, addOn, a := s.findUsageAddOn(rw, r)
+	if sub == nil {
+		return
+	}
+
+	var u usageRecord
+	if !decode(rw, r, &u.Usage) {
+		return
+	}
+
+	if !u.Amount.Valid {
+		invalid(rw, "usage.amount", "blank", "can't be blank")
+		return
+	} else if u.Amount.Int < 0 {
+		invalid(rw, "usage.amount", "greater_than_or_equal_to", "must be greater than or equal to 0")
+		return
+	}
+
+	s.seq++
+	u.subscription, u.addOn = sub.UUID, addOn.Code
+	u.XMLName.Local = "usage"
+	u.ID = s.seq
+	u.MeasuredUnit.Code = a.MeasuredUnit.Code
+	u.UsageType = a.UsageType
+	if a.UsageType == recurly.UsageTypePercentage {
+		u.UsagePercentage = addOn.UsagePercentage
+	} else {
+		u.UnitAmountInCents = addOn.UnitAmountInCents
+	}
+	u.CreatedAt = s.now()
+	u.UpdatedAt = u.CreatedAt
+	if u.UsageTimestamp.Time == nil {
+		u.UsageTimestamp = u.CreatedAt
+	}
+	if u.RecordingTimestamp.Time == nil {
+		u.RecordingTimestamp = u.CreatedAt
+	}
+	s.usage = append(s.usage, &u)
+
+	writeXML(rw, http.StatusCreated, s.usageXML(&u))
+}
+
+func (s *Server) getUsage(rw http.ResponseWriter, r *http.Request) {
+	if u := s.findUsage(rw, r); u != nil {
+		writeXML(rw, http.StatusOK, s.usageXML(u))
+	}
+}
+
+// updateUsage replaces the fields that are given of usage that hasn't been
+// billed.
+func (s *Server) updateUsage(rw http.ResponseWriter, r *http.Request) {
+	u := s.findUsage(rw, r)
+	if u == nil {
+		return
+	}
+
+	var update recurly.Usage
+	if !decode(rw, r, &update) {
+		return
+	}
+
+	if u.BilledAt.Time != nil {
+		invalid(rw, "usage.base", "invalid_state", "has been billed and can't be changed")
+		return
+	} else if update.Amount.Valid && update.Amount.Int < 0 {
+		invalid(rw, "usage.amount", "greater_than_or_equal_to", "must be greater than or equal to 0")
+		return
+	}
+
+	if update.Amount.Valid {
+		u.Amount = update.Amount
+	}
+	if update.MerchantTag != "" {
+		u.MerchantTag = update.MerchantTag
+	}
+	if update.UsageTimestamp.Time != nil {
+		u.UsageTimestamp = update.UsageTimestamp
+	}
+	if update.RecordingTimestamp.Time != nil {
+		u.RecordingTimestamp = update.RecordingTimestamp
+	}
+	u.UpdatedAt = s.now()
+
+	writeXML(rw, http.StatusOK, s.usageXML(u))
+}
+
+func (s *Server) deleteUsage(rw http.ResponseWriter, r *http.Request) {
+	u := s.findUsage(rw, r)
+	if u == nil {
+		return
+	}
+
+	if u.BilledAt.Time != nil {
+		invalid(rw, "usage.base", "invalid_state", "has been billed and can't be deleted")
+		return
+	}
+
+	for i, v := range s.usage {
+		if v == u {
+			s.usage = append(s.usage[:i], s.usage[i+1:]...)
+			break
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
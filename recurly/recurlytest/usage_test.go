@@ -0,0 +1,142 @@
+package recurlytest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+func TestMeasuredUnits(t *testing.T) {
+	_, client := newServer(t)
+
+	if r, _, _ := client.MeasuredUnits.Create(recurly.MeasuredUnit{Name: "calls"}); r.StatusCode != 422 || r.Errors[0].Field != "measured_unit.display_name" {
+		t.Errorf("TestMeasuredUnits Error: Expected display name to be required, given %+v", r)
+	}
+
+	r, u, err := client.MeasuredUnits.Create(recurly.MeasuredUnit{Name: "calls", DisplayName: "API Calls"})
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestMeasuredUnits Error: Expected measured unit to be created, given %v (%+v)", err, r)
+	} else if u.ID == 0 || !u.CreatedAt.Equal(now) {
+		t.Errorf("TestMeasuredUnits Error: Expected measured unit with an ID, given %#v", u)
+	}
+
+	if _, _, err := client.MeasuredUnits.Update(u.ID, recurly.MeasuredUnit{Description: "Calls to the API"}); err != nil {
+		t.Fatalf("TestMeasuredUnits Error: Error updating measured unit. Err: %s", err)
+	}
+
+	_, u, _ = client.MeasuredUnits.Get(u.ID)
+	if u.DisplayName != "API Calls" || u.Description != "Calls to the API" {
+		t.Errorf("TestMeasuredUnits Error: Expected update to keep unchanged fields, given %#v", u)
+	}
+
+	// Units used by add ons can't be deleted.
+	client.AddOns.Create("gold", recurly.AddOn{Code: "calls", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePrice, MeasuredUnitID: u.ID})
+	if r, _ := client.MeasuredUnits.Delete(u.ID); r.StatusCode != 422 {
+		t.Errorf("TestMeasuredUnits Error: Expected measured unit in use not to be deleted, given %+v", r)
+	}
+
+	client.AddOns.Delete("gold", "calls")
+	if _, err := client.MeasuredUnits.Delete(u.ID); err != nil {
+		t.Fatalf("TestMeasuredUnits Error: Error deleting measured unit. Err: %s", err)
+	}
+
+	if _, units, _ := client.MeasuredUnits.List(nil); len(units) != 0 {
+		t.Errorf("TestMeasuredUnits Error: Expected measured unit to be deleted, given %#v", units)
+	}
+}
+
+func TestUsageAddOn(t *testing.T) {
+	_, client := newServer(t)
+	_, u, _ := client.MeasuredUnits.Create(recurly.MeasuredUnit{Name: "calls", DisplayName: "API Calls"})
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"add_on": recurly.AddOn{Code: "a", AddOnType: "metered"}, "field": "add_on.add_on_type"},
+		map[string]interface{}{"add_on": recurly.AddOn{Code: "b", AddOnType: recurly.AddOnTypeUsage, MeasuredUnitID: u.ID}, "field": "add_on.usage_type"},
+		map[string]interface{}{"add_on": recurly.AddOn{Code: "c", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePercentage, UsagePercentage: "101", MeasuredUnitID: u.ID}, "field": "add_on.usage_percentage"},
+		map[string]interface{}{"add_on": recurly.AddOn{Code: "d", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePrice}, "field": "add_on.measured_unit_id"},
+		map[string]interface{}{"add_on": recurly.AddOn{Code: "e", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePrice, MeasuredUnitID: u.ID + 1}, "field": "add_on.measured_unit_id"},
+	}
+
+	for i, s := range suite {
+		if r, _, _ := client.AddOns.Create("gold", s["add_on"].(recurly.AddOn)); r.StatusCode != 422 || r.Errors[0].Field != s["field"] {
+			t.Errorf("TestUsageAddOn Error (%d): Expected %s to be invalid, given %+v", i, s["field"], r)
+		}
+	}
+
+	_, a, err := client.AddOns.Create("gold", recurly.AddOn{Code: "ip", UnitAmountInCents: recurly.UnitAmount{USD: 200}})
+	if err != nil || a.AddOnType != recurly.AddOnTypeFixed {
+		t.Errorf("TestUsageAddOn Error: Expected add ons to be fixed by default, given %v (%#v)", err, a)
+	}
+
+	_, a, err = client.AddOns.Create("gold", recurly.AddOn{Code: "calls", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePrice, UnitAmountInCents: recurly.UnitAmount{USD: 5}, MeasuredUnitID: u.ID})
+	if err != nil {
+		t.Fatalf("TestUsageAddOn Error: Error creating usage add on. Err: %s", err)
+	} else if a.MeasuredUnit.Code != strconv.Itoa(u.ID) || a.MeasuredUnitID != 0 {
+		t.Errorf("TestUsageAddOn Error: Expected add on to link to its measured unit, given %#v", a)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	_, client := newServer(t)
+	_, u, _ := client.MeasuredUnits.Create(recurly.MeasuredUnit{Name: "calls", DisplayName: "API Calls"})
+	client.AddOns.Create("gold", recurly.AddOn{Code: "ip", UnitAmountInCents: recurly.UnitAmount{USD: 200}})
+	client.AddOns.Create("gold", recurly.AddOn{Code: "calls", AddOnType: recurly.AddOnTypeUsage, UsageType: recurly.UsageTypePrice, UnitAmountInCents: recurly.UnitAmount{USD: 5}, MeasuredUnitID: u.ID})
+
+	ns := signup("1")
+	ns.SubscriptionAddOns = &[]recurly.SubscriptionAddOn{{Code: "ip"}, {Code: "calls"}}
+	_, sub, err := client.Subscriptions.Create(ns)
+	if err != nil {
+		t.Fatalf("TestUsage Error: Error creating subscription. Err: %s", err)
+	}
+
+	// Usage add ons aren't charged up front.
+	_, invoices, _ := client.Invoices.ListAccount("1", nil)
+	if len(invoices) != 1 || invoices[0].TotalInCents != 1200 {
+		t.Errorf("TestUsage Error: Expected the plan and fixed add on to be charged, given %#v", invoices)
+	}
+
+	if r, _, _ := client.Usage.Create(sub.UUID, "ip", recurly.Usage{Amount: recurly.NewInt(10)}); r.StatusCode != 422 {
+		t.Errorf("TestUsage Error: Expected usage on a fixed add on to be invalid, given %+v", r)
+	} else if r, _, _ := client.Usage.Create(sub.UUID, "support", recurly.Usage{Amount: recurly.NewInt(10)}); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestUsage Error: Expected usage on an unknown add on not to be found, given %+v", r)
+	}
+
+	r, usage, err := client.Usage.Create(sub.UUID, "calls", recurly.Usage{Amount: recurly.NewInt(100), MerchantTag: "Server 1"})
+	if err != nil || r.StatusCode != http.StatusCreated {
+		t.Fatalf("TestUsage Error: Expected usage to be created, given %v (%+v)", err, r)
+	} else if usage.ID == 0 || usage.UsageType != recurly.UsageTypePrice || usage.UnitAmountInCents != 5 || usage.MeasuredUnit.Code != strconv.Itoa(u.ID) || !usage.UsageTimestamp.Equal(now) {
+		t.Errorf("TestUsage Error: Expected usage priced like the add on, given %#v", usage)
+	}
+
+	if _, _, err := client.Usage.Update(sub.UUID, "calls", usage.ID, recurly.Usage{Amount: recurly.NewInt(150)}); err != nil {
+		t.Fatalf("TestUsage Error: Error updating usage. Err: %s", err)
+	}
+
+	_, usage, _ = client.Usage.Get(sub.UUID, "calls", usage.ID)
+	if usage.Amount.Int != 150 || usage.MerchantTag != "Server 1" {
+		t.Errorf("TestUsage Error: Expected update to change the amount only, given %#v", usage)
+	}
+
+	if _, _, err := client.Usage.Update(sub.UUID, "calls", usage.ID, recurly.Usage{MerchantTag: "Server 2"}); err != nil {
+		t.Fatalf("TestUsage Error: Error updating usage. Err: %s", err)
+	}
+
+	_, usage, _ = client.Usage.Get(sub.UUID, "calls", usage.ID)
+	if usage.Amount.Int != 150 || usage.MerchantTag != "Server 2" {
+		t.Errorf("TestUsage Error: Expected update to leave the amount alone, given %#v", usage)
+	}
+
+	if _, list, _ := client.Usage.List(sub.UUID, "calls", nil); len(list) != 1 || list[0].ID != usage.ID {
+		t.Errorf("TestUsage Error: Expected usage to be listed, given %#v", list)
+	}
+
+	if _, err := client.Usage.Delete(sub.UUID, "calls", usage.ID); err != nil {
+		t.Fatalf("TestUsage Error: Error deleting usage. Err: %s", err)
+	}
+
+	if r, _, _ := client.Usage.Get(sub.UUID, "calls", usage.ID); r.StatusCode != http.StatusNotFound {
+		t.Errorf("TestUsage Error: Expected usage to be deleted, given %+v", r)
+	}
+}
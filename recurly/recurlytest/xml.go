@@ -0,0 +1,499 @@
+package recurlytest
+
+import (
+	"encoding/xml"
+	"net"
+	"strconv"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// The recurly package only marshals the writable fields of some resources,
+// and never marshals href links. The types below are the read format of
+// those resources so the fake can respond with them.
+type (
+	link struct {
+		HREF string `xml:"href,attr"`
+	}
+
+	errorXML struct {
+		XMLName     xml.Name `xml:"error"`
+		Symbol      string   `xml:"symbol"`
+		Description string   `xml:"description"`
+	}
+
+	errorsXML struct {
+		XMLName          xml.Name                  `xml:"errors"`
+		TransactionError *recurly.TransactionError `xml:"transaction_error,omitempty"`
+		Errors           []recurly.Error           `xml:"error"`
+	}
+
+	accountXML struct {
+		recurly.Account
+		ParentAccount *link `xml:"parent_account,omitempty"`
+	}
+
+	accountsXML struct {
+		XMLName  xml.Name     `xml:"accounts"`
+		Accounts []accountXML `xml:"account"`
+	}
+
+	noteXML struct {
+		XMLName   xml.Name         `xml:"note"`
+		Account   link             `xml:"account"`
+		Message   string           `xml:"message"`
+		CreatedAt recurly.NullTime `xml:"created_at"`
+	}
+
+	notesXML struct {
+		XMLName xml.Name  `xml:"notes"`
+		Notes   []noteXML `xml:"note"`
+	}
+
+	plansXML struct {
+		XMLName xml.Name       `xml:"plans"`
+		Plans   []recurly.Plan `xml:"plan"`
+	}
+
+	addOnXML struct {
+		recurly.AddOn
+		MeasuredUnit *link `xml:"measured_unit,omitempty"`
+	}
+
+	addOnsXML struct {
+		XMLName xml.Name   `xml:"add_ons"`
+		AddOns  []addOnXML `xml:"add_on"`
+	}
+
+	// measuredUnitXML is a measured unit without the MarshalXML method,
+	// which leaves out the read only fields.
+	measuredUnitXML recurly.MeasuredUnit
+
+	measuredUnitsXML struct {
+		XMLName       xml.Name          `xml:"measured_units"`
+		MeasuredUnits []measuredUnitXML `xml:"measured_unit"`
+	}
+
+	usageXML struct {
+		XMLName            xml.Name         `xml:"usage"`
+		ID                 int              `xml:"id"`
+		MeasuredUnit       *link            `xml:"measured_unit,omitempty"`
+		Amount             int              `xml:"amount"`
+		MerchantTag        string           `xml:"merchant_tag,omitempty"`
+		RecordingTimestamp recurly.NullTime `xml:"recording_timestamp,omitempty"`
+		UsageTimestamp     recurly.NullTime `xml:"usage_timestamp,omitempty"`
+		UsageType          string           `xml:"usage_type"`
+		UnitAmountInCents  int              `xml:"unit_amount_in_cents,omitempty"`
+		UsagePercentage    string           `xml:"usage_percentage,omitempty"`
+		BilledAt           recurly.NullTime `xml:"billed_at,omitempty"`
+		CreatedAt          recurly.NullTime `xml:"created_at,omitempty"`
+		UpdatedAt          recurly.NullTime `xml:"updated_at,omitempty"`
+	}
+
+	usagesXML struct {
+		XMLName xml.Name   `xml:"usages"`
+		Usage   []usageXML `xml:"usage"`
+	}
+
+	couponsXML struct {
+		XMLName xml.Name         `xml:"coupons"`
+		Coupons []recurly.Coupon `xml:"coupon"`
+	}
+
+	subscriptionXML struct {
+		XMLName                xml.Name                    `xml:"subscription"`
+		Account                link                        `xml:"account"`
+		Invoice                *link                       `xml:"invoice,omitempty"`
+		Plan                   planXML                     `xml:"plan"`
+		UUID                   string                      `xml:"uuid"`
+		State                  string                      `xml:"state"`
+		UnitAmountInCents      int                         `xml:"unit_amount_in_cents"`
+		Currency               string                      `xml:"currency"`
+		Quantity               int                         `xml:"quantity"`
+		ActivatedAt            recurly.NullTime            `xml:"activated_at,omitempty"`
+		CanceledAt             recurly.NullTime            `xml:"canceled_at,omitempty"`
+		ExpiresAt              recurly.NullTime            `xml:"expires_at,omitempty"`
+		CurrentPeriodStartedAt recurly.NullTime            `xml:"current_period_started_at,omitempty"`
+		CurrentPeriodEndsAt    recurly.NullTime            `xml:"current_period_ends_at,omitempty"`
+		TrialStartedAt         recurly.NullTime            `xml:"trial_started_at,omitempty"`
+		TrialEndsAt            recurly.NullTime            `xml:"trial_ends_at,omitempty"`
+		TaxInCents             int                         `xml:"tax_in_cents,omitempty"`
+		PONumber               string                      `xml:"po_number,omitempty"`
+		NetTerms               recurly.NullInt             `xml:"net_terms,omitempty"`
+		TermsAndConditions     string                      `xml:"terms_and_conditions,omitempty"`
+		CustomerNotes          string                      `xml:"customer_notes,omitempty"`
+		VATReverseChargeNotes  string                      `xml:"vat_reverse_charge_notes,omitempty"`
+		SubscriptionAddOns     []recurly.SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on"`
+		PausedAt               recurly.NullTime            `xml:"paused_at,omitempty"`
+		ResumeAt               recurly.NullTime            `xml:"resume_at,omitempty"`
+		RemainingPauseCycles   int                         `xml:"remaining_pause_cycles,omitempty"`
+		PendingSubscription    *recurly.PendingSubscription
+	}
+
+	planXML struct {
+		Code string `xml:"plan_code"`
+		Name string `xml:"name"`
+	}
+
+	subscriptionsXML struct {
+		XMLName       xml.Name          `xml:"subscriptions"`
+		Subscriptions []subscriptionXML `xml:"subscription"`
+	}
+
+	adjustmentXML struct {
+		XMLName           xml.Name         `xml:"adjustment"`
+		Account           link             `xml:"account"`
+		Invoice           *link            `xml:"invoice,omitempty"`
+		UUID              string           `xml:"uuid"`
+		State             string           `xml:"state"`
+		Description       string           `xml:"description,omitempty"`
+		AccountingCode    string           `xml:"accounting_code,omitempty"`
+		ProductCode       string           `xml:"product_code,omitempty"`
+		Origin            string           `xml:"origin"`
+		UnitAmountInCents int              `xml:"unit_amount_in_cents"`
+		Quantity          int              `xml:"quantity"`
+		OriginalUUID      string           `xml:"original_adjustment_uuid,omitempty"`
+		DiscountInCents   int              `xml:"discount_in_cents"`
+		TaxInCents        int              `xml:"tax_in_cents"`
+		TotalInCents      int              `xml:"total_in_cents"`
+		Currency          string           `xml:"currency"`
+		Taxable           recurly.NullBool `xml:"taxable,omitempty"`
+		TaxCode           string           `xml:"tax_code,omitempty"`
+		TaxExempt         recurly.NullBool `xml:"tax_exempt,omitempty"`
+		StartDate         recurly.NullTime `xml:"start_date,omitempty"`
+		EndDate           recurly.NullTime `xml:"end_date,omitempty"`
+		CreatedAt         recurly.NullTime `xml:"created_at,omitempty"`
+	}
+
+	adjustmentsXML struct {
+		XMLName     xml.Name        `xml:"adjustments"`
+		Adjustments []adjustmentXML `xml:"adjustment"`
+	}
+
+	invoiceXML struct {
+		XMLName          xml.Name         `xml:"invoice"`
+		Account          link             `xml:"account"`
+		Subscription     *link            `xml:"subscription,omitempty"`
+		OriginalInvoice  *link            `xml:"original_invoice,omitempty"`
+		UUID             string           `xml:"uuid"`
+		State            string           `xml:"state"`
+		InvoiceNumber    int              `xml:"invoice_number"`
+		PONumber         string           `xml:"po_number,omitempty"`
+		VATNumber        string           `xml:"vat_number,omitempty"`
+		SubtotalInCents  int              `xml:"subtotal_in_cents"`
+		TaxInCents       int              `xml:"tax_in_cents"`
+		TotalInCents     int              `xml:"total_in_cents"`
+		Currency         string           `xml:"currency"`
+		CreatedAt        recurly.NullTime `xml:"created_at,omitempty"`
+		ClosedAt         recurly.NullTime `xml:"closed_at,omitempty"`
+		NetTerms         recurly.NullInt  `xml:"net_terms,omitempty"`
+		CollectionMethod string           `xml:"collection_method,omitempty"`
+		LineItems        []adjustmentXML  `xml:"line_items>adjustment"`
+		Transactions     []transactionXML `xml:"transactions>transaction"`
+	}
+
+	// refundXML is the request body for refunding an invoice.
+	refundXML struct {
+		XMLName       xml.Name                 `xml:"invoice"`
+		AmountInCents int                      `xml:"amount_in_cents"`
+		LineItems     []recurly.RefundLineItem `xml:"line_items>adjustment"`
+		RefundMethod  string                   `xml:"refund_method"`
+	}
+
+	invoicesXML struct {
+		XMLName  xml.Name     `xml:"invoices"`
+		Invoices []invoiceXML `xml:"invoice"`
+	}
+
+	transactionXML struct {
+		XMLName       xml.Name         `xml:"transaction"`
+		AccountLink   link             `xml:"account"`
+		Invoice       *link            `xml:"invoice,omitempty"`
+		Subscription  *link            `xml:"subscription,omitempty"`
+		UUID          string           `xml:"uuid"`
+		Action        string           `xml:"action"`
+		AmountInCents int              `xml:"amount_in_cents"`
+		TaxInCents    int              `xml:"tax_in_cents"`
+		Currency      string           `xml:"currency"`
+		Status        string           `xml:"status"`
+		PaymentMethod string           `xml:"payment_method"`
+		Reference     string           `xml:"reference,omitempty"`
+		Source        string           `xml:"source"`
+		Recurring     recurly.NullBool `xml:"recurring,omitempty"`
+		Test          bool             `xml:"test"`
+		Voidable      recurly.NullBool `xml:"voidable,omitempty"`
+		Refundable    recurly.NullBool `xml:"refundable,omitempty"`
+		IPAddress     net.IP           `xml:"ip_address,omitempty"`
+		CreatedAt     recurly.NullTime `xml:"created_at,omitempty"`
+		Account       recurly.Account  `xml:"details>account"`
+	}
+
+	transactionsXML struct {
+		XMLName      xml.Name         `xml:"transactions"`
+		Transactions []transactionXML `xml:"transaction"`
+	}
+
+	shippingAddressXML struct {
+		XMLName   xml.Name         `xml:"shipping_address"`
+		Account   link             `xml:"account"`
+		ID        int              `xml:"id"`
+		Nickname  string           `xml:"nickname,omitempty"`
+		FirstName string           `xml:"first_name"`
+		LastName  string           `xml:"last_name"`
+		Company   string           `xml:"company,omitempty"`
+		Email     string           `xml:"email,omitempty"`
+		VATNumber string           `xml:"vat_number,omitempty"`
+		Address   string           `xml:"address1"`
+		Address2  string           `xml:"address2,omitempty"`
+		City      string           `xml:"city"`
+		State     string           `xml:"state,omitempty"`
+		Zip       string           `xml:"zip"`
+		Country   string           `xml:"country"`
+		Phone     string           `xml:"phone,omitempty"`
+		CreatedAt recurly.NullTime `xml:"created_at,omitempty"`
+		UpdatedAt recurly.NullTime `xml:"updated_at,omitempty"`
+	}
+
+	shippingAddressesXML struct {
+		XMLName           xml.Name             `xml:"shipping_addresses"`
+		ShippingAddresses []shippingAddressXML `xml:"shipping_address"`
+	}
+
+	redemptionXML struct {
+		XMLName                xml.Name         `xml:"redemption"`
+		Coupon                 link             `xml:"coupon"`
+		Account                link             `xml:"account"`
+		SingleUse              recurly.NullBool `xml:"single_use,omitempty"`
+		TotalDiscountedInCents int              `xml:"total_discounted_in_cents"`
+		Currency               string           `xml:"currency"`
+		State                  string           `xml:"state"`
+		CreatedAt              recurly.NullTime `xml:"created_at,omitempty"`
+	}
+)
+
+// accountXML returns the read format of an account.
+func (s *Server) accountXML(a *recurly.Account) accountXML {
+	v := accountXML{Account: *a}
+	if a.ParentAccount.Code != "" {
+		l := s.link("accounts/%s", a.ParentAccount.Code)
+		v.ParentAccount = &l
+	}
+
+	return v
+}
+
+// addOnXML returns the read format of an add on.
+func (s *Server) addOnXML(a *recurly.AddOn) addOnXML {
+	v := addOnXML{AddOn: *a}
+	if a.MeasuredUnit.Code != "" {
+		l := s.link("measured_units/%s", a.MeasuredUnit.Code)
+		v.MeasuredUnit = &l
+	}
+
+	return v
+}
+
+// usageXML returns the read format of usage.
+func (s *Server) usageXML(u *usageRecord) usageXML {
+	v := usageXML{
+		ID:                 u.ID,
+		Amount:             u.Amount.Int,
+		MerchantTag:        u.MerchantTag,
+		RecordingTimestamp: u.RecordingTimestamp,
+		UsageTimestamp:     u.UsageTimestamp,
+		UsageType:          u.UsageType,
+		UnitAmountInCents:  u.UnitAmountInCents,
+		UsagePercentage:    u.UsagePercentage,
+		BilledAt:           u.BilledAt,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+	if u.MeasuredUnit.Code != "" {
+		l := s.link("measured_units/%s", u.MeasuredUnit.Code)
+		v.MeasuredUnit = &l
+	}
+
+	return v
+}
+
+// shippingAddressXML returns the read format of a shipping address.
+func (s *Server) shippingAddressXML(a *recurly.ShippingAddress) shippingAddressXML {
+	return shippingAddressXML{
+		Account:   s.link("accounts/%s", a.Account.Code),
+		ID:        a.ID,
+		Nickname:  a.Nickname,
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Company:   a.Company,
+		Email:     a.Email,
+		VATNumber: a.VATNumber,
+		Address:   a.Address,
+		Address2:  a.Address2,
+		City:      a.City,
+		State:     a.State,
+		Zip:       a.Zip,
+		Country:   a.Country,
+		Phone:     a.Phone,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+// subscriptionXML returns the read format of a subscription.
+func (s *Server) subscriptionXML(sub *recurly.Subscription) subscriptionXML {
+	v := subscriptionXML{
+		Account:                s.link("accounts/%s", sub.Account.Code),
+		Plan:                   planXML{Code: sub.Plan.Code, Name: sub.Plan.Name},
+		UUID:                   sub.UUID,
+		State:                  sub.State,
+		UnitAmountInCents:      sub.UnitAmountInCents,
+		Currency:               sub.Currency,
+		Quantity:               sub.Quantity,
+		ActivatedAt:            sub.ActivatedAt,
+		CanceledAt:             sub.CanceledAt,
+		ExpiresAt:              sub.ExpiresAt,
+		CurrentPeriodStartedAt: sub.CurrentPeriodStartedAt,
+		CurrentPeriodEndsAt:    sub.CurrentPeriodEndsAt,
+		TrialStartedAt:         sub.TrialStartedAt,
+		TrialEndsAt:            sub.TrialEndsAt,
+		TaxInCents:             sub.TaxInCents,
+		PONumber:               sub.PONumber,
+		NetTerms:               sub.NetTerms,
+		TermsAndConditions:     sub.TermsAndConditions,
+		CustomerNotes:          sub.CustomerNotes,
+		VATReverseChargeNotes:  sub.VATReverseChargeNotes,
+		SubscriptionAddOns:     sub.SubscriptionAddOns,
+		PausedAt:               sub.PausedAt,
+		ResumeAt:               sub.ResumeAt,
+		RemainingPauseCycles:   sub.RemainingPauseCycles,
+		PendingSubscription:    sub.PendingSubscription,
+	}
+	if sub.Invoice.Code != "" {
+		l := s.link("invoices/%s", sub.Invoice.Code)
+		v.Invoice = &l
+	}
+
+	return v
+}
+
+// adjustmentXML returns the read format of an adjustment.
+func (s *Server) adjustmentXML(a *recurly.Adjustment) adjustmentXML {
+	v := adjustmentXML{
+		Account:           s.link("accounts/%s", a.Account.Code),
+		UUID:              a.UUID,
+		State:             a.State,
+		Description:       a.Description,
+		AccountingCode:    a.AccountingCode,
+		ProductCode:       a.ProductCode,
+		Origin:            a.Origin,
+		UnitAmountInCents: a.UnitAmountInCents,
+		Quantity:          a.Quantity,
+		OriginalUUID:      a.OriginalAdjustmentUUID,
+		DiscountInCents:   a.DiscountInCents,
+		TaxInCents:        a.TaxInCents,
+		TotalInCents:      a.TotalInCents,
+		Currency:          a.Currency,
+		Taxable:           a.Taxable,
+		TaxCode:           a.TaxCode,
+		TaxExempt:         a.TaxExempt,
+		StartDate:         a.StartDate,
+		EndDate:           a.EndDate,
+		CreatedAt:         a.CreatedAt,
+	}
+	if a.Invoice.Code != "" {
+		l := s.link("invoices/%s", a.Invoice.Code)
+		v.Invoice = &l
+	}
+
+	return v
+}
+
+// invoiceXML returns the read format of an invoice, including its line
+// items and transactions.
+func (s *Server) invoiceXML(inv *recurly.Invoice) invoiceXML {
+	v := invoiceXML{
+		Account:          s.link("accounts/%s", inv.Account.Code),
+		UUID:             inv.UUID,
+		State:            inv.State,
+		InvoiceNumber:    inv.InvoiceNumber,
+		PONumber:         inv.PONumber,
+		VATNumber:        inv.VATNumber,
+		SubtotalInCents:  inv.SubtotalInCents,
+		TaxInCents:       inv.TaxInCents,
+		TotalInCents:     inv.TotalInCents,
+		Currency:         inv.Currency,
+		CreatedAt:        inv.CreatedAt,
+		ClosedAt:         inv.ClosedAt,
+		NetTerms:         inv.NetTerms,
+		CollectionMethod: inv.CollectionMethod,
+	}
+	if inv.Subscription.Code != "" {
+		l := s.link("subscriptions/%s", inv.Subscription.Code)
+		v.Subscription = &l
+	}
+	if inv.OriginalInvoice.Code != "" {
+		l := s.link("invoices/%s", inv.OriginalInvoice.Code)
+		v.OriginalInvoice = &l
+	}
+
+	number := strconv.Itoa(inv.InvoiceNumber)
+	for _, a := range s.adjustments {
+		if a.Invoice.Code == number {
+			v.LineItems = append(v.LineItems, s.adjustmentXML(a))
+		}
+	}
+
+	for _, t := range s.transactions {
+		if t.Invoice.Code == number {
+			v.Transactions = append(v.Transactions, s.transactionXML(t))
+		}
+	}
+
+	return v
+}
+
+// transactionXML returns the read format of a transaction.
+func (s *Server) transactionXML(t *recurly.Transaction) transactionXML {
+	v := transactionXML{
+		AccountLink:   s.link("accounts/%s", t.Account.Code),
+		UUID:          t.UUID,
+		Action:        t.Action,
+		AmountInCents: t.AmountInCents,
+		TaxInCents:    t.TaxInCents,
+		Currency:      t.Currency,
+		Status:        t.Status,
+		PaymentMethod: t.PaymentMethod,
+		Reference:     t.Reference,
+		Source:        t.Source,
+		Recurring:     t.Recurring,
+		Test:          t.Test,
+		Voidable:      t.Voidable,
+		Refundable:    t.Refundable,
+		IPAddress:     t.IPAddress,
+		CreatedAt:     t.CreatedAt,
+		Account:       t.Account,
+	}
+	if t.Invoice.Code != "" {
+		l := s.link("invoices/%s", t.Invoice.Code)
+		v.Invoice = &l
+	}
+	if t.Subscription.Code != "" {
+		l := s.link("subscriptions/%s", t.Subscription.Code)
+		v.Subscription = &l
+	}
+
+	return v
+}
+
+// redemptionXML returns the read format of a redemption.
+func (s *Server) redemptionXML(r *redemption) redemptionXML {
+	return redemptionXML{
+		Coupon:                 s.link("coupons/%s", r.Coupon.Code),
+		Account:                s.link("accounts/%s", r.Account.Code),
+		SingleUse:              r.SingleUse,
+		TotalDiscountedInCents: r.TotalDiscountedInCents,
+		Currency:               r.Currency,
+		State:                  r.State,
+		CreatedAt:              r.CreatedAt,
+	}
+}
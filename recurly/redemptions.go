@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 )
@@ -8,7 +9,19 @@ import (
 type (
 	// RedemptionsService handles communication with the coupon redemption
 	// related methods of the recurly API.
-	RedemptionsService struct {
+	RedemptionsService interface {
+		GetForAccount(accountCode string) (*Response, Redemption, error)
+		GetForAccountContext(ctx context.Context, accountCode string) (*Response, Redemption, error)
+		GetForInvoice(invoiceNumber string) (*Response, Redemption, error)
+		GetForInvoiceContext(ctx context.Context, invoiceNumber string) (*Response, Redemption, error)
+		Redeem(code string, accountCode string, currency string) (*Response, Redemption, error)
+		RedeemContext(ctx context.Context, code string, accountCode string, currency string) (*Response, Redemption, error)
+		Delete(accountCode string) (*Response, error)
+		DeleteContext(ctx context.Context, accountCode string) (*Response, error)
+	}
+
+	// redemptionsImpl implements RedemptionsService.
+	redemptionsImpl struct {
 		client *Client
 	}
 
@@ -28,9 +41,15 @@ type (
 // GetForAccount looks up information about the 'active' coupon redemption on
 // an account
 // https://dev.recurly.com/docs/lookup-a-coupon-redemption-on-an-account
-func (service RedemptionsService) GetForAccount(accountCode string) (*Response, Redemption, error) {
+func (service redemptionsImpl) GetForAccount(accountCode string) (*Response, Redemption, error) {
+	return service.GetForAccountContext(context.Background(), accountCode)
+}
+
+// GetForAccountContext is the same as GetForAccount, but uses ctx for the request.
+func (service redemptionsImpl) GetForAccountContext(ctx context.Context, accountCode string) (*Response, Redemption, error) {
+	ctx = withOperation(ctx, "Redemptions.GetForAccount", "accounts/{account_code}/redemption")
 	action := fmt.Sprintf("accounts/%s/redemption", accountCode)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Redemption{}, err
 	}
@@ -44,9 +63,15 @@ func (service RedemptionsService) GetForAccount(accountCode string) (*Response,
 // GetForInvoice looks up information about a coupon redemption applied
 // to an invoice.
 // https://dev.recurly.com/docs/lookup-a-coupon-redemption-on-an-invoice
-func (service RedemptionsService) GetForInvoice(invoiceNumber string) (*Response, Redemption, error) {
+func (service redemptionsImpl) GetForInvoice(invoiceNumber string) (*Response, Redemption, error) {
+	return service.GetForInvoiceContext(context.Background(), invoiceNumber)
+}
+
+// GetForInvoiceContext is the same as GetForInvoice, but uses ctx for the request.
+func (service redemptionsImpl) GetForInvoiceContext(ctx context.Context, invoiceNumber string) (*Response, Redemption, error) {
+	ctx = withOperation(ctx, "Redemptions.GetForInvoice", "invoices/{invoice_number}/redemption")
 	action := fmt.Sprintf("invoices/%s/redemption", invoiceNumber)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Redemption{}, err
 	}
@@ -64,7 +89,13 @@ func (service RedemptionsService) GetForInvoice(invoiceNumber string) (*Response
 // will be applied to the next subscription creation (new subscription),
 // modification (e.g. upgrade or downgrade), or renewal.
 // https://dev.recurly.com/docs/redeem-a-coupon-before-or-after-a-subscription
-func (service RedemptionsService) Redeem(code string, accountCode string, currency string) (*Response, Redemption, error) {
+func (service redemptionsImpl) Redeem(code string, accountCode string, currency string) (*Response, Redemption, error) {
+	return service.RedeemContext(context.Background(), code, accountCode, currency)
+}
+
+// RedeemContext is the same as Redeem, but uses ctx for the request.
+func (service redemptionsImpl) RedeemContext(ctx context.Context, code string, accountCode string, currency string) (*Response, Redemption, error) {
+	ctx = withOperation(ctx, "Redemptions.Redeem", "coupons/{coupon_code}/redeem")
 	action := fmt.Sprintf("coupons/%s/redeem", code)
 	data := struct {
 		XMLName     xml.Name `xml:"redemption"`
@@ -74,7 +105,7 @@ func (service RedemptionsService) Redeem(code string, accountCode string, curren
 		AccountCode: accountCode,
 		Currency:    currency,
 	}
-	req, err := service.client.newRequest("POST", action, nil, data)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, data)
 	if err != nil {
 		return nil, Redemption{}, err
 	}
@@ -91,9 +122,15 @@ func (service RedemptionsService) Redeem(code string, accountCode string, curren
 // function. Please note: the coupon will still count towards the
 // "maximum redemption total" of a coupon.
 // https://dev.recurly.com/docs/remove-a-coupon-from-an-account
-func (service RedemptionsService) Delete(accountCode string) (*Response, error) {
+func (service redemptionsImpl) Delete(accountCode string) (*Response, error) {
+	return service.DeleteContext(context.Background(), accountCode)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service redemptionsImpl) DeleteContext(ctx context.Context, accountCode string) (*Response, error) {
+	ctx = withOperation(ctx, "Redemptions.Delete", "accounts/{account_code}/redemption")
 	action := fmt.Sprintf("accounts/%s/redemption", accountCode)
-	req, err := service.client.newRequest("DELETE", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
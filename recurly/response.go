@@ -4,7 +4,9 @@ import (
 	"encoding/xml"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type (
@@ -108,3 +110,61 @@ func (r Response) Next() string {
 
 	return ""
 }
+
+// TotalRecords returns the total number of records across all pages of a
+// list request, as given by the X-Records header. It returns -1 if the header
+// is not present.
+func (r Response) TotalRecords() int {
+	v := r.Header.Get("X-Records")
+	if v == "" {
+		return -1
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// RateLimit returns the number of requests allowed in the current rate limit
+// window, as given by the X-RateLimit-Limit header. It returns -1 if the
+// header is not present.
+func (r Response) RateLimit() int {
+	return headerInt(r.Header, "X-RateLimit-Limit")
+}
+
+// RateLimitRemaining returns the number of requests left in the current rate
+// limit window, as given by the X-RateLimit-Remaining header. It returns -1
+// if the header is not present.
+func (r Response) RateLimitRemaining() int {
+	return headerInt(r.Header, "X-RateLimit-Remaining")
+}
+
+// RateLimitReset returns when the current rate limit window ends, as given
+// by the X-RateLimit-Reset header. It returns the zero time if the header is
+// not present.
+func (r Response) RateLimitReset() time.Time {
+	n := headerInt(r.Header, "X-RateLimit-Reset")
+	if n < 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(n), 0)
+}
+
+// headerInt returns the value of an integer header, or -1 if the header is
+// not present or not an integer.
+func headerInt(h http.Header, key string) int {
+	v := h.Get(key)
+	if v == "" {
+		return -1
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
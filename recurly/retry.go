@@ -0,0 +1,203 @@
+package recurly
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 250 * time.Millisecond
+	defaultRetryMaxBackoff  = 10 * time.Second
+)
+
+type (
+	// RetryPolicy configures automatic retries for failed API calls. Requests
+	// are retried when the response has a 5xx or 429 status code, or when a
+	// network error occurs before a response is received.
+	//
+	// GET, HEAD and DELETE requests are always eligible for retries. POST and
+	// PUT requests are only retried when RetryNonIdempotent is true, as
+	// retrying them may create duplicate charges or subscriptions. Partial
+	// refunds are DELETE requests with an amount_in_cents param, which are
+	// treated like POST for the same reason.
+	RetryPolicy struct {
+		// MaxAttempts is the total number of attempts made for a request,
+		// including the first one. Defaults to 3 if zero.
+		MaxAttempts int
+
+		// MinBackoff is the base delay before the first retry. Each retry
+		// doubles the delay, up to MaxBackoff. Defaults to 250ms if zero.
+		MinBackoff time.Duration
+
+		// MaxBackoff caps the delay between attempts, including a delay
+		// asked for with a Retry-After header. Defaults to 10s if zero.
+		MaxBackoff time.Duration
+
+		// RetryNonIdempotent enables retries for POST and PUT requests.
+		RetryNonIdempotent bool
+	}
+)
+
+// attempts returns the maximum number of attempts for a request.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts <= 0 {
+		return defaultRetryMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// canRetry returns true if req may be retried.
+func (p RetryPolicy) canRetry(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD":
+		return true
+	case "DELETE":
+		return req.URL.Query().Get("amount_in_cents") == "" || p.RetryNonIdempotent
+	case "POST", "PUT":
+		return p.RetryNonIdempotent
+	}
+	return false
+}
+
+// shouldRetry returns true if the result of an attempt warrants another one.
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns how long to wait before the given retry (starting at 1).
+// The delay grows exponentially with jitter so concurrent clients spread out.
+// A Retry-After header on the previous response takes precedence, up to
+// MaxBackoff.
+func (p RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultRetryMinBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > max {
+				d = max
+			}
+			return d
+		}
+	}
+
+	d := min
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// Equal jitter: wait at least half the delay.
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(time.Now())
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// send makes the HTTP call for req, retrying according to the client's
+// RetryPolicy. The request body is rebuilt from the encoded XML for every
+// attempt.
+func (c Client) send(req *http.Request) (*http.Response, error) {
+	if c.Retry == nil || !c.Retry.canRetry(req) {
+		return c.sendOnce(req)
+	}
+
+	ctx := req.Context()
+	policy := *c.Retry
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			r = req.WithContext(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := c.sendOnce(r)
+		if ctx.Err() != nil || attempt >= policy.attempts() || !policy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce makes a single HTTP call for req, first waiting for the client's
+// RateLimiter if it has one.
+func (c Client) sendOnce(req *http.Request) (*http.Response, error) {
+	if c.RateLimiter == nil {
+		return c.client.Do(req)
+	}
+
+	if err := c.RateLimiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	c.RateLimiter.Observe(resp)
+	return resp, err
+}
+
+// sleep pauses for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,273 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryIdempotentRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	r, a, err := client.Accounts.Get("1")
+	if err != nil {
+		t.Fatalf("TestRetryIdempotentRequests Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatalf("TestRetryIdempotentRequests Error: Expected response to be ok, given %d", r.StatusCode)
+	}
+
+	if attempts != 2 {
+		t.Errorf("TestRetryIdempotentRequests Error: Expected %d attempts, given %d", 2, attempts)
+	}
+
+	if a.Code != "1" {
+		t.Errorf("TestRetryIdempotentRequests Error: Expected account code %s, given %s", "1", a.Code)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+
+	r, err := client.Accounts.Close("1")
+	if err != nil {
+		t.Fatalf("TestRetryMaxAttempts Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if !r.IsServerError() {
+		t.Errorf("TestRetryMaxAttempts Error: Expected server error, given %d", r.StatusCode)
+	}
+
+	if attempts != 3 {
+		t.Errorf("TestRetryMaxAttempts Error: Expected %d attempts, given %d", 3, attempts)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description>Not found</description></error>`)
+	})
+
+	if _, _, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("TestRetryDoesNotRetryClientErrors Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("TestRetryDoesNotRetryClientErrors Error: Expected %d attempt, given %d", 1, attempts)
+	}
+}
+
+func TestRetryNonIdempotentRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var bodies []string
+	mux.HandleFunc("/v2/subscriptions", func(rw http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rw.WriteHeader(http.StatusCreated)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	// POST requests are not retried unless the policy opts in.
+	client.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+	r, _, err := client.Subscriptions.Create(NewSubscription{PlanCode: "gold", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("TestRetryNonIdempotentRequests Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.StatusCode != http.StatusBadGateway || len(bodies) != 1 {
+		t.Fatalf("TestRetryNonIdempotentRequests Error: Expected a single attempt with a 502, given %d attempts and %d", len(bodies), r.StatusCode)
+	}
+
+	bodies = nil
+	client.Retry = &RetryPolicy{MinBackoff: time.Millisecond, RetryNonIdempotent: true}
+	r, s, err := client.Subscriptions.Create(NewSubscription{PlanCode: "gold", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("TestRetryNonIdempotentRequests Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatalf("TestRetryNonIdempotentRequests Error: Expected response to be ok, given %d", r.StatusCode)
+	}
+
+	if s.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Errorf("TestRetryNonIdempotentRequests Error: Expected uuid %s, given %s", "44f83d7cba354d5b84812419f923ea96", s.UUID)
+	}
+
+	expected := "<subscription><plan_code>gold</plan_code><account></account><currency>USD</currency></subscription>"
+	if len(bodies) != 2 {
+		t.Fatalf("TestRetryNonIdempotentRequests Error: Expected %d attempts, given %d", 2, len(bodies))
+	}
+
+	for i, b := range bodies {
+		if b != expected {
+			t.Errorf("TestRetryNonIdempotentRequests Error (%d): Expected body %s, given %s", i, expected, b)
+		}
+	}
+}
+
+func TestRetryPartialRefunds(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	// A retried partial refund could refund the amount twice.
+	client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", 250)
+	if attempts != 1 {
+		t.Errorf("TestRetryPartialRefunds Error: Expected %d attempt for a partial refund, given %d", 1, attempts)
+	}
+
+	attempts = 0
+	client.Transactions.Void("a13acd8fe4294916b79aec87b7ea441f")
+	if attempts != 3 {
+		t.Errorf("TestRetryPartialRefunds Error: Expected %d attempts for a full refund, given %d", 3, attempts)
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/v2/plans/gold", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// Drop the connection without writing a response.
+			conn, _, _ := rw.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><plan><plan_code>gold</plan_code></plan>`)
+	})
+
+	r, p, err := client.Plans.Get("gold")
+	if err != nil {
+		t.Fatalf("TestRetryNetworkError Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() || p.Code != "gold" {
+		t.Errorf("TestRetryNetworkError Error: Expected plan gold with ok response, given %d and %s", r.StatusCode, p.Code)
+	}
+
+	if attempts != 2 {
+		t.Errorf("TestRetryNetworkError Error: Expected %d attempts, given %d", 2, attempts)
+	}
+}
+
+func TestRetryHonorsContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Retry = &RetryPolicy{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+
+	attempts := 0
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := client.Accounts.GetContext(ctx, "1")
+	if err != context.DeadlineExceeded {
+		t.Errorf("TestRetryHonorsContext Error: Expected error %v, given %v", context.DeadlineExceeded, err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("TestRetryHonorsContext Error: Expected %d attempt, given %d", 1, attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"retry": 1, "min": 50 * time.Millisecond, "max": 100 * time.Millisecond},
+		map[string]interface{}{"retry": 2, "min": 100 * time.Millisecond, "max": 200 * time.Millisecond},
+		map[string]interface{}{"retry": 3, "min": 200 * time.Millisecond, "max": 400 * time.Millisecond},
+		map[string]interface{}{"retry": 10, "min": 500 * time.Millisecond, "max": time.Second},
+	}
+
+	for i, s := range suite {
+		d := p.backoff(s["retry"].(int), nil)
+		if d < s["min"].(time.Duration) || d > s["max"].(time.Duration) {
+			t.Errorf("TestRetryBackoff Error (%d): Expected backoff between %s and %s, given %s", i, s["min"], s["max"], d)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	if d := (RetryPolicy{}).backoff(1, resp); d != 7*time.Second {
+		t.Errorf("TestRetryBackoff Error: Expected Retry-After backoff of %s, given %s", 7*time.Second, d)
+	}
+
+	// Retry-After can't make the client wait longer than MaxBackoff.
+	if d := p.backoff(1, resp); d != time.Second {
+		t.Errorf("TestRetryBackoff Error: Expected Retry-After backoff capped at %s, given %s", time.Second, d)
+	}
+	resp.Header.Set("Retry-After", "86400")
+	if d := (RetryPolicy{}).backoff(1, resp); d != defaultRetryMaxBackoff {
+		t.Errorf("TestRetryBackoff Error: Expected Retry-After backoff capped at %s, given %s", defaultRetryMaxBackoff, d)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if d := p.backoff(1, resp); d != 0 {
+		t.Errorf("TestRetryBackoff Error: Expected Retry-After date in the past to give %s, given %s", time.Duration(0), d)
+	}
+
+	resp.Header.Set("Retry-After", "soon")
+	if d := p.backoff(1, resp); d < 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("TestRetryBackoff Error: Expected invalid Retry-After to fall back to exponential backoff, given %s", d)
+	}
+}
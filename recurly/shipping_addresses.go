@@ -0,0 +1,206 @@
+package recurly
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+type (
+	// ShippingAddressesService handles communication with the shipping
+	// addresses related methods of the recurly API.
+	ShippingAddressesService interface {
+		List(accountCode string, params Params) (*Response, []ShippingAddress, error)
+		ListContext(ctx context.Context, accountCode string, params Params) (*Response, []ShippingAddress, error)
+		ListPager(accountCode string, params Params) *Pager[ShippingAddress]
+		Get(accountCode string, id int) (*Response, ShippingAddress, error)
+		GetContext(ctx context.Context, accountCode string, id int) (*Response, ShippingAddress, error)
+		Create(accountCode string, address ShippingAddress) (*Response, ShippingAddress, error)
+		CreateContext(ctx context.Context, accountCode string, address ShippingAddress) (*Response, ShippingAddress, error)
+		Update(accountCode string, id int, address ShippingAddress) (*Response, ShippingAddress, error)
+		UpdateContext(ctx context.Context, accountCode string, id int, address ShippingAddress) (*Response, ShippingAddress, error)
+		Delete(accountCode string, id int) (*Response, error)
+		DeleteContext(ctx context.Context, accountCode string, id int) (*Response, error)
+	}
+
+	// shippingAddressesImpl implements ShippingAddressesService.
+	shippingAddressesImpl struct {
+		client *Client
+	}
+
+	// ShippingAddress is an address an account's purchases can be shipped
+	// to. An account can have many shipping addresses.
+	ShippingAddress struct {
+		XMLName   xml.Name `xml:"shipping_address"`
+		Account   href     `xml:"account,omitempty"`
+		ID        int      `xml:"id,omitempty"`
+		Nickname  string   `xml:"nickname,omitempty"`
+		FirstName string   `xml:"first_name,omitempty"`
+		LastName  string   `xml:"last_name,omitempty"`
+		Company   string   `xml:"company,omitempty"`
+		Email     string   `xml:"email,omitempty"`
+		VATNumber string   `xml:"vat_number,omitempty"`
+		Address   string   `xml:"address1,omitempty"`
+		Address2  string   `xml:"address2,omitempty"`
+		City      string   `xml:"city,omitempty"`
+		State     string   `xml:"state,omitempty"`
+		Zip       string   `xml:"zip,omitempty"`
+		Country   string   `xml:"country,omitempty"`
+		Phone     string   `xml:"phone,omitempty"`
+		CreatedAt NullTime `xml:"created_at,omitempty"`
+		UpdatedAt NullTime `xml:"updated_at,omitempty"`
+	}
+
+	shippingAddressMarshaler struct {
+		XMLName   xml.Name `xml:"shipping_address"`
+		Nickname  string   `xml:"nickname,omitempty"`
+		FirstName string   `xml:"first_name,omitempty"`
+		LastName  string   `xml:"last_name,omitempty"`
+		Company   string   `xml:"company,omitempty"`
+		Email     string   `xml:"email,omitempty"`
+		VATNumber string   `xml:"vat_number,omitempty"`
+		Address   string   `xml:"address1,omitempty"`
+		Address2  string   `xml:"address2,omitempty"`
+		City      string   `xml:"city,omitempty"`
+		State     string   `xml:"state,omitempty"`
+		Zip       string   `xml:"zip,omitempty"`
+		Country   string   `xml:"country,omitempty"`
+		Phone     string   `xml:"phone,omitempty"`
+	}
+)
+
+// MarshalXML marshals only the fields needed for creating/updating shipping
+// addresses with the recurly API.
+func (a ShippingAddress) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	am := shippingAddressMarshaler{
+		Nickname:  a.Nickname,
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Company:   a.Company,
+		Email:     a.Email,
+		VATNumber: a.VATNumber,
+		Address:   a.Address,
+		Address2:  a.Address2,
+		City:      a.City,
+		State:     a.State,
+		Zip:       a.Zip,
+		Country:   a.Country,
+		Phone:     a.Phone,
+	}
+
+	return e.Encode(am)
+}
+
+// List returns the shipping addresses on an account.
+// https://dev.recurly.com/docs/list-accounts-shipping-address
+func (service shippingAddressesImpl) List(accountCode string, params Params) (*Response, []ShippingAddress, error) {
+	return service.ListContext(context.Background(), accountCode, params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service shippingAddressesImpl) ListContext(ctx context.Context, accountCode string, params Params) (*Response, []ShippingAddress, error) {
+	ctx = withOperation(ctx, "ShippingAddresses.List", "accounts/{account_code}/shipping_addresses")
+	action := fmt.Sprintf("accounts/%s/shipping_addresses", accountCode)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var a struct {
+		XMLName           xml.Name          `xml:"shipping_addresses"`
+		ShippingAddresses []ShippingAddress `xml:"shipping_address"`
+	}
+	res, err := service.client.do(req, &a)
+
+	return res, a.ShippingAddresses, err
+}
+
+// ListPager returns a Pager that walks every page of shipping addresses on an account.
+func (service shippingAddressesImpl) ListPager(accountCode string, params Params) *Pager[ShippingAddress] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []ShippingAddress, error) {
+		return service.ListContext(ctx, accountCode, params)
+	})
+}
+
+// Get returns a single shipping address on an account.
+// https://dev.recurly.com/docs/lookup-an-accounts-shipping-address
+func (service shippingAddressesImpl) Get(accountCode string, id int) (*Response, ShippingAddress, error) {
+	return service.GetContext(context.Background(), accountCode, id)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service shippingAddressesImpl) GetContext(ctx context.Context, accountCode string, id int) (*Response, ShippingAddress, error) {
+	ctx = withOperation(ctx, "ShippingAddresses.Get", "accounts/{account_code}/shipping_addresses/{shipping_address_id}")
+	action := fmt.Sprintf("accounts/%s/shipping_addresses/%d", accountCode, id)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
+	if err != nil {
+		return nil, ShippingAddress{}, err
+	}
+
+	var dest ShippingAddress
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Create adds a shipping address to an account.
+// https://dev.recurly.com/docs/create-shipping-address-on-an-account
+func (service shippingAddressesImpl) Create(accountCode string, address ShippingAddress) (*Response, ShippingAddress, error) {
+	return service.CreateContext(context.Background(), accountCode, address)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service shippingAddressesImpl) CreateContext(ctx context.Context, accountCode string, address ShippingAddress) (*Response, ShippingAddress, error) {
+	ctx = withOperation(ctx, "ShippingAddresses.Create", "accounts/{account_code}/shipping_addresses")
+	action := fmt.Sprintf("accounts/%s/shipping_addresses", accountCode)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, address)
+	if err != nil {
+		return nil, ShippingAddress{}, err
+	}
+
+	var dest ShippingAddress
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Update changes a shipping address on an account. Only the fields that are
+// set are updated.
+// https://dev.recurly.com/docs/edit-shipping-address-on-an-account
+func (service shippingAddressesImpl) Update(accountCode string, id int, address ShippingAddress) (*Response, ShippingAddress, error) {
+	return service.UpdateContext(context.Background(), accountCode, id, address)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service shippingAddressesImpl) UpdateContext(ctx context.Context, accountCode string, id int, address ShippingAddress) (*Response, ShippingAddress, error) {
+	ctx = withOperation(ctx, "ShippingAddresses.Update", "accounts/{account_code}/shipping_addresses/{shipping_address_id}")
+	action := fmt.Sprintf("accounts/%s/shipping_addresses/%d", accountCode, id)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, address)
+	if err != nil {
+		return nil, ShippingAddress{}, err
+	}
+
+	var dest ShippingAddress
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Delete removes a shipping address from an account. Subscriptions shipping
+// to the address are not affected.
+// https://dev.recurly.com/docs/delete-shipping-address-on-an-account
+func (service shippingAddressesImpl) Delete(accountCode string, id int) (*Response, error) {
+	return service.DeleteContext(context.Background(), accountCode, id)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service shippingAddressesImpl) DeleteContext(ctx context.Context, accountCode string, id int) (*Response, error) {
+	ctx = withOperation(ctx, "ShippingAddresses.Delete", "accounts/{account_code}/shipping_addresses/{shipping_address_id}")
+	action := fmt.Sprintf("accounts/%s/shipping_addresses/%d", accountCode, id)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.do(req, nil)
+}
@@ -0,0 +1,204 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestShippingAddressesEncoding ensures structs are encoded to XML properly.
+// Read only fields should never be sent to Recurly.
+func TestShippingAddressesEncoding(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"struct": ShippingAddress{}, "xml": "<shipping_address></shipping_address>"},
+		map[string]interface{}{"struct": ShippingAddress{ID: 1, Nickname: "Home", CreatedAt: NewTime(time.Now())}, "xml": "<shipping_address><nickname>Home</nickname></shipping_address>"},
+		map[string]interface{}{"struct": ShippingAddress{FirstName: "Verena", LastName: "Example", Company: "Recurly", Email: "verena@example.com", VATNumber: "123"}, "xml": "<shipping_address><first_name>Verena</first_name><last_name>Example</last_name><company>Recurly</company><email>verena@example.com</email><vat_number>123</vat_number></shipping_address>"},
+		map[string]interface{}{"struct": ShippingAddress{Address: "123 Main St.", Address2: "Suite 101", City: "San Francisco", State: "CA", Zip: "94105", Country: "US", Phone: "555-222-1212"}, "xml": "<shipping_address><address1>123 Main St.</address1><address2>Suite 101</address2><city>San Francisco</city><state>CA</state><zip>94105</zip><country>US</country><phone>555-222-1212</phone></shipping_address>"},
+	}
+
+	for _, s := range suite {
+		buf := new(bytes.Buffer)
+		err := xml.NewEncoder(buf).Encode(s["struct"])
+		if err != nil {
+			t.Errorf("TestShippingAddressesEncoding Error: %s", err)
+		}
+
+		if buf.String() != s["xml"] {
+			t.Errorf("TestShippingAddressesEncoding Error: Expected %s, given %s", s["xml"], buf.String())
+		}
+	}
+}
+
+func TestShippingAddressesList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/shipping_addresses", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestShippingAddressesList Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<shipping_addresses type="array">
+				<shipping_address href="https://your-subdomain.recurly.com/v2/accounts/1/shipping_addresses/2438622711411416831">
+					<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+					<subscriptions href="https://your-subdomain.recurly.com/v2/accounts/1/shipping_addresses/2438622711411416831/subscriptions"/>
+					<id type="integer">2438622711411416831</id>
+					<nickname>Work</nickname>
+					<first_name>Verena</first_name>
+					<last_name>Example</last_name>
+					<company>Recurly Inc</company>
+					<email>verena@example.com</email>
+					<vat_number></vat_number>
+					<address1>123 Main St.</address1>
+					<address2>Suite 101</address2>
+					<city>San Francisco</city>
+					<state>CA</state>
+					<zip>94105</zip>
+					<country>US</country>
+					<phone>555-222-1212</phone>
+					<created_at type="datetime">2016-03-30T20:40:56Z</created_at>
+					<updated_at type="datetime">2016-03-30T20:40:56Z</updated_at>
+				</shipping_address>
+			</shipping_addresses>`)
+	})
+
+	r, addresses, err := client.ShippingAddresses.List("1", nil)
+	if err != nil {
+		t.Errorf("TestShippingAddressesList Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestShippingAddressesList Error: Expected list shipping addresses to return OK")
+	}
+
+	ts, _ := time.Parse(datetimeFormat, "2016-03-30T20:40:56Z")
+	expected := []ShippingAddress{
+		ShippingAddress{
+			XMLName: xml.Name{Local: "shipping_address"},
+			Account: href{
+				HREF: "https://your-subdomain.recurly.com/v2/accounts/1",
+				Code: "1",
+			},
+			ID:        2438622711411416831,
+			Nickname:  "Work",
+			FirstName: "Verena",
+			LastName:  "Example",
+			Company:   "Recurly Inc",
+			Email:     "verena@example.com",
+			Address:   "123 Main St.",
+			Address2:  "Suite 101",
+			City:      "San Francisco",
+			State:     "CA",
+			Zip:       "94105",
+			Country:   "US",
+			Phone:     "555-222-1212",
+			CreatedAt: NewTime(ts),
+			UpdatedAt: NewTime(ts),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, addresses) {
+		t.Errorf("TestShippingAddressesList Error: expected shipping addresses to equal %#v, given %#v", expected, addresses)
+	}
+}
+
+func TestGetShippingAddress(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/shipping_addresses/2438622711411416831", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestGetShippingAddress Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<shipping_address href="https://your-subdomain.recurly.com/v2/accounts/1/shipping_addresses/2438622711411416831">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<id type="integer">2438622711411416831</id>
+				<nickname>Work</nickname>
+			</shipping_address>`)
+	})
+
+	r, address, err := client.ShippingAddresses.Get("1", 2438622711411416831)
+	if err != nil {
+		t.Errorf("TestGetShippingAddress Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestGetShippingAddress Error: Expected get shipping address to return OK")
+	}
+
+	if address.ID != 2438622711411416831 || address.Nickname != "Work" || address.Account.Code != "1" {
+		t.Errorf("TestGetShippingAddress Error: Unexpected shipping address, given %#v", address)
+	}
+}
+
+func TestCreateShippingAddress(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/shipping_addresses", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestCreateShippingAddress Error: Expected %s request, given %s", "POST", r.Method)
+		}
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><shipping_address></shipping_address>`)
+	})
+
+	r, _, err := client.ShippingAddresses.Create("1", ShippingAddress{Nickname: "Home"})
+	if err != nil {
+		t.Errorf("TestCreateShippingAddress Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestCreateShippingAddress Error: Expected create shipping address to return OK")
+	}
+}
+
+func TestUpdateShippingAddress(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/shipping_addresses/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestUpdateShippingAddress Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><shipping_address></shipping_address>`)
+	})
+
+	r, _, err := client.ShippingAddresses.Update("1", 5, ShippingAddress{Nickname: "Home"})
+	if err != nil {
+		t.Errorf("TestUpdateShippingAddress Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestUpdateShippingAddress Error: Expected update shipping address to return OK")
+	}
+}
+
+func TestDeleteShippingAddress(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/shipping_addresses/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestDeleteShippingAddress Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+		rw.WriteHeader(204)
+	})
+
+	r, err := client.ShippingAddresses.Delete("1", 5)
+	if err != nil {
+		t.Errorf("TestDeleteShippingAddress Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestDeleteShippingAddress Error: Expected delete shipping address to return OK")
+	}
+}
@@ -0,0 +1,117 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Sites is a registry of clients for multiple Recurly sites, such as one
+	// site per brand. Each site is registered under a key with its own
+	// subdomain and API key, and every client shares the registry's
+	// http.Client so connections are pooled across sites. Sites is safe for
+	// concurrent use.
+	Sites struct {
+		client *http.Client
+
+		mu    sync.RWMutex
+		sites map[string]*Client
+	}
+)
+
+// ErrUnknownSite is returned when a site key has not been registered.
+var ErrUnknownSite = errors.New("recurly: unknown site")
+
+// NewSites creates an empty registry. If httpClient is nil, a client is
+// created with a transport tuned for keeping connections open to several
+// Recurly subdomains at once.
+func NewSites(httpClient *http.Client) *Sites {
+	if httpClient == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConns = 100
+		t.MaxIdleConnsPerHost = 10
+		t.IdleConnTimeout = 90 * time.Second
+		httpClient = &http.Client{Transport: t}
+	}
+
+	return &Sites{
+		client: httpClient,
+		sites:  make(map[string]*Client),
+	}
+}
+
+// HTTPClient returns the http.Client shared by every site.
+func (s *Sites) HTTPClient() *http.Client {
+	return s.client
+}
+
+// Add registers a site under key and returns its client. Options such as
+// TypedErrors and Retry can be set on the returned client before it is
+// used. It returns an error if key is already registered.
+func (s *Sites) Add(key string, subDomain string, apiKey string) (*Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sites[key]; ok {
+		return nil, fmt.Errorf("recurly: site %q is already registered", key)
+	}
+
+	c := NewClient(subDomain, apiKey, s.client)
+	s.sites[key] = c
+
+	return c, nil
+}
+
+// Get returns the client for the site registered under key. The error wraps
+// ErrUnknownSite if there is no such site.
+func (s *Sites) Get(key string) (*Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.sites[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSite, key)
+	}
+
+	return c, nil
+}
+
+// Remove unregisters the site under key. Requests already made with its
+// client are not affected.
+func (s *Sites) Remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sites, key)
+}
+
+// Keys returns the keys of the registered sites in sorted order.
+func (s *Sites) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.sites))
+	for k := range s.sites {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// RotateKey replaces the API key of the site registered under key. The
+// site's client is updated in place, so callers holding it pick up the new
+// key on their next request, and requests already in flight finish with the
+// old one.
+func (s *Sites) RotateKey(key string, apiKey string) error {
+	c, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+
+	c.SetAPIKey(apiKey)
+	return nil
+}
@@ -0,0 +1,144 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSites(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		key, _, _ := r.BasicAuth()
+		rw.WriteHeader(200)
+		fmt.Fprintf(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code><company_name>%s</company_name></account>`, key)
+	})
+
+	sites := NewSites(nil)
+	for _, key := range []string{"brand-b", "brand-a"} {
+		c, err := sites.Add(key, key, key+"-key")
+		if err != nil {
+			t.Fatalf("TestSites Error: Error adding site. Err: %s", err)
+		}
+		c.BaseURL = server.URL + "/"
+	}
+
+	if _, err := sites.Add("brand-a", "brand-a", "other"); err == nil {
+		t.Error("TestSites Error: Expected error adding a site twice")
+	}
+
+	if given := sites.Keys(); !reflect.DeepEqual(given, []string{"brand-a", "brand-b"}) {
+		t.Errorf("TestSites Error: Expected sorted site keys, given %v", given)
+	}
+
+	a, _ := sites.Get("brand-a")
+	b, _ := sites.Get("brand-b")
+	if a.client != sites.HTTPClient() || b.client != sites.HTTPClient() {
+		t.Error("TestSites Error: Expected every site to share the http.Client")
+	}
+
+	if a.subDomain != "brand-a" || b.BaseURL != server.URL+"/" {
+		t.Errorf("TestSites Error: Expected per-site clients, given %#v and %#v", a, b)
+	}
+
+	if _, acct, _ := b.Accounts.Get("1"); acct.CompanyName != "brand-b-key" {
+		t.Errorf("TestSites Error: Expected request with brand-b-key, given %s", acct.CompanyName)
+	}
+
+	sites.Remove("brand-b")
+	if _, err := sites.Get("brand-b"); !errors.Is(err, ErrUnknownSite) {
+		t.Errorf("TestSites Error: Expected ErrUnknownSite, given %v", err)
+	}
+
+	if err := sites.RotateKey("brand-b", "new"); !errors.Is(err, ErrUnknownSite) {
+		t.Errorf("TestSites Error: Expected ErrUnknownSite rotating a removed site, given %v", err)
+	}
+}
+
+func TestSitesRotateKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	started := make(chan struct{})
+	rotated := make(chan struct{})
+	mux.HandleFunc("/v2/accounts/1", func(rw http.ResponseWriter, r *http.Request) {
+		key, _, _ := r.BasicAuth()
+		if key == "old" {
+			close(started)
+			<-rotated
+		}
+		rw.WriteHeader(200)
+		fmt.Fprintf(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code><company_name>%s</company_name></account>`, key)
+	})
+
+	sites := NewSites(nil)
+	c, _ := sites.Add("brand", "brand", "old")
+	c.BaseURL = server.URL + "/"
+
+	done := make(chan string)
+	go func() {
+		_, a, _ := c.Accounts.Get("1")
+		done <- a.CompanyName
+	}()
+
+	// Rotate while the first request is in flight.
+	<-started
+	if err := sites.RotateKey("brand", "new"); err != nil {
+		t.Fatalf("TestSitesRotateKey Error: Error rotating key. Err: %s", err)
+	}
+	close(rotated)
+
+	if given := <-done; given != "old" {
+		t.Errorf("TestSitesRotateKey Error: Expected in-flight request to finish with the old key, given %s", given)
+	}
+
+	if _, a, _ := c.Accounts.Get("1"); a.CompanyName != "new" {
+		t.Errorf("TestSitesRotateKey Error: Expected next request to use the new key, given %s", a.CompanyName)
+	}
+}
+
+func TestSitesReuseConnections(t *testing.T) {
+	var mu sync.Mutex
+	var opened int
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			opened++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	sites := NewSites(nil)
+	for _, key := range []string{"brand-a", "brand-b"} {
+		c, _ := sites.Add(key, key, key+"-key")
+		c.BaseURL = srv.URL + "/"
+	}
+
+	for i := 0; i < 3; i++ {
+		for _, key := range sites.Keys() {
+			c, _ := sites.Get(key)
+			if _, _, err := c.Accounts.Get("1"); err != nil {
+				t.Fatalf("TestSitesReuseConnections Error: Error occurred making API call. Err: %s", err)
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if opened != 1 {
+		t.Errorf("TestSitesReuseConnections Error: Expected sequential requests to reuse one connection, given %d connections", opened)
+	}
+}
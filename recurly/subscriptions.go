@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"time"
@@ -9,7 +10,49 @@ import (
 type (
 	// SubscriptionsService handles communication with the subscription related methods
 	// of the recurly API.
-	SubscriptionsService struct {
+	SubscriptionsService interface {
+		List(params Params) (*Response, []Subscription, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Subscription, error)
+		ListPager(params Params) *Pager[Subscription]
+		ListAccount(accountCode string, params Params) (*Response, []Subscription, error)
+		ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Subscription, error)
+		ListAccountPager(accountCode string, params Params) *Pager[Subscription]
+		Get(uuid string) (*Response, Subscription, error)
+		GetContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		Create(s NewSubscription) (*Response, Subscription, error)
+		CreateContext(ctx context.Context, s NewSubscription) (*Response, Subscription, error)
+		Preview(s NewSubscription) (*Response, Subscription, error)
+		PreviewContext(ctx context.Context, s NewSubscription) (*Response, Subscription, error)
+		Update(uuid string, s UpdateSubscription) (*Response, Subscription, error)
+		UpdateContext(ctx context.Context, uuid string, s UpdateSubscription) (*Response, Subscription, error)
+		UpdateNotes(uuid string, n SubscriptionNotes) (*Response, Subscription, error)
+		UpdateNotesContext(ctx context.Context, uuid string, n SubscriptionNotes) (*Response, Subscription, error)
+		PreviewChange(uuid string, s UpdateSubscription) (*Response, Subscription, error)
+		PreviewChangeContext(ctx context.Context, uuid string, s UpdateSubscription) (*Response, Subscription, error)
+		Cancel(uuid string) (*Response, Subscription, error)
+		CancelContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		Reactivate(uuid string) (*Response, Subscription, error)
+		ReactivateContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		TerminateWithPartialRefund(uuid string) (*Response, Subscription, error)
+		TerminateWithPartialRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		TerminateWithFullRefund(uuid string) (*Response, Subscription, error)
+		TerminateWithFullRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		TerminateWithoutRefund(uuid string) (*Response, Subscription, error)
+		TerminateWithoutRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		Postpone(uuid string, dt time.Time, bulk bool) (*Response, Subscription, error)
+		PostponeContext(ctx context.Context, uuid string, dt time.Time, bulk bool) (*Response, Subscription, error)
+		Pause(uuid string, cycles int) (*Response, Subscription, error)
+		PauseContext(ctx context.Context, uuid string, cycles int) (*Response, Subscription, error)
+		RemovePause(uuid string) (*Response, Subscription, error)
+		RemovePauseContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		Resume(uuid string) (*Response, Subscription, error)
+		ResumeContext(ctx context.Context, uuid string) (*Response, Subscription, error)
+		CancelPendingChange(uuid string) (*Response, error)
+		CancelPendingChangeContext(ctx context.Context, uuid string) (*Response, error)
+	}
+
+	// subscriptionsImpl implements SubscriptionsService.
+	subscriptionsImpl struct {
 		client *Client
 	}
 
@@ -37,7 +80,27 @@ type (
 		TaxRate                float64             `xml:"tax_rate,omitempty"`
 		PONumber               string              `xml:"po_number,omitempty"`
 		NetTerms               NullInt             `xml:"net_terms,omitempty"`
-		SubscriptionAddOns     []SubscriptionAddOn `xml:"subscriptions_add_ons,omitempty"`
+		TermsAndConditions     string              `xml:"terms_and_conditions,omitempty"`
+		CustomerNotes          string              `xml:"customer_notes,omitempty"`
+		VATReverseChargeNotes  string              `xml:"vat_reverse_charge_notes,omitempty"`
+		SubscriptionAddOns     []SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
+		PausedAt               NullTime            `xml:"paused_at,omitempty"`
+		ResumeAt               NullTime            `xml:"resume_at,omitempty"`
+		RemainingPauseCycles   int                 `xml:"remaining_pause_cycles,omitempty"`
+
+		// PendingSubscription is the change scheduled by an update with a
+		// Timeframe of "renewal", or nil if there is none.
+		PendingSubscription *PendingSubscription `xml:"pending_subscription,omitempty"`
+	}
+
+	// PendingSubscription describes the plan, pricing and add ons a
+	// subscription changes to at its next renewal.
+	PendingSubscription struct {
+		XMLName            xml.Name            `xml:"pending_subscription"`
+		Plan               nestedPlan          `xml:"plan,omitempty"`
+		UnitAmountInCents  int                 `xml:"unit_amount_in_cents,omitempty"`
+		Quantity           int                 `xml:"quantity,omitempty"`
+		SubscriptionAddOns []SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
 	}
 
 	nestedPlan struct {
@@ -52,6 +115,7 @@ type (
 		Code              string   `xml:"add_on_code"`
 		UnitAmountInCents int      `xml:"unit_amount_in_cents"`
 		Quantity          int      `xml:"quantity,omitempty"`
+		UsagePercentage   string   `xml:"usage_percentage,omitempty"`
 	}
 
 	// NewSubscription is used to create new subscriptions.
@@ -76,6 +140,12 @@ type (
 		CustomerNotes           string               `xml:"customer_notes,omitempty"`
 		VATReverseChargeNotes   string               `xml:"vat_reverse_charge_notes,omitempty"`
 		BankAccountAuthorizedAt NullTime             `xml:"bank_account_authorized_at,omitempty"`
+
+		// The subscription ships to an existing shipping address on the
+		// account when ShippingAddressID is set, or to ShippingAddress,
+		// which is added to the account, otherwise.
+		ShippingAddressID int              `xml:"shipping_address_id,omitempty"`
+		ShippingAddress   *ShippingAddress `xml:"shipping_address,omitempty"`
 	}
 
 	// UpdateSubscription is used to update subscriptions
@@ -98,6 +168,12 @@ type (
 		CustomerNotes         string   `xml:"customer_notes,omitempty"`
 		VATReverseChargeNotes string   `xml:"vat_reverse_charge_notes,omitempty"`
 	}
+
+	// pauseSubscription is the request body for pausing a subscription.
+	pauseSubscription struct {
+		XMLName              xml.Name `xml:"subscription"`
+		RemainingPauseCycles int      `xml:"remaining_pause_cycles"`
+	}
 )
 
 const (
@@ -126,6 +202,10 @@ const (
 	// SubscriptionStatePastDue are subscriptions that are active or canceled
 	// and have a past-due invoice
 	SubscriptionStatePastDue = "past_due"
+
+	// SubscriptionStatePaused are subscriptions that are paused for a number
+	// of billing cycles and will resume at ResumeAt
+	SubscriptionStatePaused = "paused"
 )
 
 // MakeUpdate creates an UpdateSubscription with values that need to be passed
@@ -141,10 +221,65 @@ func (s Subscription) MakeUpdate() UpdateSubscription {
 	}
 }
 
+// Validate checks the subscription for mistakes Recurly would reject, such
+// as a missing plan code, currency or account code, an unknown currency or
+// country, or negative amounts. It returns a *ValidationError listing every
+// problem found, or nil. See Client.ValidateRequests.
+func (s NewSubscription) Validate() error {
+	var v validation
+	v.required("subscription.plan_code", s.PlanCode != "")
+	v.required("subscription.currency", s.Currency != "")
+	v.currency("subscription.currency", s.Currency)
+	v.account("subscription.account", s.Account)
+	v.nonNegative("subscription.unit_amount_in_cents", s.UnitAmountInCents)
+	v.nonNegative("subscription.quantity", s.Quantity)
+	v.nonNegative("subscription.total_billing_cycles", s.TotalBillingCycles)
+	v.oneOf("subscription.collection_method", s.CollectionMethod, "automatic", "manual")
+	if s.SubscriptionAddOns != nil {
+		v.addOns("subscription.subscription_add_ons", *s.SubscriptionAddOns)
+	}
+	if s.ShippingAddress != nil {
+		v.country("subscription.shipping_address.country", s.ShippingAddress.Country)
+	}
+
+	return v.err()
+}
+
+// Validate checks the update for mistakes Recurly would reject, such as an
+// unknown timeframe or negative amounts. It returns a *ValidationError
+// listing every problem found, or nil. See Client.ValidateRequests.
+func (s UpdateSubscription) Validate() error {
+	var v validation
+	v.oneOf("subscription.timeframe", s.Timeframe, "now", "renewal", "bill_date")
+	v.nonNegative("subscription.unit_amount_in_cents", s.UnitAmountInCents)
+	v.nonNegative("subscription.quantity", s.Quantity)
+	v.oneOf("subscription.collection_method", s.CollectionMethod, "automatic", "manual")
+	if s.SubscriptionAddOns != nil {
+		v.addOns("subscription.subscription_add_ons", *s.SubscriptionAddOns)
+	}
+
+	return v.err()
+}
+
+// addOns records the errors in the add ons of a subscription.
+func (v *validation) addOns(prefix string, addOns []SubscriptionAddOn) {
+	for _, a := range addOns {
+		v.required(prefix+".add_on_code", a.Code != "")
+		v.nonNegative(prefix+".unit_amount_in_cents", a.UnitAmountInCents)
+		v.nonNegative(prefix+".quantity", a.Quantity)
+	}
+}
+
 // List returns a list of all the subscriptions.
 // https://docs.recurly.com/api/subscriptions#list-subscriptions
-func (service SubscriptionsService) List(params Params) (*Response, []Subscription, error) {
-	req, err := service.client.newRequest("GET", "subscriptions", params, nil)
+func (service subscriptionsImpl) List(params Params) (*Response, []Subscription, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service subscriptionsImpl) ListContext(ctx context.Context, params Params) (*Response, []Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.List", "subscriptions")
+	req, err := service.client.newRequest(ctx, "GET", "subscriptions", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -158,11 +293,24 @@ func (service SubscriptionsService) List(params Params) (*Response, []Subscripti
 	return res, s.Subscriptions, err
 }
 
+// ListPager returns a Pager that walks every page of subscriptions.
+func (service subscriptionsImpl) ListPager(params Params) *Pager[Subscription] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Subscription, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // ListAccount returns a list of subscriptions for an account.
 // https://docs.recurly.com/api/subscriptions#list-account-subscriptions
-func (service SubscriptionsService) ListAccount(accountCode string, params Params) (*Response, []Subscription, error) {
+func (service subscriptionsImpl) ListAccount(accountCode string, params Params) (*Response, []Subscription, error) {
+	return service.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext is the same as ListAccount, but uses ctx for the request.
+func (service subscriptionsImpl) ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.ListAccount", "accounts/{account_code}/subscriptions")
 	action := fmt.Sprintf("accounts/%s/subscriptions", accountCode)
-	req, err := service.client.newRequest("GET", action, params, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -176,11 +324,24 @@ func (service SubscriptionsService) ListAccount(accountCode string, params Param
 	return res, s.Subscriptions, err
 }
 
+// ListAccountPager returns a Pager that walks every page of subscriptions for an account.
+func (service subscriptionsImpl) ListAccountPager(accountCode string, params Params) *Pager[Subscription] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Subscription, error) {
+		return service.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
 // Get returns a subscription by uuid
 // https://docs.recurly.com/api/subscriptions#lookup-subscription
-func (service SubscriptionsService) Get(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) Get(uuid string) (*Response, Subscription, error) {
+	return service.GetContext(context.Background(), uuid)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service subscriptionsImpl) GetContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Get", "subscriptions/{uuid}")
 	action := fmt.Sprintf("subscriptions/%s", uuid)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -193,8 +354,14 @@ func (service SubscriptionsService) Get(uuid string) (*Response, Subscription, e
 
 // Create creates a new subscription.
 // https://docs.recurly.com/api/subscriptions#create-subscription
-func (service SubscriptionsService) Create(s NewSubscription) (*Response, Subscription, error) {
-	req, err := service.client.newRequest("POST", "subscriptions", nil, s)
+func (service subscriptionsImpl) Create(s NewSubscription) (*Response, Subscription, error) {
+	return service.CreateContext(context.Background(), s)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service subscriptionsImpl) CreateContext(ctx context.Context, s NewSubscription) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Create", "subscriptions")
+	req, err := service.client.newRequest(ctx, "POST", "subscriptions", nil, s)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -207,8 +374,14 @@ func (service SubscriptionsService) Create(s NewSubscription) (*Response, Subscr
 
 // Preview returns a preview for a new subscription applied to an account.
 // https://docs.recurly.com/api/subscriptions#preview-sub
-func (service SubscriptionsService) Preview(s NewSubscription) (*Response, Subscription, error) {
-	req, err := service.client.newRequest("POST", "subscriptions/preview", nil, s)
+func (service subscriptionsImpl) Preview(s NewSubscription) (*Response, Subscription, error) {
+	return service.PreviewContext(context.Background(), s)
+}
+
+// PreviewContext is the same as Preview, but uses ctx for the request.
+func (service subscriptionsImpl) PreviewContext(ctx context.Context, s NewSubscription) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Preview", "subscriptions/preview")
+	req, err := service.client.newRequest(ctx, "POST", "subscriptions/preview", nil, s)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -225,9 +398,15 @@ func (service SubscriptionsService) Preview(s NewSubscription) (*Response, Subsc
 // identically. If updating SubscriptionAddOns, you should provide the entire replacement
 // value. See recurly documentation for more info.
 // https://docs.recurly.com/api/subscriptions#update-subscription
-func (service SubscriptionsService) Update(uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+func (service subscriptionsImpl) Update(uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+	return service.UpdateContext(context.Background(), uuid, s)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service subscriptionsImpl) UpdateContext(ctx context.Context, uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Update", "subscriptions/{uuid}")
 	action := fmt.Sprintf("subscriptions/%s", uuid)
-	req, err := service.client.newRequest("PUT", action, nil, s)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, s)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -241,9 +420,15 @@ func (service SubscriptionsService) Update(uuid string, s UpdateSubscription) (*
 // UpdateNotes updates a subscription's invoice notes before the next renewal.
 // Updating notes will not trigger the renewal.
 // https://docs.recurly.com/api/subscriptions#update-subscription-notes
-func (service SubscriptionsService) UpdateNotes(uuid string, n SubscriptionNotes) (*Response, Subscription, error) {
+func (service subscriptionsImpl) UpdateNotes(uuid string, n SubscriptionNotes) (*Response, Subscription, error) {
+	return service.UpdateNotesContext(context.Background(), uuid, n)
+}
+
+// UpdateNotesContext is the same as UpdateNotes, but uses ctx for the request.
+func (service subscriptionsImpl) UpdateNotesContext(ctx context.Context, uuid string, n SubscriptionNotes) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.UpdateNotes", "subscriptions/{uuid}/notes")
 	action := fmt.Sprintf("subscriptions/%s/notes", uuid)
-	req, err := service.client.newRequest("PUT", action, nil, n)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, n)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -257,9 +442,15 @@ func (service SubscriptionsService) UpdateNotes(uuid string, n SubscriptionNotes
 // PreviewChange returns a preview for a subscription change applied to an
 // account without committing a subscription change or posting an invoice.
 // https://docs.recurly.com/api/subscriptions#sub-change-preview
-func (service SubscriptionsService) PreviewChange(uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+func (service subscriptionsImpl) PreviewChange(uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+	return service.PreviewChangeContext(context.Background(), uuid, s)
+}
+
+// PreviewChangeContext is the same as PreviewChange, but uses ctx for the request.
+func (service subscriptionsImpl) PreviewChangeContext(ctx context.Context, uuid string, s UpdateSubscription) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.PreviewChange", "subscriptions/{uuid}/preview")
 	action := fmt.Sprintf("subscriptions/%s/preview", uuid)
-	req, err := service.client.newRequest("POST", action, nil, s)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, s)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -273,9 +464,15 @@ func (service SubscriptionsService) PreviewChange(uuid string, s UpdateSubscript
 // Cancel cancels a subscription so it remains active and then expires at the
 // end of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#cancel-subscription
-func (service SubscriptionsService) Cancel(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) Cancel(uuid string) (*Response, Subscription, error) {
+	return service.CancelContext(context.Background(), uuid)
+}
+
+// CancelContext is the same as Cancel, but uses ctx for the request.
+func (service subscriptionsImpl) CancelContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Cancel", "subscriptions/{uuid}/cancel")
 	action := fmt.Sprintf("subscriptions/%s/cancel", uuid)
-	req, err := service.client.newRequest("PUT", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -289,9 +486,15 @@ func (service SubscriptionsService) Cancel(uuid string) (*Response, Subscription
 // Reactivate will reactivate a canceled subscription so it renews at the end
 // of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#reactivate-subscription
-func (service SubscriptionsService) Reactivate(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) Reactivate(uuid string) (*Response, Subscription, error) {
+	return service.ReactivateContext(context.Background(), uuid)
+}
+
+// ReactivateContext is the same as Reactivate, but uses ctx for the request.
+func (service subscriptionsImpl) ReactivateContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Reactivate", "subscriptions/{uuid}/reactivate")
 	action := fmt.Sprintf("subscriptions/%s/reactivate", uuid)
-	req, err := service.client.newRequest("PUT", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -305,9 +508,15 @@ func (service SubscriptionsService) Reactivate(uuid string) (*Response, Subscrip
 // TerminateWithPartialRefund will terminate the active subscription
 // immediately with a full refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (service SubscriptionsService) TerminateWithPartialRefund(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) TerminateWithPartialRefund(uuid string) (*Response, Subscription, error) {
+	return service.TerminateWithPartialRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithPartialRefundContext is the same as TerminateWithPartialRefund, but uses ctx for the request.
+func (service subscriptionsImpl) TerminateWithPartialRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.TerminateWithPartialRefund", "subscriptions/{uuid}/terminate")
 	action := fmt.Sprintf("subscriptions/%s/terminate", uuid)
-	req, err := service.client.newRequest("PUT", action, Params{"refund_type": "partial"}, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, Params{"refund_type": "partial"}, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -321,9 +530,15 @@ func (service SubscriptionsService) TerminateWithPartialRefund(uuid string) (*Re
 // TerminateWithFullRefund will terminate the active subscription
 // immediately with a full refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (service SubscriptionsService) TerminateWithFullRefund(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) TerminateWithFullRefund(uuid string) (*Response, Subscription, error) {
+	return service.TerminateWithFullRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithFullRefundContext is the same as TerminateWithFullRefund, but uses ctx for the request.
+func (service subscriptionsImpl) TerminateWithFullRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.TerminateWithFullRefund", "subscriptions/{uuid}/terminate")
 	action := fmt.Sprintf("subscriptions/%s/terminate", uuid)
-	req, err := service.client.newRequest("PUT", action, Params{"refund_type": "full"}, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, Params{"refund_type": "full"}, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -337,9 +552,15 @@ func (service SubscriptionsService) TerminateWithFullRefund(uuid string) (*Respo
 // TerminateWithoutRefund will terminate the active subscription
 // immediately with no refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (service SubscriptionsService) TerminateWithoutRefund(uuid string) (*Response, Subscription, error) {
+func (service subscriptionsImpl) TerminateWithoutRefund(uuid string) (*Response, Subscription, error) {
+	return service.TerminateWithoutRefundContext(context.Background(), uuid)
+}
+
+// TerminateWithoutRefundContext is the same as TerminateWithoutRefund, but uses ctx for the request.
+func (service subscriptionsImpl) TerminateWithoutRefundContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.TerminateWithoutRefund", "subscriptions/{uuid}/terminate")
 	action := fmt.Sprintf("subscriptions/%s/terminate", uuid)
-	req, err := service.client.newRequest("PUT", action, Params{"refund_type": "none"}, nil)
+	req, err := service.client.newRequest(ctx, "PUT", action, Params{"refund_type": "none"}, nil)
 	if err != nil {
 		return nil, Subscription{}, err
 	}
@@ -354,9 +575,15 @@ func (service SubscriptionsService) TerminateWithoutRefund(uuid string) (*Respon
 // The subscription will not be prorated. For a subscription in a trial period,
 // modifying the renewal date will modify when the trial expires.
 // https://docs.recurly.com/api/subscriptions#postpone-subscription
-func (service SubscriptionsService) Postpone(uuid string, dt time.Time, bulk bool) (*Response, Subscription, error) {
+func (service subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool) (*Response, Subscription, error) {
+	return service.PostponeContext(context.Background(), uuid, dt, bulk)
+}
+
+// PostponeContext is the same as Postpone, but uses ctx for the request.
+func (service subscriptionsImpl) PostponeContext(ctx context.Context, uuid string, dt time.Time, bulk bool) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Postpone", "subscriptions/{uuid}/postpone")
 	action := fmt.Sprintf("subscriptions/%s/postpone", uuid)
-	req, err := service.client.newRequest("PUT", action, Params{
+	req, err := service.client.newRequest(ctx, "PUT", action, Params{
 		"bulk":              bulk,
 		"next_renewal_date": dt.Format(time.RFC3339),
 	}, nil)
@@ -370,6 +597,101 @@ func (service SubscriptionsService) Postpone(uuid string, dt time.Time, bulk boo
 	return res, dest, err
 }
 
+// Pause schedules an active subscription to pause for the given number of
+// billing cycles, starting at its next renewal. Pausing a paused
+// subscription changes the number of cycles left before it resumes. cycles
+// must be at least 1, or a *ValidationError is returned without making a
+// request; use RemovePause to remove a scheduled pause.
+// https://dev.recurly.com/docs/pause-subscription
+func (service subscriptionsImpl) Pause(uuid string, cycles int) (*Response, Subscription, error) {
+	return service.PauseContext(context.Background(), uuid, cycles)
+}
+
+// PauseContext is the same as Pause, but uses ctx for the request.
+func (service subscriptionsImpl) PauseContext(ctx context.Context, uuid string, cycles int) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Pause", "subscriptions/{uuid}/pause")
+
+	// Zero cycles would remove the pause, which is RemovePause's job.
+	if cycles < 1 {
+		var v validation
+		v.add("subscription.remaining_pause_cycles", "greater_than", "must be greater than 0")
+		return nil, Subscription{}, v.err()
+	}
+
+	return service.pause(ctx, uuid, cycles)
+}
+
+// RemovePause removes a pause that is scheduled but hasn't started yet. Use
+// Resume for subscriptions that are already paused.
+// https://dev.recurly.com/docs/pause-subscription
+func (service subscriptionsImpl) RemovePause(uuid string) (*Response, Subscription, error) {
+	return service.RemovePauseContext(context.Background(), uuid)
+}
+
+// RemovePauseContext is the same as RemovePause, but uses ctx for the request.
+func (service subscriptionsImpl) RemovePauseContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.RemovePause", "subscriptions/{uuid}/pause")
+	return service.pause(ctx, uuid, 0)
+}
+
+// pause sets the remaining pause cycles of a subscription. Recurly removes
+// a scheduled pause when the cycles are 0.
+func (service subscriptionsImpl) pause(ctx context.Context, uuid string, cycles int) (*Response, Subscription, error) {
+	action := fmt.Sprintf("subscriptions/%s/pause", uuid)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, pauseSubscription{
+		RemainingPauseCycles: cycles,
+	})
+	if err != nil {
+		return nil, Subscription{}, err
+	}
+
+	var dest Subscription
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Resume immediately resumes a paused subscription, starting a new billing
+// cycle.
+// https://dev.recurly.com/docs/resume-subscription
+func (service subscriptionsImpl) Resume(uuid string) (*Response, Subscription, error) {
+	return service.ResumeContext(context.Background(), uuid)
+}
+
+// ResumeContext is the same as Resume, but uses ctx for the request.
+func (service subscriptionsImpl) ResumeContext(ctx context.Context, uuid string) (*Response, Subscription, error) {
+	ctx = withOperation(ctx, "Subscriptions.Resume", "subscriptions/{uuid}/resume")
+	action := fmt.Sprintf("subscriptions/%s/resume", uuid)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, nil)
+	if err != nil {
+		return nil, Subscription{}, err
+	}
+
+	var dest Subscription
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// CancelPendingChange removes the change scheduled for a subscription's next
+// renewal by an update with a Timeframe of "renewal". See
+// Subscription.PendingSubscription.
+func (service subscriptionsImpl) CancelPendingChange(uuid string) (*Response, error) {
+	return service.CancelPendingChangeContext(context.Background(), uuid)
+}
+
+// CancelPendingChangeContext is the same as CancelPendingChange, but uses ctx for the request.
+func (service subscriptionsImpl) CancelPendingChangeContext(ctx context.Context, uuid string) (*Response, error) {
+	ctx = withOperation(ctx, "Subscriptions.CancelPendingChange", "subscriptions/{uuid}/pending")
+	action := fmt.Sprintf("subscriptions/%s/pending", uuid)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.do(req, nil)
+}
+
 // Note: Create/Update Subscription with AddOns and Create/Update manual invoice
 // are the same endpoint as Create. You just need to include additional parameters
 // for each method. See the documentation here:
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
@@ -179,6 +180,22 @@ func TestSubscriptionsEncoding(t *testing.T) {
 			},
 			BankAccountAuthorizedAt: NewTime(ts),
 		}, "xml": "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><bank_account_authorized_at>2015-06-03T13:42:23Z</bank_account_authorized_at></subscription>"},
+		map[string]interface{}{"struct": NewSubscription{
+			PlanCode: "gold",
+			Currency: "USD",
+			Account: Account{
+				Code: "123",
+			},
+			ShippingAddressID: 2438622711411416831,
+		}, "xml": "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><shipping_address_id>2438622711411416831</shipping_address_id></subscription>"},
+		map[string]interface{}{"struct": NewSubscription{
+			PlanCode: "gold",
+			Currency: "USD",
+			Account: Account{
+				Code: "123",
+			},
+			ShippingAddress: &ShippingAddress{ID: 1, FirstName: "Verena", Address: "123 Main St.", City: "San Francisco", Zip: "94105", Country: "US"},
+		}, "xml": "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><shipping_address><first_name>Verena</first_name><address1>123 Main St.</address1><city>San Francisco</city><zip>94105</zip><country>US</country></shipping_address></subscription>"},
 
 		// Update Subscription Tests
 		map[string]interface{}{"struct": UpdateSubscription{}, "xml": "<subscription></subscription>"},
@@ -459,6 +476,9 @@ func TestGetSubscriptions(t *testing.T) {
 			<tax_rate type="float">0.0875</tax_rate>
 			<po_number nil="nil"></po_number>
 			<net_terms type="integer">0</net_terms>
+			<terms_and_conditions>Net 30.</terms_and_conditions>
+			<customer_notes>Thanks for your business.</customer_notes>
+			<vat_reverse_charge_notes>Reverse charge applies.</vat_reverse_charge_notes>
 			<subscription_add_ons type="array">
 			</subscription_add_ons>
 			<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
@@ -506,6 +526,9 @@ func TestGetSubscriptions(t *testing.T) {
 		TaxRegion:              "CA",
 		TaxRate:                0.0875,
 		NetTerms:               NewInt(0),
+		TermsAndConditions:     "Net 30.",
+		CustomerNotes:          "Thanks for your business.",
+		VATReverseChargeNotes:  "Reverse charge applies.",
 	}
 
 	if !reflect.DeepEqual(expected, subscription) {
@@ -513,6 +536,47 @@ func TestGetSubscriptions(t *testing.T) {
 	}
 }
 
+// TestGetSubscriptionAddOns ensures the add ons of a subscription are
+// decoded from the subscription_add_ons element Recurly returns.
+func TestGetSubscriptionAddOns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>active</state>
+			<subscription_add_ons type="array">
+				<subscription_add_on>
+					<add_on_code>ipaddresses</add_on_code>
+					<unit_amount_in_cents type="integer">200</unit_amount_in_cents>
+					<quantity type="integer">2</quantity>
+				</subscription_add_on>
+				<subscription_add_on>
+					<add_on_code>support</add_on_code>
+					<unit_amount_in_cents type="integer">500</unit_amount_in_cents>
+					<quantity type="integer">1</quantity>
+				</subscription_add_on>
+			</subscription_add_ons>
+		</subscription>`)
+	})
+
+	_, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("TestGetSubscriptionAddOns Error: Error occurred making API call. Err: %s", err)
+	}
+
+	expected := []SubscriptionAddOn{
+		SubscriptionAddOn{XMLName: xml.Name{Local: "subscription_add_on"}, Code: "ipaddresses", UnitAmountInCents: 200, Quantity: 2},
+		SubscriptionAddOn{XMLName: xml.Name{Local: "subscription_add_on"}, Code: "support", UnitAmountInCents: 500, Quantity: 1},
+	}
+	if !reflect.DeepEqual(expected, subscription.SubscriptionAddOns) {
+		t.Errorf("TestGetSubscriptionAddOns Error: expected add ons to equal %#v, given %#v", expected, subscription.SubscriptionAddOns)
+	}
+}
+
 func TestCreateSubscription(t *testing.T) {
 	setup()
 	defer teardown()
@@ -770,3 +834,172 @@ func TestPostponeSubscription(t *testing.T) {
 		t.Fatal("TestPostponeSubscription Error: Expected postpone subscription change to return OK")
 	}
 }
+
+func TestGetPausedSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+			  <plan_code>gold</plan_code>
+			  <name>Gold plan</name>
+			</plan>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>paused</state>
+			<paused_at type="datetime">2011-06-27T07:00:00Z</paused_at>
+			<resume_at type="datetime">2011-08-27T07:00:00Z</resume_at>
+			<remaining_pause_cycles type="integer">2</remaining_pause_cycles>
+			<pending_subscription type="subscription">
+				<plan href="https://your-subdomain.recurly.com/v2/plans/platinum">
+					<plan_code>platinum</plan_code>
+					<name>Platinum plan</name>
+				</plan>
+				<unit_amount_in_cents type="integer">2000</unit_amount_in_cents>
+				<quantity type="integer">3</quantity>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_code>ipaddresses</add_on_code>
+						<unit_amount_in_cents type="integer">200</unit_amount_in_cents>
+						<quantity type="integer">2</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+			</pending_subscription>
+			<a name="resume" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/resume" method="put"/>
+		</subscription>`)
+	})
+
+	_, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("TestGetPausedSubscription Error: Error occurred making API call. Err: %s", err)
+	}
+
+	pausedAt, _ := time.Parse(datetimeFormat, "2011-06-27T07:00:00Z")
+	resumeAt, _ := time.Parse(datetimeFormat, "2011-08-27T07:00:00Z")
+	if subscription.State != SubscriptionStatePaused || !subscription.PausedAt.Equal(pausedAt) || !subscription.ResumeAt.Equal(resumeAt) || subscription.RemainingPauseCycles != 2 {
+		t.Errorf("TestGetPausedSubscription Error: Expected subscription paused for 2 cycles, given %#v", subscription)
+	}
+
+	expected := &PendingSubscription{
+		XMLName:           xml.Name{Local: "pending_subscription"},
+		Plan:              nestedPlan{Code: "platinum", Name: "Platinum plan"},
+		UnitAmountInCents: 2000,
+		Quantity:          3,
+		SubscriptionAddOns: []SubscriptionAddOn{
+			{
+				XMLName:           xml.Name{Local: "subscription_add_on"},
+				Code:              "ipaddresses",
+				UnitAmountInCents: 200,
+				Quantity:          2,
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, subscription.PendingSubscription) {
+		t.Errorf("TestGetPausedSubscription Error: expected pending subscription to equal %#v, given %#v", expected, subscription.PendingSubscription)
+	}
+}
+
+func TestPauseSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var given string
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/pause", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestPauseSubscription Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		given = string(b)
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	r, _, err := client.Subscriptions.Pause("44f83d7cba354d5b84812419f923ea96", 2)
+	if err != nil {
+		t.Errorf("TestPauseSubscription Error: Error occurred making API call. Err: %s", err)
+	} else if r.IsError() {
+		t.Fatal("TestPauseSubscription Error: Expected pause subscription to return OK")
+	}
+
+	expected := "<subscription><remaining_pause_cycles>2</remaining_pause_cycles></subscription>"
+	if given != expected {
+		t.Errorf("TestPauseSubscription Error: Expected body %s, given %s", expected, given)
+	}
+
+	r, _, err = client.Subscriptions.RemovePause("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Errorf("TestPauseSubscription Error: Error occurred making API call. Err: %s", err)
+	} else if r.IsError() {
+		t.Fatal("TestPauseSubscription Error: Expected remove pause to return OK")
+	}
+
+	expected = "<subscription><remaining_pause_cycles>0</remaining_pause_cycles></subscription>"
+	if given != expected {
+		t.Errorf("TestPauseSubscription Error: Expected body %s, given %s", expected, given)
+	}
+}
+
+func TestPauseSubscriptionInvalidCycles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/pause", func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("TestPauseSubscriptionInvalidCycles Error: Expected no request to be made")
+	})
+
+	for _, cycles := range []int{0, -1} {
+		r, _, err := client.Subscriptions.Pause("44f83d7cba354d5b84812419f923ea96", cycles)
+		if r != nil {
+			t.Errorf("TestPauseSubscriptionInvalidCycles Error (%d): Expected nil response, given %+v", cycles, r)
+		}
+
+		ve, ok := err.(*ValidationError)
+		if !ok || len(ve.Errors) != 1 || ve.Errors[0].Field != "subscription.remaining_pause_cycles" {
+			t.Errorf("TestPauseSubscriptionInvalidCycles Error (%d): Expected pause cycles validation error, given %v", cycles, err)
+		}
+	}
+}
+
+func TestResumeSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/resume", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestResumeSubscription Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>active</state></subscription>`)
+	})
+
+	r, subscription, err := client.Subscriptions.Resume("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Errorf("TestResumeSubscription Error: Error occurred making API call. Err: %s", err)
+	} else if r.IsError() {
+		t.Fatal("TestResumeSubscription Error: Expected resume subscription to return OK")
+	} else if subscription.State != SubscriptionStateActive {
+		t.Errorf("TestResumeSubscription Error: Expected active subscription, given %s", subscription.State)
+	}
+}
+
+func TestCancelPendingSubscriptionChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/pending", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestCancelPendingSubscriptionChange Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+		rw.WriteHeader(204)
+	})
+
+	r, err := client.Subscriptions.CancelPendingChange("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Errorf("TestCancelPendingSubscriptionChange Error: Error occurred making API call. Err: %s", err)
+	} else if r.StatusCode != 204 {
+		t.Errorf("TestCancelPendingSubscriptionChange Error: Expected status code 204, given %d", r.StatusCode)
+	}
+}
@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net"
@@ -9,7 +10,25 @@ import (
 type (
 	// TransactionsService handles communication with the transactions related methods
 	// of the recurly API.
-	TransactionsService struct {
+	TransactionsService interface {
+		List(params Params) (*Response, []Transaction, error)
+		ListContext(ctx context.Context, params Params) (*Response, []Transaction, error)
+		ListPager(params Params) *Pager[Transaction]
+		ListAccount(accountCode string, params Params) (*Response, []Transaction, error)
+		ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Transaction, error)
+		ListAccountPager(accountCode string, params Params) *Pager[Transaction]
+		Get(uuid string) (*Response, Transaction, error)
+		GetContext(ctx context.Context, uuid string) (*Response, Transaction, error)
+		Create(nt NewTransaction) (*Response, Transaction, error)
+		CreateContext(ctx context.Context, nt NewTransaction) (*Response, Transaction, error)
+		Refund(uuid string, amountInCents int) (*Response, Transaction, error)
+		RefundContext(ctx context.Context, uuid string, amountInCents int) (*Response, Transaction, error)
+		Void(uuid string) (*Response, Transaction, error)
+		VoidContext(ctx context.Context, uuid string) (*Response, Transaction, error)
+	}
+
+	// transactionsImpl implements TransactionsService.
+	transactionsImpl struct {
 		client *Client
 	}
 
@@ -61,6 +80,11 @@ type (
 		Refundable    NullBool `xml:"refundable,omitempty"`
 		IPAddress     net.IP   `xml:"ip_address,omitempty"`
 		Account       Account  `xml:"account"`
+
+		// Set either ShippingAddressID or ShippingAddress to ship the
+		// purchase. See NewSubscription.
+		ShippingAddressID int              `xml:"shipping_address_id,omitempty"`
+		ShippingAddress   *ShippingAddress `xml:"shipping_address,omitempty"`
 	}
 
 	transactionResult struct {
@@ -119,10 +143,36 @@ const (
 	TransactionStatusVoid = "void"
 )
 
+// Validate checks the transaction for mistakes Recurly would reject, such
+// as a missing amount, currency or account code, or an unknown currency or
+// country. It returns a *ValidationError listing every problem found, or
+// nil. See Client.ValidateRequests.
+func (t NewTransaction) Validate() error {
+	var v validation
+	if t.AmountInCents <= 0 {
+		v.add("transaction.amount_in_cents", "greater_than", "must be greater than 0")
+	}
+	v.nonNegative("transaction.tax_in_cents", t.TaxInCents)
+	v.required("transaction.currency", t.Currency != "")
+	v.currency("transaction.currency", t.Currency)
+	v.account("transaction.account", t.Account)
+	if t.ShippingAddress != nil {
+		v.country("transaction.shipping_address.country", t.ShippingAddress.Country)
+	}
+
+	return v.err()
+}
+
 // List returns a list of transactions
 // https://dev.recurly.com/docs/list-transactions
-func (service TransactionsService) List(params Params) (*Response, []Transaction, error) {
-	req, err := service.client.newRequest("GET", "transactions", params, nil)
+func (service transactionsImpl) List(params Params) (*Response, []Transaction, error) {
+	return service.ListContext(context.Background(), params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service transactionsImpl) ListContext(ctx context.Context, params Params) (*Response, []Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.List", "transactions")
+	req, err := service.client.newRequest(ctx, "GET", "transactions", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -136,11 +186,24 @@ func (service TransactionsService) List(params Params) (*Response, []Transaction
 	return res, p.Transactions, err
 }
 
+// ListPager returns a Pager that walks every page of transactions.
+func (service transactionsImpl) ListPager(params Params) *Pager[Transaction] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Transaction, error) {
+		return service.ListContext(ctx, params)
+	})
+}
+
 // ListAccount returns a list of transactions for an account
 // https://dev.recurly.com/docs/list-accounts-transactions
-func (service TransactionsService) ListAccount(accountCode string, params Params) (*Response, []Transaction, error) {
+func (service transactionsImpl) ListAccount(accountCode string, params Params) (*Response, []Transaction, error) {
+	return service.ListAccountContext(context.Background(), accountCode, params)
+}
+
+// ListAccountContext is the same as ListAccount, but uses ctx for the request.
+func (service transactionsImpl) ListAccountContext(ctx context.Context, accountCode string, params Params) (*Response, []Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.ListAccount", "accounts/{account_code}/transactions")
 	action := fmt.Sprintf("accounts/%s/transactions", accountCode)
-	req, err := service.client.newRequest("GET", action, params, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -154,14 +217,27 @@ func (service TransactionsService) ListAccount(accountCode string, params Params
 	return res, p.Transactions, err
 }
 
+// ListAccountPager returns a Pager that walks every page of transactions for an account.
+func (service transactionsImpl) ListAccountPager(accountCode string, params Params) *Pager[Transaction] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Transaction, error) {
+		return service.ListAccountContext(ctx, accountCode, params)
+	})
+}
+
 // Get returns account and billing information at the time the transaction was
 // submitted. It may not reflect the latest account information. A
 // transaction_error section may be included if the transaction failed.
 // Please see transaction error codes for more details.
 // https://dev.recurly.com/docs/lookup-transaction
-func (service TransactionsService) Get(uuid string) (*Response, Transaction, error) {
+func (service transactionsImpl) Get(uuid string) (*Response, Transaction, error) {
+	return service.GetContext(context.Background(), uuid)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service transactionsImpl) GetContext(ctx context.Context, uuid string) (*Response, Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.Get", "transactions/{uuid}")
 	action := fmt.Sprintf("transactions/%s", uuid)
-	req, err := service.client.newRequest("GET", action, nil, nil)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
 	if err != nil {
 		return nil, Transaction{}, err
 	}
@@ -178,8 +254,69 @@ func (service TransactionsService) Get(uuid string) (*Response, Transaction, err
 // attributes must be supplied. When charging an existing account only the
 // account_code must be supplied.
 // https://dev.recurly.com/docs/create-transaction
-func (service TransactionsService) Create(nt NewTransaction) (*Response, Transaction, error) {
-	req, err := service.client.newRequest("POST", "transactions", nil, nt)
+func (service transactionsImpl) Create(nt NewTransaction) (*Response, Transaction, error) {
+	return service.CreateContext(context.Background(), nt)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service transactionsImpl) CreateContext(ctx context.Context, nt NewTransaction) (*Response, Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.Create", "transactions")
+	req, err := service.client.newRequest(ctx, "POST", "transactions", nil, nt)
+	if err != nil {
+		return nil, Transaction{}, err
+	}
+
+	var dest Transaction
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Refund refunds amountInCents of a successful transaction. An amount less
+// than 1 returns a *ValidationError without making a request; use Void to
+// void or refund a transaction in full. The returned transaction is the
+// refund. If the gateway fails to process the refund, the error is available
+// on the Response's TransactionError.
+// https://dev.recurly.com/docs/refund-transaction
+func (service transactionsImpl) Refund(uuid string, amountInCents int) (*Response, Transaction, error) {
+	return service.RefundContext(context.Background(), uuid, amountInCents)
+}
+
+// RefundContext is the same as Refund, but uses ctx for the request.
+func (service transactionsImpl) RefundContext(ctx context.Context, uuid string, amountInCents int) (*Response, Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.Refund", "transactions/{uuid}")
+
+	// Without an amount Recurly voids or refunds the whole transaction,
+	// which is Void's job.
+	if amountInCents < 1 {
+		var v validation
+		v.add("transaction.amount_in_cents", "greater_than", "must be greater than 0")
+		return nil, Transaction{}, v.err()
+	}
+
+	return service.delete(ctx, uuid, Params{"amount_in_cents": amountInCents})
+}
+
+// Void voids a transaction that has not yet settled, and refunds the full
+// remaining amount of one that has. Check Voidable to tell which will happen.
+// The returned transaction is the voided transaction or the refund. If the
+// gateway fails to process the void, the error is available on the
+// Response's TransactionError.
+// https://dev.recurly.com/docs/refund-transaction
+func (service transactionsImpl) Void(uuid string) (*Response, Transaction, error) {
+	return service.VoidContext(context.Background(), uuid)
+}
+
+// VoidContext is the same as Void, but uses ctx for the request.
+func (service transactionsImpl) VoidContext(ctx context.Context, uuid string) (*Response, Transaction, error) {
+	ctx = withOperation(ctx, "Transactions.Void", "transactions/{uuid}")
+	return service.delete(ctx, uuid, nil)
+}
+
+// delete sends the request shared by refunds and voids.
+func (service transactionsImpl) delete(ctx context.Context, uuid string, params Params) (*Response, Transaction, error) {
+	action := fmt.Sprintf("transactions/%s", uuid)
+	req, err := service.client.newRequest(ctx, "DELETE", action, params, nil)
 	if err != nil {
 		return nil, Transaction{}, err
 	}
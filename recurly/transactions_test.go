@@ -18,6 +18,8 @@ import (
 func TestTransactionsEncoding(t *testing.T) {
 	suite := []map[string]interface{}{
 		map[string]interface{}{"struct": Transaction{}, "xml": "<transaction><amount_in_cents>0</amount_in_cents><currency></currency><details><account></account></details></transaction>"},
+		map[string]interface{}{"struct": NewTransaction{AmountInCents: 100, Currency: "USD", Account: Account{Code: "1"}, ShippingAddressID: 5}, "xml": "<transaction><amount_in_cents>100</amount_in_cents><currency>USD</currency><account><account_code>1</account_code></account><shipping_address_id>5</shipping_address_id></transaction>"},
+		map[string]interface{}{"struct": NewTransaction{AmountInCents: 100, Currency: "USD", Account: Account{Code: "1"}, ShippingAddress: &ShippingAddress{Nickname: "Work", Zip: "94105"}}, "xml": "<transaction><amount_in_cents>100</amount_in_cents><currency>USD</currency><account><account_code>1</account_code></account><shipping_address><nickname>Work</nickname><zip>94105</zip></shipping_address></transaction>"},
 	}
 
 	for _, s := range suite {
@@ -172,8 +174,8 @@ func TestTransactionsList(t *testing.T) {
 					CardType:  "Visa",
 					Year:      2017,
 					Month:     11,
-					FirstSix:  411111,
-					LastFour:  1111,
+					FirstSix:  "411111",
+					LastFour:  "1111",
 				},
 			},
 		}
@@ -323,8 +325,8 @@ func TestTransactionsListAccount(t *testing.T) {
 					CardType:  "Visa",
 					Year:      2017,
 					Month:     11,
-					FirstSix:  411111,
-					LastFour:  1111,
+					FirstSix:  "411111",
+					LastFour:  "1111",
 				},
 			},
 		}
@@ -463,8 +465,8 @@ func TestGetTransaction(t *testing.T) {
 				CardType:  "Visa",
 				Year:      2017,
 				Month:     11,
-				FirstSix:  411111,
-				LastFour:  1111,
+				FirstSix:  "411111",
+				LastFour:  "1111",
 			},
 		},
 	}
@@ -595,7 +597,7 @@ func TestNewTransactionFraudCard(t *testing.T) {
 			BillingInfo: &Billing{
 				FirstName: "Verena",
 				LastName:  "Example",
-				Number:    4000000000000085,
+				Number:    "4000000000000085",
 				Month:     10,
 				Year:      2020,
 			},
@@ -622,6 +624,203 @@ func TestNewTransactionFraudCard(t *testing.T) {
 	}
 }
 
+func TestRefundTransactionPartial(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestRefundTransactionPartial Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+
+		if given := r.URL.Query().Get("amount_in_cents"); given != "250" {
+			t.Errorf("TestRefundTransactionPartial Error: Expected amount of 250, given %s", given)
+		}
+
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction type="credit_card">
+				<uuid>2e40e4da6f4fbb1d2df1f14ba6a9d6b1</uuid>
+				<action>refund</action>
+				<amount_in_cents type="integer">250</amount_in_cents>
+				<currency>USD</currency>
+				<status>success</status>
+			</transaction>`)
+	})
+
+	_, txn, err := client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", 250)
+	if err != nil {
+		t.Errorf("TestRefundTransactionPartial Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if txn.Action != "refund" || txn.AmountInCents != 250 {
+		t.Errorf("TestRefundTransactionPartial Error: Expected refund transaction for 250 cents, given %#v", txn)
+	}
+}
+
+func TestRefundTransactionInvalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("TestRefundTransactionInvalid Error: Expected no request for an amount less than 1")
+	})
+
+	for _, amount := range []int{0, -250} {
+		r, _, err := client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", amount)
+		if r != nil {
+			t.Errorf("TestRefundTransactionInvalid Error: Expected nil response for %d, given %+v", amount, r)
+		}
+
+		ve, ok := err.(*ValidationError)
+		if !ok || len(ve.Errors) != 1 || ve.Errors[0].Field != "transaction.amount_in_cents" {
+			t.Errorf("TestRefundTransactionInvalid Error: Expected amount validation error for %d, given %v", amount, err)
+		}
+	}
+}
+
+func TestRefundTransactionNotRefundable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+				<error field="transaction.base" symbol="not_refundable">This transaction is not refundable.</error>
+			</errors>`)
+	})
+
+	client.TypedErrors = true
+
+	r, _, err := client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", 250)
+	if r.IsOK() {
+		t.Fatal("TestRefundTransactionNotRefundable Error: Expected refunding transaction to return error")
+	}
+
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("TestRefundTransactionNotRefundable Error: Expected *ValidationError, given %#v", err)
+	}
+
+	if len(ve.Errors) != 1 || ve.Errors[0].Symbol != "not_refundable" || ve.Errors[0].Field != "transaction.base" {
+		t.Errorf("TestRefundTransactionNotRefundable Error: Expected not_refundable error, given %#v", ve.Errors)
+	}
+}
+
+func TestRefundTransactionDeclined(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(422)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+				<transaction_error>
+					<error_code>declined</error_code>
+					<error_category>hard</error_category>
+					<merchant_message>The refund was declined by the payment gateway.</merchant_message>
+					<customer_message>The refund could not be processed.</customer_message>
+				</transaction_error>
+				<error field="transaction.base" symbol="declined">The refund could not be processed.</error>
+			</errors>`)
+	})
+
+	client.TypedErrors = true
+
+	r, _, err := client.Transactions.Refund("a13acd8fe4294916b79aec87b7ea441f", 250)
+	if r.TransactionError.ErrorCode != "declined" {
+		t.Errorf("TestRefundTransactionDeclined Error: Expected declined transaction error, given %+v", r.TransactionError)
+	}
+
+	te, ok := err.(*TransactionFailedError)
+	if !ok {
+		t.Fatalf("TestRefundTransactionDeclined Error: Expected *TransactionFailedError, given %#v", err)
+	}
+
+	if !te.IsHardDecline() {
+		t.Errorf("TestRefundTransactionDeclined Error: Expected hard decline, given %+v", te.TransactionError)
+	}
+}
+
+func TestVoidTransaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestVoidTransaction Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+
+		if r.URL.RawQuery != "" {
+			t.Errorf("TestVoidTransaction Error: Expected no query string, given %s", r.URL.RawQuery)
+		}
+
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction type="credit_card">
+				<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+				<action>purchase</action>
+				<amount_in_cents type="integer">1000</amount_in_cents>
+				<currency>USD</currency>
+				<status>void</status>
+				<voidable type="boolean">false</voidable>
+				<refundable type="boolean">false</refundable>
+			</transaction>`)
+	})
+
+	_, txn, err := client.Transactions.Void("a13acd8fe4294916b79aec87b7ea441f")
+	if err != nil {
+		t.Errorf("TestVoidTransaction Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if txn.Status != TransactionStatusVoid || txn.Voidable.Bool || !txn.Voidable.Valid {
+		t.Errorf("TestVoidTransaction Error: Expected voided transaction, given %#v", txn)
+	}
+}
+
+// TestVoidTransactionSettled ensures Recurly's refund of a settled
+// transaction is returned from Void.
+func TestVoidTransactionSettled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestVoidTransactionSettled Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+
+		if given := r.URL.Query().Get("amount_in_cents"); given != "" {
+			t.Errorf("TestVoidTransactionSettled Error: Expected no amount for a full refund, given %s", given)
+		}
+
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction href="https://your-subdomain.recurly.com/v2/transactions/2e40e4da6f4fbb1d2df1f14ba6a9d6b1" type="credit_card">
+				<uuid>2e40e4da6f4fbb1d2df1f14ba6a9d6b1</uuid>
+				<action>refund</action>
+				<amount_in_cents type="integer">1000</amount_in_cents>
+				<currency>USD</currency>
+				<status>success</status>
+				<voidable type="boolean">true</voidable>
+				<refundable type="boolean">false</refundable>
+			</transaction>`)
+	})
+
+	r, txn, err := client.Transactions.Void("a13acd8fe4294916b79aec87b7ea441f")
+	if err != nil {
+		t.Errorf("TestVoidTransactionSettled Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestVoidTransactionSettled Error: Expected voiding settled transaction to return OK")
+	}
+
+	if txn.UUID != "2e40e4da6f4fbb1d2df1f14ba6a9d6b1" || txn.Action != "refund" || txn.AmountInCents != 1000 || txn.Status != TransactionStatusSuccess {
+		t.Errorf("TestVoidTransactionSettled Error: Expected refund transaction for 1000 cents, given %#v", txn)
+	}
+}
+
 func TestCVVIsFunctions(t *testing.T) {
 	c := CVVResult{transactionResult{Code: "M"}}
 	if !c.IsMatch() {
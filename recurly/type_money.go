@@ -0,0 +1,366 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// Money is an amount in the minor unit of a currency, such as cents for
+	// USD or whole yen for JPY. Arithmetic on Money in different currencies
+	// returns a *CurrencyMismatchError instead of mixing them.
+	Money struct {
+		Amount   int
+		Currency string
+	}
+
+	// CurrencyMismatchError is returned when combining Money in different
+	// currencies.
+	CurrencyMismatchError struct {
+		A string
+		B string
+	}
+
+	// moneyLocale holds the conventions for formatting money in a locale.
+	moneyLocale struct {
+		decimal     string
+		group       string
+		symbolAfter bool
+		space       bool
+	}
+)
+
+// ErrMoneyOverflow is returned when Money arithmetic overflows an int.
+var ErrMoneyOverflow = errors.New("recurly: money amount overflows int")
+
+// moneyLocales holds the formatting conventions by language, with regional
+// overrides keyed by the full tag.
+var moneyLocales = map[string]moneyLocale{
+	"en":    {decimal: ".", group: ","},
+	"ja":    {decimal: ".", group: ","},
+	"de":    {decimal: ",", group: ".", symbolAfter: true, space: true},
+	"es":    {decimal: ",", group: ".", symbolAfter: true, space: true},
+	"it":    {decimal: ",", group: ".", symbolAfter: true, space: true},
+	"fr":    {decimal: ",", group: " ", symbolAfter: true, space: true},
+	"sv":    {decimal: ",", group: " ", symbolAfter: true, space: true},
+	"nl":    {decimal: ",", group: ".", space: true},
+	"pt":    {decimal: ",", group: ".", space: true},
+	"de-ch": {decimal: ".", group: "'", space: true},
+}
+
+// currencySymbols holds the symbols for common currencies. Other currencies
+// are formatted with their code.
+var currencySymbols = map[string]string{
+	"AUD": "A$",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"CNY": "CN¥",
+	"EUR": "€",
+	"GBP": "£",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"MXN": "MX$",
+	"NZD": "NZ$",
+	"USD": "$",
+}
+
+// NewMoney returns Money for an amount in the currency's minor unit.
+func NewMoney(amount int, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("recurly: currency mismatch: %s and %s", e.A, e.B)
+}
+
+// check returns an error if m and o are in different currencies.
+func (m Money) check(o Money) error {
+	if !strings.EqualFold(m.Currency, o.Currency) {
+		return &CurrencyMismatchError{A: m.Currency, B: o.Currency}
+	}
+	return nil
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+
+	n := m.Amount + o.Amount
+	if (n > m.Amount) != (o.Amount > 0) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: n, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+
+	n := m.Amount - o.Amount
+	if (n < m.Amount) != (o.Amount > 0) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: n, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by n, such as a unit amount times a quantity.
+func (m Money) Mul(n int) (Money, error) {
+	v := m.Amount * n
+	if n != 0 && v/n != m.Amount {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	// The most negative int times -1 overflows back to itself, which the
+	// division above can't detect.
+	if n == -1 && v == m.Amount && v != 0 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: v, Currency: m.Currency}, nil
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// IsZero returns true if the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative returns true if the amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Cmp compares m and o, returning -1 if m is less than o, 0 if they are
+// equal, and 1 if m is greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.check(o); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Allocate splits m into parts proportional to ratios without losing any
+// minor units. Units left over after dividing are given one at a time to
+// the parts with the largest remainders, earliest first on ties, so the
+// parts always add up to m.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	var total int
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("recurly: allocation ratios must not be negative")
+		}
+		total += r
+	}
+	if total <= 0 {
+		return nil, errors.New("recurly: allocation ratios must add up to more than zero")
+	}
+
+	amount := m.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	parts := make([]Money, len(ratios))
+	remainders := make([]int, len(ratios))
+	left := amount
+	for i, r := range ratios {
+		v := amount * r
+		if r != 0 && v/r != amount {
+			return nil, ErrMoneyOverflow
+		}
+		parts[i] = Money{Amount: v / total, Currency: m.Currency}
+		remainders[i] = v % total
+		left -= parts[i].Amount
+	}
+
+	for ; left > 0; left-- {
+		max := 0
+		for i := range remainders {
+			if remainders[i] > remainders[max] {
+				max = i
+			}
+		}
+		parts[max].Amount++
+		remainders[max] = -1
+	}
+
+	if m.Amount < 0 {
+		for i := range parts {
+			parts[i].Amount = -parts[i].Amount
+		}
+	}
+	return parts, nil
+}
+
+// Split splits m into n parts that differ by at most one minor unit.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, errors.New("recurly: money must be split into at least one part")
+	}
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// String returns the amount in the currency's major unit followed by the
+// currency code, e.g. "10.50 USD".
+func (m Money) String() string {
+	return FormatAmount(m.Amount, m.Currency) + " " + m.Currency
+}
+
+// Format formats m for a locale given as a language tag such as "en-US" or
+// "de-DE", using the locale's decimal and grouping separators and symbol
+// placement. Unknown locales are formatted like "en".
+func (m Money) Format(locale string) string {
+	tag := strings.ToLower(strings.Replace(locale, "_", "-", -1))
+	l, ok := moneyLocales[tag]
+	if !ok {
+		l, ok = moneyLocales[strings.SplitN(tag, "-", 2)[0]]
+	}
+	if !ok {
+		l = moneyLocales["en"]
+	}
+
+	amount := m.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	parts := strings.SplitN(FormatAmount(amount, m.Currency), ".", 2)
+	var b strings.Builder
+	for i, c := range parts[0] {
+		if i > 0 && (len(parts[0])-i)%3 == 0 {
+			b.WriteString(l.group)
+		}
+		b.WriteRune(c)
+	}
+	if len(parts) == 2 {
+		b.WriteString(l.decimal)
+		b.WriteString(parts[1])
+	}
+
+	symbol, ok := currencySymbols[strings.ToUpper(m.Currency)]
+	if !ok {
+		symbol = strings.ToUpper(m.Currency)
+	}
+
+	var s string
+	switch {
+	case l.symbolAfter:
+		s = b.String() + " " + symbol
+	case l.space || unicode.IsLetter(lastRune(symbol)):
+		// Symbols ending in a letter, like CHF, are always set apart from
+		// the number.
+		s = symbol + " " + b.String()
+	default:
+		s = symbol + b.String()
+	}
+	if m.Amount < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// lastRune returns the last rune of s, or utf8.RuneError if s is empty.
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// Money returns the amount for the currency as Money, and whether it was set.
+func (u UnitAmount) Money(currency string) (Money, bool) {
+	v, ok := u.Get(currency)
+	return NewMoney(v, currency), ok
+}
+
+// Cost returns what it cost to acquire the account.
+func (a AccountAcquisition) Cost() Money {
+	return NewMoney(a.CostInCents, a.Currency)
+}
+
+// UnitAmount returns the unit amount of the adjustment.
+func (a Adjustment) UnitAmount() Money {
+	return NewMoney(a.UnitAmountInCents, a.Currency)
+}
+
+// Discount returns the discount applied to the adjustment.
+func (a Adjustment) Discount() Money {
+	return NewMoney(a.DiscountInCents, a.Currency)
+}
+
+// Tax returns the tax on the adjustment.
+func (a Adjustment) Tax() Money {
+	return NewMoney(a.TaxInCents, a.Currency)
+}
+
+// Total returns the total of the adjustment.
+func (a Adjustment) Total() Money {
+	return NewMoney(a.TotalInCents, a.Currency)
+}
+
+// Subtotal returns the subtotal of the invoice, before tax.
+func (i Invoice) Subtotal() Money {
+	return NewMoney(i.SubtotalInCents, i.Currency)
+}
+
+// Tax returns the tax on the invoice.
+func (i Invoice) Tax() Money {
+	return NewMoney(i.TaxInCents, i.Currency)
+}
+
+// Total returns the total of the invoice.
+func (i Invoice) Total() Money {
+	return NewMoney(i.TotalInCents, i.Currency)
+}
+
+// Amount returns the amount of the transaction.
+func (t Transaction) Amount() Money {
+	return NewMoney(t.AmountInCents, t.Currency)
+}
+
+// Tax returns the tax included in the transaction.
+func (t Transaction) Tax() Money {
+	return NewMoney(t.TaxInCents, t.Currency)
+}
+
+// UnitAmount returns the unit amount of the subscription.
+func (s Subscription) UnitAmount() Money {
+	return NewMoney(s.UnitAmountInCents, s.Currency)
+}
+
+// Tax returns the tax on the subscription.
+func (s Subscription) Tax() Money {
+	return NewMoney(s.TaxInCents, s.Currency)
+}
+
+// TotalDiscounted returns the total discounted by the redemption.
+func (r Redemption) TotalDiscounted() Money {
+	return NewMoney(r.TotalDiscountedInCents, r.Currency)
+}
+
+// Discount returns the coupon's fixed discount in the currency. Coupons
+// hold a single fixed amount, so the currency must be given.
+func (c Coupon) Discount(currency string) Money {
+	return NewMoney(c.DiscountInCents, currency)
+}
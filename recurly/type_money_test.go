@@ -0,0 +1,146 @@
+package recurly
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := NewMoney(1050, "usd")
+	b := NewMoney(250, "USD")
+
+	if sum, err := a.Add(b); err != nil || sum != NewMoney(1300, "USD") {
+		t.Errorf("TestMoneyArithmetic Error: Expected 13.00 USD, given %s (%v)", sum, err)
+	}
+
+	if diff, err := b.Sub(a); err != nil || diff != NewMoney(-800, "USD") || !diff.IsNegative() {
+		t.Errorf("TestMoneyArithmetic Error: Expected -8.00 USD, given %s (%v)", diff, err)
+	}
+
+	if product, err := b.Mul(3); err != nil || product != NewMoney(750, "USD") {
+		t.Errorf("TestMoneyArithmetic Error: Expected 7.50 USD, given %s (%v)", product, err)
+	}
+
+	if c, err := a.Cmp(b); err != nil || c != 1 {
+		t.Errorf("TestMoneyArithmetic Error: Expected 1, given %d (%v)", c, err)
+	}
+
+	if a.Neg() != NewMoney(-1050, "USD") || !NewMoney(0, "EUR").IsZero() {
+		t.Error("TestMoneyArithmetic Error: Expected Neg and IsZero to work")
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd := NewMoney(1000, "USD")
+	eur := NewMoney(1000, "EUR")
+
+	_, err := usd.Add(eur)
+	if e, ok := err.(*CurrencyMismatchError); !ok || e.A != "USD" || e.B != "EUR" {
+		t.Errorf("TestMoneyCurrencyMismatch Error: Expected *CurrencyMismatchError, given %#v", err)
+	}
+
+	if _, err := usd.Sub(eur); err == nil {
+		t.Error("TestMoneyCurrencyMismatch Error: Expected error subtracting EUR from USD")
+	}
+
+	if _, err := usd.Cmp(eur); err == nil {
+		t.Error("TestMoneyCurrencyMismatch Error: Expected error comparing USD and EUR")
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	suite := []func() (Money, error){
+		func() (Money, error) { return NewMoney(math.MaxInt, "USD").Add(NewMoney(1, "USD")) },
+		func() (Money, error) { return NewMoney(math.MinInt, "USD").Sub(NewMoney(1, "USD")) },
+		func() (Money, error) { return NewMoney(math.MaxInt/2+1, "USD").Mul(2) },
+		func() (Money, error) { return NewMoney(math.MinInt, "USD").Mul(-1) },
+	}
+
+	for i, f := range suite {
+		if _, err := f(); err != ErrMoneyOverflow {
+			t.Errorf("TestMoneyOverflow Error (%d): Expected ErrMoneyOverflow, given %v", i, err)
+		}
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"money": NewMoney(100, "USD"), "ratios": []int{1, 1, 1}, "expected": []int{34, 33, 33}},
+		map[string]interface{}{"money": NewMoney(5, "USD"), "ratios": []int{3, 7}, "expected": []int{2, 3}},
+		map[string]interface{}{"money": NewMoney(-100, "USD"), "ratios": []int{1, 1, 1}, "expected": []int{-34, -33, -33}},
+		map[string]interface{}{"money": NewMoney(1000, "JPY"), "ratios": []int{1, 0, 2}, "expected": []int{333, 0, 667}},
+	}
+
+	for i, s := range suite {
+		parts, err := s["money"].(Money).Allocate(s["ratios"].([]int)...)
+		if err != nil {
+			t.Fatalf("TestMoneyAllocate Error (%d): Error allocating. Err: %s", i, err)
+		}
+
+		var given []int
+		for _, p := range parts {
+			given = append(given, p.Amount)
+		}
+
+		if !reflect.DeepEqual(given, s["expected"]) {
+			t.Errorf("TestMoneyAllocate Error (%d): Expected %v, given %v", i, s["expected"], given)
+		}
+	}
+
+	if _, err := NewMoney(100, "USD").Allocate(0, 0); err == nil {
+		t.Error("TestMoneyAllocate Error: Expected error allocating by zero ratios")
+	}
+
+	parts, _ := NewMoney(1001, "EUR").Split(4)
+	if !reflect.DeepEqual(parts, []Money{NewMoney(251, "EUR"), NewMoney(250, "EUR"), NewMoney(250, "EUR"), NewMoney(250, "EUR")}) {
+		t.Errorf("TestMoneyAllocate Error: Expected 10.01 EUR split four ways, given %v", parts)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"money": NewMoney(123456, "USD"), "locale": "en-US", "expected": "$1,234.56"},
+		map[string]interface{}{"money": NewMoney(-1050, "USD"), "locale": "en", "expected": "-$10.50"},
+		map[string]interface{}{"money": NewMoney(123456, "EUR"), "locale": "de-DE", "expected": "1.234,56 €"},
+		map[string]interface{}{"money": NewMoney(123456, "EUR"), "locale": "fr_FR", "expected": "1 234,56 €"},
+		map[string]interface{}{"money": NewMoney(123456, "EUR"), "locale": "nl-NL", "expected": "€ 1.234,56"},
+		map[string]interface{}{"money": NewMoney(123456, "CHF"), "locale": "de-CH", "expected": "CHF 1'234.56"},
+		map[string]interface{}{"money": NewMoney(123456, "CHF"), "locale": "en-US", "expected": "CHF 1,234.56"},
+		map[string]interface{}{"money": NewMoney(1234567, "JPY"), "locale": "ja-JP", "expected": "¥1,234,567"},
+		map[string]interface{}{"money": NewMoney(99, "GBP"), "locale": "xx", "expected": "£0.99"},
+	}
+
+	for _, s := range suite {
+		given := s["money"].(Money).Format(s["locale"].(string))
+		if given != s["expected"].(string) {
+			t.Errorf("TestMoneyFormat Error: Expected %s, given %s", s["expected"], given)
+		}
+	}
+
+	if given := NewMoney(1050, "USD").String(); given != "10.50 USD" {
+		t.Errorf("TestMoneyFormat Error: Expected 10.50 USD, given %s", given)
+	}
+}
+
+func TestMoneyHelpers(t *testing.T) {
+	inv := Invoice{SubtotalInCents: 1000, TaxInCents: 80, TotalInCents: 1080, Currency: "USD"}
+	if total, err := inv.Subtotal().Add(inv.Tax()); err != nil || total != inv.Total() {
+		t.Errorf("TestMoneyHelpers Error: Expected subtotal and tax to add up to %s, given %s (%v)", inv.Total(), total, err)
+	}
+
+	txn := Transaction{AmountInCents: 1080, Currency: "EUR"}
+	if _, err := inv.Total().Sub(txn.Amount()); err == nil {
+		t.Error("TestMoneyHelpers Error: Expected error mixing USD invoice and EUR transaction")
+	}
+
+	price := UnitAmount{USD: 1000, Other: map[string]int{"GBP": 800}}
+	if m, ok := price.Money("gbp"); !ok || m != NewMoney(800, "GBP") {
+		t.Errorf("TestMoneyHelpers Error: Expected 8.00 GBP, given %s (%v)", m, ok)
+	}
+
+	a := Adjustment{UnitAmountInCents: 500, Quantity: 2, TotalInCents: 1000, Currency: "USD"}
+	if total, _ := a.UnitAmount().Mul(a.Quantity); total != a.Total() {
+		t.Errorf("TestMoneyHelpers Error: Expected unit amount times quantity to be %s, given %s", a.Total(), total)
+	}
+}
@@ -1,39 +1,178 @@
 package recurly
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 type (
-	// UnitAmount is used in plans where unit amounts are represented in cents
-	// in both EUR and USD.
+	// UnitAmount is used in plans and add ons where unit amounts are
+	// represented in cents, keyed by ISO 4217 currency code. USD and EUR
+	// have their own fields; amounts in every other currency are held in
+	// Other. Use Get and Set to work with any currency. In every currency, a
+	// zero amount is the same as no amount and isn't encoded.
+	//
+	// Amounts in zero-decimal currencies such as JPY are in whole units
+	// rather than cents. See IsZeroDecimal.
 	UnitAmount struct {
-		USD int `xml:"USD,omitempty"`
-		EUR int `xml:"EUR,omitempty"`
-	}
+		USD int
+		EUR int
 
-	uaAlias struct {
-		USD int `xml:"USD,omitempty"`
-		EUR int `xml:"EUR,omitempty"`
+		// Other holds amounts in currencies other than USD and EUR.
+		Other map[string]int
 	}
 )
 
-// UnmarshalXML unmarshals an int properly, as well as marshaling an empty string to nil.
+// zeroDecimal holds the currencies that have no minor unit.
+var zeroDecimal = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true,
+	"JPY": true, "KMF": true, "KRW": true, "PYG": true, "RWF": true,
+	"UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true,
+	"XPF": true,
+}
+
+// IsZeroDecimal returns true if the currency has no minor unit, meaning
+// amounts "in cents" for the currency are in whole units.
+func IsZeroDecimal(currency string) bool {
+	return zeroDecimal[strings.ToUpper(currency)]
+}
+
+// FormatAmount formats an amount in cents as a decimal string in the
+// currency's major unit, e.g. 1050 USD is "10.50" and 500 JPY is "500".
+func FormatAmount(amountInCents int, currency string) string {
+	if IsZeroDecimal(currency) {
+		return strconv.Itoa(amountInCents)
+	}
+
+	sign := ""
+	if amountInCents < 0 {
+		sign = "-"
+		amountInCents = -amountInCents
+	}
+	return sign + strconv.Itoa(amountInCents/100) + "." + strconv.Itoa(amountInCents%100/10) + strconv.Itoa(amountInCents%10)
+}
+
+// Get returns the amount for the currency and whether it was set. Zero
+// amounts are reported as not set.
+func (u UnitAmount) Get(currency string) (int, bool) {
+	var v int
+	switch currency = strings.ToUpper(currency); currency {
+	case "USD":
+		v = u.USD
+	case "EUR":
+		v = u.EUR
+	default:
+		v = u.Other[currency]
+	}
+
+	return v, v != 0
+}
+
+// Set sets the amount for the currency. Setting a zero amount is the same as
+// calling Delete.
+func (u *UnitAmount) Set(currency string, amountInCents int) {
+	switch currency = strings.ToUpper(currency); currency {
+	case "USD":
+		u.USD = amountInCents
+	case "EUR":
+		u.EUR = amountInCents
+	default:
+		if amountInCents == 0 {
+			delete(u.Other, currency)
+			return
+		}
+		if u.Other == nil {
+			u.Other = make(map[string]int)
+		}
+		u.Other[currency] = amountInCents
+	}
+}
+
+// Delete removes the amount for the currency.
+func (u *UnitAmount) Delete(currency string) {
+	switch currency = strings.ToUpper(currency); currency {
+	case "USD":
+		u.USD = 0
+	case "EUR":
+		u.EUR = 0
+	default:
+		delete(u.Other, currency)
+	}
+}
+
+// Currencies returns the codes of the currencies with an amount set, in the
+// order they are encoded: USD and EUR first, then the rest alphabetically.
+func (u UnitAmount) Currencies() []string {
+	var codes []string
+	if u.USD != 0 {
+		codes = append(codes, "USD")
+	}
+	if u.EUR != 0 {
+		codes = append(codes, "EUR")
+	}
+
+	other := make([]string, 0, len(u.Other))
+	for code, v := range u.Other {
+		if v != 0 {
+			other = append(other, code)
+		}
+	}
+	sort.Strings(other)
+
+	return append(codes, other...)
+}
+
+// UnmarshalXML unmarshals every currency element into the unit amount.
+// Empty elements, such as nil values, are skipped, and elements that hold
+// anything other than an integer return an error.
 func (u *UnitAmount) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var v uaAlias
-	err := d.DecodeElement(&v, &start)
-	if err == nil && (v.USD > 0 || v.EUR > 0) {
-		*u = UnitAmount{USD: v.USD, EUR: v.EUR}
+	var v struct {
+		Amounts []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*u = UnitAmount{}
+	for _, a := range v.Amounts {
+		s := strings.TrimSpace(a.Value)
+		if s == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("recurly: invalid %s unit amount %q", a.XMLName.Local, s)
+		}
+		u.Set(a.XMLName.Local, n)
 	}
 
 	return nil
 }
 
-// MarshalXML marshals NullBools to XML. Otherwise nothing is
-// marshaled.
+// MarshalXML marshals each currency as its own element, in the order given
+// by Currencies. If no currencies are set, nothing is marshaled.
 func (u UnitAmount) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if u.USD > 0 || u.EUR > 0 {
-		v := (uaAlias)(u)
-		e.EncodeElement(v, start)
+	codes := u.Currencies()
+	if len(codes) == 0 {
+		return nil
 	}
 
-	return nil
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, code := range codes {
+		v, _ := u.Get(code)
+		if err := e.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: code}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
 }
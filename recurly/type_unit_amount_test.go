@@ -13,6 +13,7 @@ func TestUnitAmount(t *testing.T) {
 	given3 := UnitAmount{EUR: 650}
 	given4 := UnitAmount{}
 	given5 := UnitAmount{USD: 1}
+	given6 := UnitAmount{USD: 800, Other: map[string]int{"JPY": 900, "GBP": 600}}
 
 	type s struct {
 		XMLName xml.Name   `xml:"s"`
@@ -26,6 +27,7 @@ func TestUnitAmount(t *testing.T) {
 		map[string]interface{}{"struct": s{XMLName: xml.Name{Local: "s"}, Name: "Bob", Amount: given3}, "expected": "<s><name>Bob</name><amount><EUR>650</EUR></amount></s>"},
 		map[string]interface{}{"struct": s{XMLName: xml.Name{Local: "s"}, Name: "Bob", Amount: given4}, "expected": "<s><name>Bob</name></s>"},
 		map[string]interface{}{"struct": s{XMLName: xml.Name{Local: "s"}, Name: "Bob", Amount: given5}, "expected": "<s><name>Bob</name><amount><USD>1</USD></amount></s>"},
+		map[string]interface{}{"struct": s{XMLName: xml.Name{Local: "s"}, Name: "Bob", Amount: given6}, "expected": "<s><name>Bob</name><amount><USD>800</USD><GBP>600</GBP><JPY>900</JPY></amount></s>"},
 	}
 
 	for i, test := range suite {
@@ -51,3 +53,92 @@ func TestUnitAmount(t *testing.T) {
 		}
 	}
 }
+
+func TestUnitAmountDecodeSkipsNil(t *testing.T) {
+	var given UnitAmount
+	str := `<unit_amount_in_cents><AUD type="integer">1500</AUD><EUR nil="nil"></EUR><USD type="integer">1000</USD></unit_amount_in_cents>`
+	if err := xml.NewDecoder(bytes.NewBufferString(str)).Decode(&given); err != nil {
+		t.Fatalf("TestUnitAmountDecodeSkipsNil Error: Error decoding. Error: %s", err)
+	}
+
+	expected := UnitAmount{USD: 1000, Other: map[string]int{"AUD": 1500}}
+	if !reflect.DeepEqual(expected, given) {
+		t.Errorf("TestUnitAmountDecodeSkipsNil Error: Expected %#v, given %#v", expected, given)
+	}
+}
+
+func TestUnitAmountDecodeInvalid(t *testing.T) {
+	suite := []string{
+		`<unit_amount_in_cents><USD>ten</USD></unit_amount_in_cents>`,
+		`<unit_amount_in_cents><USD>1000</USD><EUR>`,
+	}
+
+	for i, str := range suite {
+		var given UnitAmount
+		if err := xml.NewDecoder(bytes.NewBufferString(str)).Decode(&given); err == nil {
+			t.Errorf("TestUnitAmountDecodeInvalid Error (%d): Expected error decoding %s, given %#v", i, str, given)
+		}
+	}
+}
+
+func TestUnitAmountAccessors(t *testing.T) {
+	var u UnitAmount
+	u.Set("usd", 1000)
+	u.Set("GBP", 800)
+	u.Set("jpy", 1200)
+
+	if u.USD != 1000 || u.Other["GBP"] != 800 || u.Other["JPY"] != 1200 {
+		t.Errorf("TestUnitAmountAccessors Error: Expected amounts to be set, given %#v", u)
+	}
+
+	if v, ok := u.Get("gbp"); !ok || v != 800 {
+		t.Errorf("TestUnitAmountAccessors Error: Expected GBP of 800, given %d (%v)", v, ok)
+	}
+
+	if _, ok := u.Get("EUR"); ok {
+		t.Error("TestUnitAmountAccessors Error: Expected EUR to not be set")
+	}
+
+	if given := u.Currencies(); !reflect.DeepEqual(given, []string{"USD", "GBP", "JPY"}) {
+		t.Errorf("TestUnitAmountAccessors Error: Expected USD, GBP, JPY, given %v", given)
+	}
+
+	// Zero amounts are unset in every currency.
+	u.Set("CAD", 0)
+	u.Set("EUR", 0)
+	for _, code := range []string{"CAD", "EUR"} {
+		if _, ok := u.Get(code); ok {
+			t.Errorf("TestUnitAmountAccessors Error: Expected zero %s to not be set", code)
+		}
+	}
+	if given := (UnitAmount{Other: map[string]int{"CAD": 0}}).Currencies(); len(given) != 0 {
+		t.Errorf("TestUnitAmountAccessors Error: Expected no currencies for a zero amount, given %v", given)
+	}
+
+	u.Delete("GBP")
+	u.Delete("USD")
+	if given := u.Currencies(); !reflect.DeepEqual(given, []string{"JPY"}) {
+		t.Errorf("TestUnitAmountAccessors Error: Expected only JPY after delete, given %v", given)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"amount": 1050, "currency": "USD", "expected": "10.50"},
+		map[string]interface{}{"amount": 5, "currency": "EUR", "expected": "0.05"},
+		map[string]interface{}{"amount": -1999, "currency": "GBP", "expected": "-19.99"},
+		map[string]interface{}{"amount": 500, "currency": "JPY", "expected": "500"},
+		map[string]interface{}{"amount": 15000, "currency": "krw", "expected": "15000"},
+	}
+
+	for _, s := range suite {
+		given := FormatAmount(s["amount"].(int), s["currency"].(string))
+		if given != s["expected"].(string) {
+			t.Errorf("TestFormatAmount Error: Expected %s, given %s", s["expected"], given)
+		}
+	}
+
+	if IsZeroDecimal("USD") || !IsZeroDecimal("JPY") {
+		t.Error("TestFormatAmount Error: Expected only JPY to be zero-decimal")
+	}
+}
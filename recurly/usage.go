@@ -0,0 +1,188 @@
+package recurly
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+type (
+	// UsageService handles communication with the usage related methods of
+	// the recurly API. Usage is logged against a usage add on of a
+	// subscription, named by the subscription's UUID and the add on code.
+	UsageService interface {
+		List(subUUID string, addOnCode string, params Params) (*Response, []Usage, error)
+		ListContext(ctx context.Context, subUUID string, addOnCode string, params Params) (*Response, []Usage, error)
+		ListPager(subUUID string, addOnCode string, params Params) *Pager[Usage]
+		Get(subUUID string, addOnCode string, id int) (*Response, Usage, error)
+		GetContext(ctx context.Context, subUUID string, addOnCode string, id int) (*Response, Usage, error)
+		Create(subUUID string, addOnCode string, u Usage) (*Response, Usage, error)
+		CreateContext(ctx context.Context, subUUID string, addOnCode string, u Usage) (*Response, Usage, error)
+		Update(subUUID string, addOnCode string, id int, u Usage) (*Response, Usage, error)
+		UpdateContext(ctx context.Context, subUUID string, addOnCode string, id int, u Usage) (*Response, Usage, error)
+		Delete(subUUID string, addOnCode string, id int) (*Response, error)
+		DeleteContext(ctx context.Context, subUUID string, addOnCode string, id int) (*Response, error)
+	}
+
+	// usageImpl implements UsageService.
+	usageImpl struct {
+		client *Client
+	}
+
+	// Usage is an amount of a usage add on used by a subscription, such as
+	// a number of API calls. It is billed on the subscription's next
+	// invoice, after which it can't be changed. Amount is a NullInt so an
+	// update can leave it unchanged, and UsagePercentage is a decimal
+	// string, like the add on's.
+	Usage struct {
+		XMLName            xml.Name `xml:"usage"`
+		ID                 int      `xml:"id,omitempty"`
+		MeasuredUnit       href     `xml:"measured_unit,omitempty"`
+		Amount             NullInt  `xml:"amount,omitempty"`
+		MerchantTag        string   `xml:"merchant_tag,omitempty"`
+		RecordingTimestamp NullTime `xml:"recording_timestamp,omitempty"`
+		UsageTimestamp     NullTime `xml:"usage_timestamp,omitempty"`
+		UsageType          string   `xml:"usage_type,omitempty"`
+		UnitAmountInCents  int      `xml:"unit_amount_in_cents,omitempty"`
+		UsagePercentage    string   `xml:"usage_percentage,omitempty"`
+		BilledAt           NullTime `xml:"billed_at,omitempty"`
+		CreatedAt          NullTime `xml:"created_at,omitempty"`
+		UpdatedAt          NullTime `xml:"updated_at,omitempty"`
+	}
+
+	usageMarshaler struct {
+		XMLName            xml.Name `xml:"usage"`
+		Amount             NullInt  `xml:"amount,omitempty"`
+		MerchantTag        string   `xml:"merchant_tag,omitempty"`
+		RecordingTimestamp NullTime `xml:"recording_timestamp,omitempty"`
+		UsageTimestamp     NullTime `xml:"usage_timestamp,omitempty"`
+	}
+)
+
+// MarshalXML marshals only the fields needed for logging/updating usage
+// with the recurly API.
+func (u Usage) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	um := usageMarshaler{
+		Amount:             u.Amount,
+		MerchantTag:        u.MerchantTag,
+		RecordingTimestamp: u.RecordingTimestamp,
+		UsageTimestamp:     u.UsageTimestamp,
+	}
+
+	return e.Encode(um)
+}
+
+// List returns the usage logged against a subscription add on.
+// https://dev.recurly.com/docs/list-add-ons-usage
+func (service usageImpl) List(subUUID string, addOnCode string, params Params) (*Response, []Usage, error) {
+	return service.ListContext(context.Background(), subUUID, addOnCode, params)
+}
+
+// ListContext is the same as List, but uses ctx for the request.
+func (service usageImpl) ListContext(ctx context.Context, subUUID string, addOnCode string, params Params) (*Response, []Usage, error) {
+	ctx = withOperation(ctx, "Usage.List", "subscriptions/{uuid}/add_ons/{add_on_code}/usage")
+	action := fmt.Sprintf("subscriptions/%s/add_ons/%s/usage", subUUID, addOnCode)
+	req, err := service.client.newRequest(ctx, "GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var u struct {
+		XMLName xml.Name `xml:"usages"`
+		Usage   []Usage  `xml:"usage"`
+	}
+	res, err := service.client.do(req, &u)
+
+	return res, u.Usage, err
+}
+
+// ListPager returns a Pager that walks every page of usage logged against a subscription add on.
+func (service usageImpl) ListPager(subUUID string, addOnCode string, params Params) *Pager[Usage] {
+	return NewPager(params, func(ctx context.Context, params Params) (*Response, []Usage, error) {
+		return service.ListContext(ctx, subUUID, addOnCode, params)
+	})
+}
+
+// Get returns a single usage record.
+// https://dev.recurly.com/docs/lookup-usage-record
+func (service usageImpl) Get(subUUID string, addOnCode string, id int) (*Response, Usage, error) {
+	return service.GetContext(context.Background(), subUUID, addOnCode, id)
+}
+
+// GetContext is the same as Get, but uses ctx for the request.
+func (service usageImpl) GetContext(ctx context.Context, subUUID string, addOnCode string, id int) (*Response, Usage, error) {
+	ctx = withOperation(ctx, "Usage.Get", "subscriptions/{uuid}/add_ons/{add_on_code}/usage/{usage_id}")
+	action := fmt.Sprintf("subscriptions/%s/add_ons/%s/usage/%d", subUUID, addOnCode, id)
+	req, err := service.client.newRequest(ctx, "GET", action, nil, nil)
+	if err != nil {
+		return nil, Usage{}, err
+	}
+
+	var dest Usage
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Create logs usage against a subscription add on. UsageTimestamp is when
+// the usage happened, and RecordingTimestamp when it was recorded; Recurly
+// uses the current time for either when it's not set.
+// https://dev.recurly.com/docs/log-usage
+func (service usageImpl) Create(subUUID string, addOnCode string, u Usage) (*Response, Usage, error) {
+	return service.CreateContext(context.Background(), subUUID, addOnCode, u)
+}
+
+// CreateContext is the same as Create, but uses ctx for the request.
+func (service usageImpl) CreateContext(ctx context.Context, subUUID string, addOnCode string, u Usage) (*Response, Usage, error) {
+	ctx = withOperation(ctx, "Usage.Create", "subscriptions/{uuid}/add_ons/{add_on_code}/usage")
+	action := fmt.Sprintf("subscriptions/%s/add_ons/%s/usage", subUUID, addOnCode)
+	req, err := service.client.newRequest(ctx, "POST", action, nil, u)
+	if err != nil {
+		return nil, Usage{}, err
+	}
+
+	var dest Usage
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Update changes usage that hasn't been billed yet. Only the fields that are
+// set are updated.
+// https://dev.recurly.com/docs/update-usage-record
+func (service usageImpl) Update(subUUID string, addOnCode string, id int, u Usage) (*Response, Usage, error) {
+	return service.UpdateContext(context.Background(), subUUID, addOnCode, id, u)
+}
+
+// UpdateContext is the same as Update, but uses ctx for the request.
+func (service usageImpl) UpdateContext(ctx context.Context, subUUID string, addOnCode string, id int, u Usage) (*Response, Usage, error) {
+	ctx = withOperation(ctx, "Usage.Update", "subscriptions/{uuid}/add_ons/{add_on_code}/usage/{usage_id}")
+	action := fmt.Sprintf("subscriptions/%s/add_ons/%s/usage/%d", subUUID, addOnCode, id)
+	req, err := service.client.newRequest(ctx, "PUT", action, nil, u)
+	if err != nil {
+		return nil, Usage{}, err
+	}
+
+	var dest Usage
+	res, err := service.client.do(req, &dest)
+
+	return res, dest, err
+}
+
+// Delete removes usage that hasn't been billed yet.
+// https://dev.recurly.com/docs/delete-a-usage-record
+func (service usageImpl) Delete(subUUID string, addOnCode string, id int) (*Response, error) {
+	return service.DeleteContext(context.Background(), subUUID, addOnCode, id)
+}
+
+// DeleteContext is the same as Delete, but uses ctx for the request.
+func (service usageImpl) DeleteContext(ctx context.Context, subUUID string, addOnCode string, id int) (*Response, error) {
+	ctx = withOperation(ctx, "Usage.Delete", "subscriptions/{uuid}/add_ons/{add_on_code}/usage/{usage_id}")
+	action := fmt.Sprintf("subscriptions/%s/add_ons/%s/usage/%d", subUUID, addOnCode, id)
+	req, err := service.client.newRequest(ctx, "DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.do(req, nil)
+}
@@ -0,0 +1,199 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestUsageEncoding ensures structs are encoded to XML properly.
+// Read only fields should never be sent to Recurly.
+func TestUsageEncoding(t *testing.T) {
+	ts := time.Date(2017, time.February, 1, 12, 0, 0, 0, time.UTC)
+	suite := []map[string]interface{}{
+		map[string]interface{}{"struct": Usage{}, "xml": "<usage></usage>"},
+		map[string]interface{}{"struct": Usage{ID: 1, Amount: NewInt(100), UsageType: UsageTypePrice, UnitAmountInCents: 5, BilledAt: NewTime(ts)}, "xml": "<usage><amount>100</amount></usage>"},
+		map[string]interface{}{"struct": Usage{MerchantTag: "Server 1"}, "xml": "<usage><merchant_tag>Server 1</merchant_tag></usage>"},
+		map[string]interface{}{"struct": Usage{Amount: NewInt(100), MerchantTag: "Server 1", UsageTimestamp: NewTime(ts), RecordingTimestamp: NewTime(ts)}, "xml": "<usage><amount>100</amount><merchant_tag>Server 1</merchant_tag><recording_timestamp>2017-02-01T12:00:00Z</recording_timestamp><usage_timestamp>2017-02-01T12:00:00Z</usage_timestamp></usage>"},
+	}
+
+	for _, s := range suite {
+		buf := new(bytes.Buffer)
+		err := xml.NewEncoder(buf).Encode(s["struct"])
+		if err != nil {
+			t.Errorf("TestUsageEncoding Error: %s", err)
+		}
+
+		if buf.String() != s["xml"] {
+			t.Errorf("TestUsageEncoding Error: Expected %s, given %s", s["xml"], buf.String())
+		}
+	}
+}
+
+func TestUsageList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/abc/add_ons/calls/usage", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestUsageList Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<usages type="array">
+				<usage href="https://your-subdomain.recurly.com/v2/subscriptions/abc/add_ons/calls/usage/394729929104688227">
+					<id type="integer">394729929104688227</id>
+					<measured_unit href="https://your-subdomain.recurly.com/v2/measured_units/5"/>
+					<amount type="integer">100</amount>
+					<merchant_tag>Server 1</merchant_tag>
+					<recording_timestamp type="datetime">2017-02-01T12:00:00Z</recording_timestamp>
+					<usage_timestamp type="datetime">2017-02-01T12:00:00Z</usage_timestamp>
+					<usage_type>price</usage_type>
+					<unit_amount_in_cents type="integer">5</unit_amount_in_cents>
+					<usage_percentage nil="nil"></usage_percentage>
+					<billed_at nil="nil"></billed_at>
+					<created_at type="datetime">2017-02-01T12:00:00Z</created_at>
+					<updated_at type="datetime">2017-02-01T12:00:00Z</updated_at>
+				</usage>
+			</usages>`)
+	})
+
+	r, usage, err := client.Usage.List("abc", "calls", nil)
+	if err != nil {
+		t.Errorf("TestUsageList Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestUsageList Error: Expected list usage to return OK")
+	}
+
+	ts, _ := time.Parse(datetimeFormat, "2017-02-01T12:00:00Z")
+	expected := []Usage{
+		Usage{
+			XMLName: xml.Name{Local: "usage"},
+			ID:      394729929104688227,
+			MeasuredUnit: href{
+				HREF: "https://your-subdomain.recurly.com/v2/measured_units/5",
+				Code: "5",
+			},
+			Amount:             NewInt(100),
+			MerchantTag:        "Server 1",
+			RecordingTimestamp: NewTime(ts),
+			UsageTimestamp:     NewTime(ts),
+			UsageType:          UsageTypePrice,
+			UnitAmountInCents:  5,
+			CreatedAt:          NewTime(ts),
+			UpdatedAt:          NewTime(ts),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, usage) {
+		t.Errorf("TestUsageList Error: expected usage to equal %#v, given %#v", expected, usage)
+	}
+}
+
+func TestGetUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/abc/add_ons/calls/usage/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("TestGetUsage Error: Expected %s request, given %s", "GET", r.Method)
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?>
+			<usage href="https://your-subdomain.recurly.com/v2/subscriptions/abc/add_ons/calls/usage/5">
+				<id type="integer">5</id>
+				<amount type="integer">100</amount>
+				<usage_type>percentage</usage_type>
+				<usage_percentage type="float">2.5</usage_percentage>
+			</usage>`)
+	})
+
+	r, usage, err := client.Usage.Get("abc", "calls", 5)
+	if err != nil {
+		t.Errorf("TestGetUsage Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestGetUsage Error: Expected get usage to return OK")
+	}
+
+	if usage.ID != 5 || usage.Amount != NewInt(100) || usage.UsageType != UsageTypePercentage || usage.UsagePercentage != "2.5" {
+		t.Errorf("TestGetUsage Error: Unexpected usage, given %#v", usage)
+	}
+}
+
+func TestCreateUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/abc/add_ons/calls/usage", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("TestCreateUsage Error: Expected %s request, given %s", "POST", r.Method)
+		}
+		rw.WriteHeader(201)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><usage></usage>`)
+	})
+
+	r, _, err := client.Usage.Create("abc", "calls", Usage{Amount: NewInt(100)})
+	if err != nil {
+		t.Errorf("TestCreateUsage Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestCreateUsage Error: Expected create usage to return OK")
+	}
+}
+
+func TestUpdateUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/abc/add_ons/calls/usage/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("TestUpdateUsage Error: Expected %s request, given %s", "PUT", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected := "<usage><merchant_tag>Server 2</merchant_tag></usage>"; given.String() != expected {
+			t.Errorf("TestUpdateUsage Error: Expected request body of %s, given %s", expected, given.String())
+		}
+		rw.WriteHeader(200)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="UTF-8"?><usage></usage>`)
+	})
+
+	r, _, err := client.Usage.Update("abc", "calls", 5, Usage{MerchantTag: "Server 2"})
+	if err != nil {
+		t.Errorf("TestUpdateUsage Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestUpdateUsage Error: Expected update usage to return OK")
+	}
+}
+
+func TestDeleteUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/abc/add_ons/calls/usage/5", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("TestDeleteUsage Error: Expected %s request, given %s", "DELETE", r.Method)
+		}
+		rw.WriteHeader(204)
+	})
+
+	r, err := client.Usage.Delete("abc", "calls", 5)
+	if err != nil {
+		t.Errorf("TestDeleteUsage Error: Error occurred making API call. Err: %s", err)
+	}
+
+	if r.IsError() {
+		t.Fatal("TestDeleteUsage Error: Expected delete usage to return OK")
+	}
+}
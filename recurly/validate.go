@@ -0,0 +1,155 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// validation collects the validation errors found in a payload, in the same
+// shape as the errors returned by Recurly.
+type validation []Error
+
+// currencies holds the ISO 4217 currency codes.
+var currencies = codeSet(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND
+	BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU
+	CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS
+	GIP GMD GNF GTQ GYD HKD HNL HRK HTG HUF IDR ILS INR IQD IRR ISK JMD JOD
+	JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL
+	MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR
+	NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG
+	SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY
+	TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XCD
+	XCG XOF XPF YER ZAR ZMW ZWG ZWL
+`)
+
+// countries holds the ISO 3166-1 alpha-2 country codes, along with XK for
+// Kosovo, which Recurly accepts.
+var countries = codeSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI
+	BJ BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN
+	CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK
+	FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+	HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN
+	KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK
+	ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP
+	NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF
+	TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI
+	VN VU WF WS XK YE YT ZA ZM ZW
+`)
+
+// codeSet returns the set of whitespace separated codes in s.
+func codeSet(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, code := range strings.Fields(s) {
+		m[code] = true
+	}
+	return m
+}
+
+// IsCurrency returns true if code is an ISO 4217 currency code, such as
+// "USD". Codes are matched without regard to case.
+func IsCurrency(code string) bool {
+	return currencies[strings.ToUpper(code)]
+}
+
+// IsCountry returns true if code is an ISO 3166-1 alpha-2 country code, such
+// as "US". Codes are matched without regard to case.
+func IsCountry(code string) bool {
+	return countries[strings.ToUpper(code)]
+}
+
+// add records an error on field.
+func (v *validation) add(field string, symbol string, message string) {
+	*v = append(*v, Error{
+		XMLName: xml.Name{Local: "error"},
+		Field:   field,
+		Symbol:  symbol,
+		Message: message,
+	})
+}
+
+// required records an error on field if it isn't set.
+func (v *validation) required(field string, set bool) {
+	if !set {
+		v.add(field, "blank", "can't be blank")
+	}
+}
+
+// oneOf records an error on field if value is set and isn't one of values.
+func (v *validation) oneOf(field string, value string, values ...string) {
+	if value == "" {
+		return
+	}
+	for _, s := range values {
+		if value == s {
+			return
+		}
+	}
+	v.add(field, "inclusion", "is not included in the list")
+}
+
+// currency records an error on field if code is set and isn't a currency.
+func (v *validation) currency(field string, code string) {
+	if code != "" && !IsCurrency(code) {
+		v.add(field, "invalid_currency", "is not a valid ISO 4217 currency code")
+	}
+}
+
+// country records an error on field if code is set and isn't a country.
+func (v *validation) country(field string, code string) {
+	if code != "" && !IsCountry(code) {
+		v.add(field, "invalid", "is not a valid ISO 3166-1 country code")
+	}
+}
+
+// nonNegative records an error on field if n is less than zero.
+func (v *validation) nonNegative(field string, n int) {
+	if n < 0 {
+		v.add(field, "greater_than_or_equal_to", "must be greater than or equal to 0")
+	}
+}
+
+// unitAmount records an error for each currency of u that isn't a currency
+// or has a negative amount.
+func (v *validation) unitAmount(field string, u UnitAmount) {
+	for _, c := range u.Currencies() {
+		amount, _ := u.Get(c)
+		v.currency(field+"."+c, c)
+		v.nonNegative(field+"."+c, amount)
+	}
+}
+
+// account records the errors in an account given with a subscription or
+// transaction. prefix is the field of the account, such as
+// "subscription.account".
+func (v *validation) account(prefix string, a Account) {
+	v.required(prefix+".account_code", a.Code != "")
+	v.country(prefix+".address.country", a.Address.Country)
+	if a.BillingInfo != nil {
+		v.country(prefix+".billing_info.country", a.BillingInfo.Country)
+		v.nested(prefix, a.BillingInfo.Validate())
+	}
+}
+
+// nested records the errors of a nested payload, such as billing info,
+// with their fields under prefix.
+func (v *validation) nested(prefix string, err error) {
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return
+	}
+	for _, e := range verr.Errors {
+		v.add(prefix+"."+e.Field, e.Symbol, e.Message)
+	}
+}
+
+// err returns the collected errors as a *ValidationError, or nil if there
+// are none.
+func (v validation) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v}
+}
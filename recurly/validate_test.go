@@ -0,0 +1,156 @@
+package recurly
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestIsCurrencyAndCountry(t *testing.T) {
+	for _, code := range []string{"USD", "eur", "JPY"} {
+		if !IsCurrency(code) {
+			t.Errorf("TestIsCurrencyAndCountry Error: Expected %s to be a currency", code)
+		}
+	}
+	for _, code := range []string{"", "US", "DOLLARS", "XYZ"} {
+		if IsCurrency(code) {
+			t.Errorf("TestIsCurrencyAndCountry Error: Expected %s not to be a currency", code)
+		}
+	}
+
+	for _, code := range []string{"US", "gb", "DE", "XK"} {
+		if !IsCountry(code) {
+			t.Errorf("TestIsCurrencyAndCountry Error: Expected %s to be a country", code)
+		}
+	}
+	for _, code := range []string{"", "USA", "UK", "ZZ"} {
+		if IsCountry(code) {
+			t.Errorf("TestIsCurrencyAndCountry Error: Expected %s not to be a country", code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var fees UnitAmount
+	fees.Set("USD", 100)
+	fees.Set("XYZ", -1)
+
+	suite := []map[string]interface{}{
+		// Subscriptions
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "USD", Account: Account{Code: "1"}}, "fields": []string(nil)},
+		map[string]interface{}{"struct": NewSubscription{}, "fields": []string{"subscription.plan_code", "subscription.currency", "subscription.account.account_code"}},
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "usdollars", Account: Account{Code: "1", Address: Address{Country: "USA"}}}, "fields": []string{"subscription.currency", "subscription.account.address.country"}},
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "USD", Account: Account{Code: "1", BillingInfo: &Billing{Number: "4111", Month: 13}}}, "fields": []string{"subscription.account.billing_info.month"}},
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "USD", Account: Account{Code: "1"}, Quantity: -1, UnitAmountInCents: -1, CollectionMethod: "invoice"}, "fields": []string{"subscription.unit_amount_in_cents", "subscription.quantity", "subscription.collection_method"}},
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "USD", Account: Account{Code: "1"}, SubscriptionAddOns: &[]SubscriptionAddOn{{Quantity: -2}}}, "fields": []string{"subscription.subscription_add_ons.add_on_code", "subscription.subscription_add_ons.quantity"}},
+		map[string]interface{}{"struct": NewSubscription{PlanCode: "gold", Currency: "USD", Account: Account{Code: "1"}, ShippingAddress: &ShippingAddress{Country: "Canada"}}, "fields": []string{"subscription.shipping_address.country"}},
+		map[string]interface{}{"struct": UpdateSubscription{Timeframe: "renewal", Quantity: 2}, "fields": []string(nil)},
+		map[string]interface{}{"struct": UpdateSubscription{Timeframe: "later", Quantity: -2}, "fields": []string{"subscription.timeframe", "subscription.quantity"}},
+
+		// Transactions
+		map[string]interface{}{"struct": NewTransaction{AmountInCents: 100, Currency: "EUR", Account: Account{Code: "1"}}, "fields": []string(nil)},
+		map[string]interface{}{"struct": NewTransaction{TaxInCents: -1}, "fields": []string{"transaction.amount_in_cents", "transaction.tax_in_cents", "transaction.currency", "transaction.account.account_code"}},
+
+		// Adjustments
+		map[string]interface{}{"struct": Adjustment{UnitAmountInCents: -500, Currency: "USD"}, "fields": []string(nil)},
+		map[string]interface{}{"struct": Adjustment{Currency: "US", Quantity: -1}, "fields": []string{"adjustment.currency", "adjustment.quantity"}},
+
+		// Coupons
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypePercent, DiscountPercent: 10}, "fields": []string(nil)},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypeDollars, DiscountInCents: 500}, "fields": []string(nil)},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypeFreeTrial}, "fields": []string(nil)},
+		map[string]interface{}{"struct": Coupon{}, "fields": []string{"coupon.coupon_code", "coupon.name", "coupon.discount_type"}},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: "half"}, "fields": []string{"coupon.discount_type"}},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypePercent, DiscountPercent: 101, DiscountInCents: 500}, "fields": []string{"coupon.discount_percent", "coupon.discount_in_cents"}},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypeDollars, DiscountPercent: 10}, "fields": []string{"coupon.discount_in_cents", "coupon.discount_percent"}},
+		map[string]interface{}{"struct": Coupon{Code: "save", Name: "Save", DiscountType: CouponDiscountTypeFreeTrial, DiscountPercent: 10, MaxRedemptions: NewInt(-1)}, "fields": []string{"coupon.discount_percent", "coupon.max_redemptions"}},
+
+		// Plans
+		map[string]interface{}{"struct": Plan{Code: "gold", Name: "Gold", IntervalUnit: PlanIntervalUnitMonths, IntervalLength: 1}, "fields": []string(nil)},
+		map[string]interface{}{"struct": Plan{IntervalUnit: "weeks", TrialIntervalUnit: "years", IntervalLength: -1}, "fields": []string{"plan.plan_code", "plan.name", "plan.plan_interval_unit", "plan.plan_interval_length", "plan.trial_interval_unit"}},
+		map[string]interface{}{"struct": Plan{Code: "gold", Name: "Gold", SetupFeeInCents: fees}, "fields": []string{"plan.setup_fee_in_cents.XYZ", "plan.setup_fee_in_cents.XYZ"}},
+	}
+
+	for i, s := range suite {
+		err := s["struct"].(interface{ Validate() error }).Validate()
+
+		var given []string
+		if verr, ok := err.(*ValidationError); ok {
+			for _, e := range verr.Errors {
+				given = append(given, e.Field)
+			}
+		} else if err != nil {
+			t.Fatalf("TestValidate Error (%d): Expected *ValidationError, given %T", i, err)
+		}
+
+		if !reflect.DeepEqual(given, s["fields"]) {
+			t.Errorf("TestValidate Error (%d): Expected errors on %v, given %v", i, s["fields"], given)
+		}
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc("/v2/plans", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(201)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><plan></plan>`))
+	})
+
+	// Without ValidateRequests, invalid payloads are left to Recurly.
+	if _, _, err := client.Plans.Create(Plan{}); err != nil {
+		t.Fatalf("TestValidateRequests Error: Error occurred making API call. Err: %s", err)
+	} else if calls != 1 {
+		t.Fatalf("TestValidateRequests Error: Expected 1 request, given %d", calls)
+	}
+
+	client.ValidateRequests = true
+	r, _, err := client.Plans.Create(Plan{Code: "gold"})
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("TestValidateRequests Error: Expected *ValidationError, given %v", err)
+	} else if r != nil {
+		t.Fatalf("TestValidateRequests Error: Expected nil response, given %#v", r)
+	} else if calls != 1 {
+		t.Fatalf("TestValidateRequests Error: Expected no request to be made, given %d", calls)
+	} else if len(verr.Errors) != 1 || verr.Errors[0].Field != "plan.name" || verr.Errors[0].Symbol != "blank" {
+		t.Fatalf("TestValidateRequests Error: Unexpected errors: %#v", verr.Errors)
+	}
+
+	if _, _, err := client.Plans.Create(Plan{Code: "gold", Name: "Gold"}); err != nil {
+		t.Fatalf("TestValidateRequests Error: Error occurred making API call. Err: %s", err)
+	} else if calls != 2 {
+		t.Fatalf("TestValidateRequests Error: Expected 2 requests, given %d", calls)
+	}
+}
+
+func TestValidateRequestsUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc("/v2/plans/gold", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(200)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><plan><plan_code>gold</plan_code><name>Gold v2</name></plan>`))
+	})
+
+	// Updates leave out the fields that don't change.
+	client.ValidateRequests = true
+	if _, p, err := client.Plans.Update("gold", Plan{Name: "Gold v2"}); err != nil {
+		t.Fatalf("TestValidateRequestsUpdate Error: Error occurred making API call. Err: %s", err)
+	} else if calls != 1 || p.Name != "Gold v2" {
+		t.Fatalf("TestValidateRequestsUpdate Error: Expected partial update to be sent, given %d requests and %#v", calls, p)
+	}
+
+	// The fields that are given are still checked.
+	_, _, err := client.Plans.Update("gold", Plan{IntervalUnit: "weeks"})
+	if verr, ok := err.(*ValidationError); !ok || len(verr.Errors) != 1 || verr.Errors[0].Field != "plan.plan_interval_unit" {
+		t.Fatalf("TestValidateRequestsUpdate Error: Expected interval unit error, given %v", err)
+	} else if calls != 1 {
+		t.Fatalf("TestValidateRequestsUpdate Error: Expected no request to be made, given %d", calls)
+	}
+}
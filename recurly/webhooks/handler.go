@@ -0,0 +1,323 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// maxBodySize is the largest notification body the Handler will read.
+const maxBodySize = 1 << 20
+
+type (
+	// Handler is an http.Handler that receives webhooks from Recurly,
+	// authenticates them, parses the notification and dispatches it to the
+	// callback registered for its type.
+	//
+	// Responses follow what Recurly expects: 2xx when the notification was
+	// handled (or has no callback), 4xx when the request is rejected, and 5xx
+	// when a callback returns an error so that Recurly retries the delivery
+	// later.
+	//
+	// Callbacks should be registered before the Handler starts serving
+	// requests.
+	Handler struct {
+		username string
+		password string
+		networks []*net.IPNet
+
+		callbacks map[string]func(ctx context.Context, n interface{}) error
+
+		// ErrorLog is called with errors returned from callbacks and errors
+		// parsing notifications. Optional.
+		ErrorLog func(err error)
+	}
+)
+
+// NewHandler returns a Handler that requires HTTP basic auth with the given
+// credentials, matching those configured for the webhook endpoint in Recurly.
+// If username and password are both empty, authentication is disabled.
+func NewHandler(username string, password string) *Handler {
+	return &Handler{
+		username:  username,
+		password:  password,
+		callbacks: make(map[string]func(ctx context.Context, n interface{}) error),
+	}
+}
+
+// AllowIPs restricts deliveries to requests from the given IP addresses or
+// CIDR ranges. Requests from any other address receive a 403. The address is
+// taken from the request's RemoteAddr, so if the Handler runs behind a proxy
+// make sure RemoteAddr is set to the client address.
+func (h *Handler) AllowIPs(ips ...string) error {
+	for _, ip := range ips {
+		_, network, err := net.ParseCIDR(ip)
+		if err != nil {
+			parsed := net.ParseIP(ip)
+			if parsed == nil {
+				return fmt.Errorf("webhooks: invalid IP address or CIDR range: %q", ip)
+			}
+
+			bits := 8 * net.IPv6len
+			if parsed.To4() != nil {
+				parsed = parsed.To4()
+				bits = 8 * net.IPv4len
+			}
+			network = &net.IPNet{IP: parsed, Mask: net.CIDRMask(bits, bits)}
+		}
+
+		h.networks = append(h.networks, network)
+	}
+
+	return nil
+}
+
+// ServeHTTP handles a webhook delivery from Recurly.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rw.Header().Set("Allow", "POST")
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.allowed(r) {
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if !h.authenticated(r) {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="recurly"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	n, err := Parse(http.MaxBytesReader(rw, r.Body, maxBodySize))
+	if errors.Is(err, ErrUnknownNotification) {
+		// Nothing can handle it; acknowledge so Recurly doesn't retry.
+		h.logError(err)
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	} else if err != nil {
+		h.logError(err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	fn, ok := h.callbacks[notificationType(n)]
+	if !ok {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := fn(r.Context(), n); err != nil {
+		h.logError(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// allowed returns true if the request comes from an allowed IP address.
+func (h *Handler) allowed(r *http.Request) bool {
+	if len(h.networks) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range h.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authenticated returns true if the request has the expected basic auth
+// credentials.
+func (h *Handler) authenticated(r *http.Request) bool {
+	if h.username == "" && h.password == "" {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(h.username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(h.password)) == 1
+	return userOK && passOK
+}
+
+func (h *Handler) logError(err error) {
+	if h.ErrorLog != nil {
+		h.ErrorLog(err)
+	}
+}
+
+// on registers a callback for a notification type.
+func (h *Handler) on(name string, fn func(ctx context.Context, n interface{}) error) {
+	h.callbacks[name] = fn
+}
+
+// notificationType returns the Type of a parsed notification.
+func notificationType(n interface{}) string {
+	switch n := n.(type) {
+	case *AccountNotification:
+		return n.Type
+	case *SubscriptionNotification:
+		return n.Type
+	case *InvoiceNotification:
+		return n.Type
+	case *PaymentNotification:
+		return n.Type
+	}
+	return ""
+}
+
+// OnNewAccount registers fn to be called for NewAccount notifications.
+func (h *Handler) OnNewAccount(fn func(ctx context.Context, n *AccountNotification) error) {
+	h.on(NewAccount, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*AccountNotification))
+	})
+}
+
+// OnCanceledAccount registers fn to be called for CanceledAccount notifications.
+func (h *Handler) OnCanceledAccount(fn func(ctx context.Context, n *AccountNotification) error) {
+	h.on(CanceledAccount, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*AccountNotification))
+	})
+}
+
+// OnBillingInfoUpdated registers fn to be called for BillingInfoUpdated notifications.
+func (h *Handler) OnBillingInfoUpdated(fn func(ctx context.Context, n *AccountNotification) error) {
+	h.on(BillingInfoUpdated, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*AccountNotification))
+	})
+}
+
+// OnNewSubscription registers fn to be called for NewSubscription notifications.
+func (h *Handler) OnNewSubscription(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(NewSubscription, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnUpdatedSubscription registers fn to be called for UpdatedSubscription notifications.
+func (h *Handler) OnUpdatedSubscription(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(UpdatedSubscription, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnCanceledSubscription registers fn to be called for CanceledSubscription notifications.
+func (h *Handler) OnCanceledSubscription(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(CanceledSubscription, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnExpiredSubscription registers fn to be called for ExpiredSubscription notifications.
+func (h *Handler) OnExpiredSubscription(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(ExpiredSubscription, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnRenewedSubscription registers fn to be called for RenewedSubscription notifications.
+func (h *Handler) OnRenewedSubscription(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(RenewedSubscription, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnReactivatedAccount registers fn to be called for ReactivatedAccount notifications.
+func (h *Handler) OnReactivatedAccount(fn func(ctx context.Context, n *SubscriptionNotification) error) {
+	h.on(ReactivatedAccount, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*SubscriptionNotification))
+	})
+}
+
+// OnNewInvoice registers fn to be called for NewInvoice notifications.
+func (h *Handler) OnNewInvoice(fn func(ctx context.Context, n *InvoiceNotification) error) {
+	h.on(NewInvoice, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*InvoiceNotification))
+	})
+}
+
+// OnProcessingInvoice registers fn to be called for ProcessingInvoice notifications.
+func (h *Handler) OnProcessingInvoice(fn func(ctx context.Context, n *InvoiceNotification) error) {
+	h.on(ProcessingInvoice, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*InvoiceNotification))
+	})
+}
+
+// OnClosedInvoice registers fn to be called for ClosedInvoice notifications.
+func (h *Handler) OnClosedInvoice(fn func(ctx context.Context, n *InvoiceNotification) error) {
+	h.on(ClosedInvoice, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*InvoiceNotification))
+	})
+}
+
+// OnPastDueInvoice registers fn to be called for PastDueInvoice notifications.
+func (h *Handler) OnPastDueInvoice(fn func(ctx context.Context, n *InvoiceNotification) error) {
+	h.on(PastDueInvoice, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*InvoiceNotification))
+	})
+}
+
+// OnScheduledPayment registers fn to be called for ScheduledPayment notifications.
+func (h *Handler) OnScheduledPayment(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(ScheduledPayment, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
+
+// OnProcessingPayment registers fn to be called for ProcessingPayment notifications.
+func (h *Handler) OnProcessingPayment(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(ProcessingPayment, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
+
+// OnSuccessfulPayment registers fn to be called for SuccessfulPayment notifications.
+func (h *Handler) OnSuccessfulPayment(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(SuccessfulPayment, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
+
+// OnFailedPayment registers fn to be called for FailedPayment notifications.
+func (h *Handler) OnFailedPayment(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(FailedPayment, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
+
+// OnSuccessfulRefund registers fn to be called for SuccessfulRefund notifications.
+func (h *Handler) OnSuccessfulRefund(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(SuccessfulRefund, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
+
+// OnVoidPayment registers fn to be called for VoidPayment notifications.
+func (h *Handler) OnVoidPayment(fn func(ctx context.Context, n *PaymentNotification) error) {
+	h.on(VoidPayment, func(ctx context.Context, n interface{}) error {
+		return fn(ctx, n.(*PaymentNotification))
+	})
+}
@@ -0,0 +1,171 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newWebhookRequest(body io.Reader) *http.Request {
+	r := httptest.NewRequest("POST", "/recurly", body)
+	r.SetBasicAuth("recurly", "s3cret")
+	return r
+}
+
+func TestHandlerDispatchesNotifications(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+
+	var payment *PaymentNotification
+	h.OnSuccessfulPayment(func(ctx context.Context, n *PaymentNotification) error {
+		payment = n
+		return nil
+	})
+
+	var sub *SubscriptionNotification
+	h.OnCanceledSubscription(func(ctx context.Context, n *SubscriptionNotification) error {
+		sub = n
+		return nil
+	})
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, newWebhookRequest(openFixture(t, SuccessfulPayment)))
+	if rw.Code != http.StatusNoContent {
+		t.Fatalf("TestHandlerDispatchesNotifications Error: Expected status code %d, given %d", http.StatusNoContent, rw.Code)
+	}
+
+	if payment == nil {
+		t.Fatal("TestHandlerDispatchesNotifications Error: Expected successful payment callback to be called")
+	}
+
+	if payment.Transaction.UUID != "a5143c1d3a6f4a8287d0e2cc1d4c0427" || payment.Transaction.AmountInCents != 1000 {
+		t.Errorf("TestHandlerDispatchesNotifications Error: Expected transaction a5143c1d3a6f4a8287d0e2cc1d4c0427 for 1000 cents, given %#v", payment.Transaction)
+	}
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, newWebhookRequest(openFixture(t, CanceledSubscription)))
+	if rw.Code != http.StatusNoContent {
+		t.Fatalf("TestHandlerDispatchesNotifications Error: Expected status code %d, given %d", http.StatusNoContent, rw.Code)
+	}
+
+	if sub == nil || sub.Subscription.State != "canceled" {
+		t.Errorf("TestHandlerDispatchesNotifications Error: Expected canceled subscription, given %#v", sub)
+	}
+}
+
+func TestHandlerWithoutCallback(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+	h.OnSuccessfulPayment(func(ctx context.Context, n *PaymentNotification) error {
+		t.Error("TestHandlerWithoutCallback Error: Unexpected call to successful payment callback")
+		return nil
+	})
+
+	for _, name := range []string{FailedPayment, NewAccount, "unknown_notification"} {
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newWebhookRequest(openFixture(t, name)))
+		if rw.Code != http.StatusNoContent {
+			t.Errorf("TestHandlerWithoutCallback Error (%s): Expected status code %d, given %d", name, http.StatusNoContent, rw.Code)
+		}
+	}
+}
+
+func TestHandlerCallbackError(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+
+	var logged error
+	h.ErrorLog = func(err error) { logged = err }
+
+	failure := errors.New("database unavailable")
+	h.OnNewInvoice(func(ctx context.Context, n *InvoiceNotification) error {
+		return failure
+	})
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, newWebhookRequest(openFixture(t, NewInvoice)))
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("TestHandlerCallbackError Error: Expected status code %d, given %d", http.StatusInternalServerError, rw.Code)
+	}
+
+	if logged != failure {
+		t.Errorf("TestHandlerCallbackError Error: Expected error %v to be logged, given %v", failure, logged)
+	}
+}
+
+func TestHandlerAuthentication(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"auth": false, "code": http.StatusUnauthorized},
+		map[string]interface{}{"auth": true, "username": "recurly", "password": "wrong", "code": http.StatusUnauthorized},
+		map[string]interface{}{"auth": true, "username": "someone", "password": "s3cret", "code": http.StatusUnauthorized},
+		map[string]interface{}{"auth": true, "username": "recurly", "password": "s3cret", "code": http.StatusNoContent},
+	}
+
+	for i, s := range suite {
+		r := httptest.NewRequest("POST", "/recurly", openFixture(t, NewAccount))
+		if s["auth"].(bool) {
+			r.SetBasicAuth(s["username"].(string), s["password"].(string))
+		}
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		if rw.Code != s["code"].(int) {
+			t.Errorf("TestHandlerAuthentication Error (%d): Expected status code %d, given %d", i, s["code"], rw.Code)
+		}
+
+		if rw.Code == http.StatusUnauthorized && rw.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("TestHandlerAuthentication Error (%d): Expected WWW-Authenticate header", i)
+		}
+	}
+}
+
+func TestHandlerIPAllowlist(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+	if err := h.AllowIPs("74.201.212.175", "64.74.141.0/24"); err != nil {
+		t.Fatalf("TestHandlerIPAllowlist Error: Error setting allowed IPs. Err: %s", err)
+	}
+
+	suite := []map[string]interface{}{
+		map[string]interface{}{"addr": "74.201.212.175:4000", "code": http.StatusNoContent},
+		map[string]interface{}{"addr": "64.74.141.87:4000", "code": http.StatusNoContent},
+		map[string]interface{}{"addr": "74.201.212.176:4000", "code": http.StatusForbidden},
+		map[string]interface{}{"addr": "192.0.2.1:4000", "code": http.StatusForbidden},
+		map[string]interface{}{"addr": "invalid", "code": http.StatusForbidden},
+	}
+
+	for _, s := range suite {
+		r := newWebhookRequest(openFixture(t, NewAccount))
+		r.RemoteAddr = s["addr"].(string)
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		if rw.Code != s["code"].(int) {
+			t.Errorf("TestHandlerIPAllowlist Error (%s): Expected status code %d, given %d", s["addr"], s["code"], rw.Code)
+		}
+	}
+
+	if err := h.AllowIPs("not-an-ip"); err == nil {
+		t.Error("TestHandlerIPAllowlist Error: Expected error for invalid IP, given nil")
+	}
+}
+
+func TestHandlerBadRequests(t *testing.T) {
+	h := NewHandler("recurly", "s3cret")
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, newWebhookRequest(strings.NewReader("<new_account_notification><account>")))
+	if rw.Code != http.StatusBadRequest {
+		t.Errorf("TestHandlerBadRequests Error: Expected status code %d for malformed XML, given %d", http.StatusBadRequest, rw.Code)
+	}
+
+	rw = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/recurly", nil)
+	r.SetBasicAuth("recurly", "s3cret")
+	h.ServeHTTP(rw, r)
+	if rw.Code != http.StatusMethodNotAllowed {
+		t.Errorf("TestHandlerBadRequests Error: Expected status code %d for GET request, given %d", http.StatusMethodNotAllowed, rw.Code)
+	}
+}
@@ -0,0 +1,264 @@
+// Package webhooks parses the XML push notifications Recurly sends to your
+// site when events occur on an account.
+//
+// Notifications reuse the types from the recurly package, so an account in a
+// webhook decodes into a recurly.Account, a subscription into a
+// recurly.Subscription, and so on.
+// https://dev.recurly.com/page/webhooks
+package webhooks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+// Account notifications.
+const (
+	NewAccount         = "new_account_notification"
+	CanceledAccount    = "canceled_account_notification"
+	BillingInfoUpdated = "billing_info_updated_notification"
+)
+
+// Subscription notifications.
+const (
+	NewSubscription      = "new_subscription_notification"
+	UpdatedSubscription  = "updated_subscription_notification"
+	CanceledSubscription = "canceled_subscription_notification"
+	ExpiredSubscription  = "expired_subscription_notification"
+	RenewedSubscription  = "renewed_subscription_notification"
+	ReactivatedAccount   = "reactivated_account_notification"
+)
+
+// Invoice notifications.
+const (
+	NewInvoice        = "new_invoice_notification"
+	ProcessingInvoice = "processing_invoice_notification"
+	ClosedInvoice     = "closed_invoice_notification"
+	PastDueInvoice    = "past_due_invoice_notification"
+)
+
+// Payment notifications.
+const (
+	ScheduledPayment  = "scheduled_payment_notification"
+	ProcessingPayment = "processing_payment_notification"
+	SuccessfulPayment = "successful_payment_notification"
+	FailedPayment     = "failed_payment_notification"
+	SuccessfulRefund  = "successful_refund_notification"
+	VoidPayment       = "void_payment_notification"
+)
+
+// ErrUnknownNotification is returned by Parse when the notification type is
+// not recognized. The returned error wraps it and includes the type name.
+var ErrUnknownNotification = errors.New("webhooks: unknown notification")
+
+type (
+	// AccountNotification is sent for account events: NewAccount,
+	// CanceledAccount and BillingInfoUpdated.
+	AccountNotification struct {
+		Type    string          `xml:"-"`
+		Account recurly.Account `xml:"account"`
+	}
+
+	// SubscriptionNotification is sent for subscription events:
+	// NewSubscription, UpdatedSubscription, CanceledSubscription,
+	// ExpiredSubscription, RenewedSubscription and ReactivatedAccount.
+	SubscriptionNotification struct {
+		Type         string               `xml:"-"`
+		Account      recurly.Account      `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
+
+	// InvoiceNotification is sent for invoice events: NewInvoice,
+	// ProcessingInvoice, ClosedInvoice and PastDueInvoice.
+	InvoiceNotification struct {
+		Type    string          `xml:"-"`
+		Account recurly.Account `xml:"account"`
+		Invoice recurly.Invoice `xml:"-"`
+	}
+
+	// PaymentNotification is sent for payment events: ScheduledPayment,
+	// ProcessingPayment, SuccessfulPayment, FailedPayment, SuccessfulRefund
+	// and VoidPayment.
+	PaymentNotification struct {
+		Type        string              `xml:"-"`
+		Account     recurly.Account     `xml:"account"`
+		Transaction recurly.Transaction `xml:"-"`
+
+		// InvoiceNumber is the number of the invoice the transaction was
+		// made against, if any.
+		InvoiceNumber int `xml:"-"`
+
+		// Message is the gateway's message for the transaction.
+		Message string `xml:"-"`
+	}
+
+	// rawElement holds the inner XML of an element so it can be decoded
+	// into more than one struct.
+	rawElement struct {
+		Inner []byte `xml:",innerxml"`
+	}
+
+	// webhookTransaction holds the transaction fields that are named
+	// differently in webhooks than they are in the API.
+	webhookTransaction struct {
+		ID             string           `xml:"id"`
+		InvoiceID      string           `xml:"invoice_id"`
+		InvoiceNumber  int              `xml:"invoice_number"`
+		SubscriptionID string           `xml:"subscription_id"`
+		Date           recurly.NullTime `xml:"date"`
+		Message        string           `xml:"message"`
+	}
+
+	// webhookInvoice holds the invoice fields that are named differently in
+	// webhooks than they are in the API.
+	webhookInvoice struct {
+		SubscriptionID string           `xml:"subscription_id"`
+		Date           recurly.NullTime `xml:"date"`
+	}
+)
+
+// Parse reads a webhook notification and returns it as one of
+// *AccountNotification, *SubscriptionNotification, *InvoiceNotification or
+// *PaymentNotification. The Type field holds the notification name, which
+// matches one of the constants in this package.
+func Parse(r io.Reader) (interface{}, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := rootName(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case NewAccount, CanceledAccount, BillingInfoUpdated:
+		var n AccountNotification
+		if err := xml.Unmarshal(body, &n); err != nil {
+			return nil, err
+		}
+		n.Type = name
+		return &n, nil
+	case NewSubscription, UpdatedSubscription, CanceledSubscription,
+		ExpiredSubscription, RenewedSubscription, ReactivatedAccount:
+		var n SubscriptionNotification
+		if err := xml.Unmarshal(body, &n); err != nil {
+			return nil, err
+		}
+		n.Type = name
+		return &n, nil
+	case NewInvoice, ProcessingInvoice, ClosedInvoice, PastDueInvoice:
+		return parseInvoice(name, body)
+	case ScheduledPayment, ProcessingPayment, SuccessfulPayment,
+		FailedPayment, SuccessfulRefund, VoidPayment:
+		return parsePayment(name, body)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownNotification, name)
+}
+
+// rootName returns the name of the root element of an XML document.
+func rootName(body []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return "", errors.New("webhooks: empty notification")
+			}
+			return "", err
+		}
+
+		if se, ok := t.(xml.StartElement); ok {
+			return se.Name.Local, nil
+		}
+	}
+}
+
+// parseInvoice decodes an invoice notification. Webhook invoices use
+// subscription_id and date where the API uses links and created_at.
+func parseInvoice(name string, body []byte) (*InvoiceNotification, error) {
+	var v struct {
+		Account recurly.Account `xml:"account"`
+		Invoice rawElement      `xml:"invoice"`
+	}
+	if err := xml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+
+	inner := wrap("invoice", v.Invoice.Inner)
+
+	var inv recurly.Invoice
+	if err := xml.Unmarshal(inner, &inv); err != nil {
+		return nil, err
+	}
+
+	var w webhookInvoice
+	if err := xml.Unmarshal(inner, &w); err != nil {
+		return nil, err
+	}
+
+	inv.Subscription.Code = w.SubscriptionID
+	if inv.CreatedAt.Time == nil {
+		inv.CreatedAt = w.Date
+	}
+
+	return &InvoiceNotification{
+		Type:    name,
+		Account: v.Account,
+		Invoice: inv,
+	}, nil
+}
+
+// parsePayment decodes a payment notification. Webhook transactions use id,
+// invoice_id, subscription_id and date where the API uses uuid, links and
+// created_at.
+func parsePayment(name string, body []byte) (*PaymentNotification, error) {
+	var v struct {
+		Account     recurly.Account `xml:"account"`
+		Transaction rawElement      `xml:"transaction"`
+	}
+	if err := xml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+
+	inner := wrap("transaction", v.Transaction.Inner)
+
+	var t recurly.Transaction
+	if err := xml.Unmarshal(inner, &t); err != nil {
+		return nil, err
+	}
+
+	var w webhookTransaction
+	if err := xml.Unmarshal(inner, &w); err != nil {
+		return nil, err
+	}
+
+	t.UUID = w.ID
+	t.Invoice.Code = w.InvoiceID
+	t.Subscription.Code = w.SubscriptionID
+	t.CreatedAt = w.Date
+
+	return &PaymentNotification{
+		Type:          name,
+		Account:       v.Account,
+		Transaction:   t,
+		InvoiceNumber: w.InvoiceNumber,
+		Message:       w.Message,
+	}, nil
+}
+
+// wrap surrounds inner XML with an element of the given name.
+func wrap(name string, inner []byte) []byte {
+	buf := bytes.NewBufferString("<" + name + ">")
+	buf.Write(inner)
+	buf.WriteString("</" + name + ">")
+	return buf.Bytes()
+}
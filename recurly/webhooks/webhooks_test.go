@@ -0,0 +1,230 @@
+package webhooks
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blacklightcms/go-recurly/recurly"
+)
+
+func TestParseAccountNotifications(t *testing.T) {
+	for _, name := range []string{NewAccount, CanceledAccount, BillingInfoUpdated} {
+		n := parseFixture(t, name)
+
+		given, ok := n.(*AccountNotification)
+		if !ok {
+			t.Fatalf("TestParseAccountNotifications Error (%s): Expected *AccountNotification, given %T", name, n)
+		}
+
+		expected := &AccountNotification{
+			Type:    name,
+			Account: expectedAccount(),
+		}
+
+		if !reflect.DeepEqual(expected, given) {
+			t.Errorf("TestParseAccountNotifications Error (%s): Expected notification to equal %#v, given %#v", name, expected, given)
+		}
+	}
+}
+
+func TestParseSubscriptionNotifications(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"type": NewSubscription, "state": "active"},
+		map[string]interface{}{"type": UpdatedSubscription, "state": "active"},
+		map[string]interface{}{"type": RenewedSubscription, "state": "active"},
+		map[string]interface{}{"type": ReactivatedAccount, "state": "active"},
+		map[string]interface{}{"type": CanceledSubscription, "state": "canceled", "canceled": true},
+		map[string]interface{}{"type": ExpiredSubscription, "state": "expired", "canceled": true},
+	}
+
+	for _, s := range suite {
+		name := s["type"].(string)
+		n := parseFixture(t, name)
+
+		given, ok := n.(*SubscriptionNotification)
+		if !ok {
+			t.Fatalf("TestParseSubscriptionNotifications Error (%s): Expected *SubscriptionNotification, given %T", name, n)
+		}
+
+		sub := recurly.Subscription{
+			XMLName:                xml.Name{Local: "subscription"},
+			UUID:                   "6ab458a887d38070807ebb3bed7ac1e5",
+			State:                  s["state"].(string),
+			Quantity:               1,
+			ActivatedAt:            newTime("2010-07-22T20:42:05Z"),
+			CurrentPeriodStartedAt: newTime("2010-09-22T20:42:05Z"),
+			CurrentPeriodEndsAt:    newTime("2010-10-22T20:42:05Z"),
+		}
+		sub.Plan.Code = "bootstrap"
+		sub.Plan.Name = "Bootstrap"
+		if _, ok := s["canceled"]; ok {
+			sub.CanceledAt = newTime("2010-09-23T22:05:03Z")
+			sub.ExpiresAt = newTime("2010-10-22T20:42:05Z")
+		}
+
+		expected := &SubscriptionNotification{
+			Type:         name,
+			Account:      expectedAccount(),
+			Subscription: sub,
+		}
+
+		if !reflect.DeepEqual(expected, given) {
+			t.Errorf("TestParseSubscriptionNotifications Error (%s): Expected notification to equal %#v, given %#v", name, expected, given)
+		}
+	}
+}
+
+func TestParseInvoiceNotifications(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"type": NewInvoice, "state": "open"},
+		map[string]interface{}{"type": ProcessingInvoice, "state": "processing"},
+		map[string]interface{}{"type": ClosedInvoice, "state": recurly.InvoiceStateCollected, "closed": true},
+		map[string]interface{}{"type": PastDueInvoice, "state": recurly.InvoiceStatePastDue},
+	}
+
+	for _, s := range suite {
+		name := s["type"].(string)
+		n := parseFixture(t, name)
+
+		given, ok := n.(*InvoiceNotification)
+		if !ok {
+			t.Fatalf("TestParseInvoiceNotifications Error (%s): Expected *InvoiceNotification, given %T", name, n)
+		}
+
+		inv := recurly.Invoice{
+			XMLName:          xml.Name{Local: "invoice"},
+			UUID:             "ffc64d71d4b5404e93f13aac9c63b007",
+			State:            s["state"].(string),
+			InvoiceNumber:    1000,
+			TotalInCents:     1000,
+			Currency:         "USD",
+			CreatedAt:        newTime("2014-01-01T20:21:44Z"),
+			NetTerms:         recurly.NewInt(0),
+			CollectionMethod: "manual",
+		}
+		inv.Subscription.Code = "6ab458a887d38070807ebb3bed7ac1e5"
+		if _, ok := s["closed"]; ok {
+			inv.ClosedAt = newTime("2014-01-01T20:24:02Z")
+		}
+
+		expected := &InvoiceNotification{
+			Type:    name,
+			Account: expectedAccount(),
+			Invoice: inv,
+		}
+
+		if !reflect.DeepEqual(expected, given) {
+			t.Errorf("TestParseInvoiceNotifications Error (%s): Expected notification to equal %#v, given %#v", name, expected, given)
+		}
+	}
+}
+
+func TestParsePaymentNotifications(t *testing.T) {
+	suite := []map[string]interface{}{
+		map[string]interface{}{"type": ScheduledPayment, "action": "purchase", "status": "scheduled", "message": "Bogus Gateway: Forced success", "voidable": false, "refundable": false},
+		map[string]interface{}{"type": ProcessingPayment, "action": "purchase", "status": "processing", "message": "Bogus Gateway: Forced success", "voidable": false, "refundable": false},
+		map[string]interface{}{"type": SuccessfulPayment, "action": "purchase", "status": recurly.TransactionStatusSuccess, "message": "Bogus Gateway: Forced success", "voidable": true, "refundable": true},
+		map[string]interface{}{"type": FailedPayment, "action": "purchase", "status": "declined", "message": "This transaction has been declined", "voidable": false, "refundable": false},
+		map[string]interface{}{"type": SuccessfulRefund, "action": "credit", "status": recurly.TransactionStatusSuccess, "message": "Bogus Gateway: Forced success", "voidable": true, "refundable": false},
+		map[string]interface{}{"type": VoidPayment, "action": "purchase", "status": recurly.TransactionStatusVoid, "message": "Test Gateway: Successful test transaction", "voidable": false, "refundable": false},
+	}
+
+	for _, s := range suite {
+		name := s["type"].(string)
+		n := parseFixture(t, name)
+
+		given, ok := n.(*PaymentNotification)
+		if !ok {
+			t.Fatalf("TestParsePaymentNotifications Error (%s): Expected *PaymentNotification, given %T", name, n)
+		}
+
+		txn := recurly.Transaction{
+			XMLName:       xml.Name{Local: "transaction"},
+			UUID:          "a5143c1d3a6f4a8287d0e2cc1d4c0427",
+			Action:        s["action"].(string),
+			AmountInCents: 1000,
+			Status:        s["status"].(string),
+			Reference:     "a5143c1d3a6f4a82",
+			Source:        "subscription",
+			Test:          true,
+			Voidable:      recurly.NewBool(s["voidable"].(bool)),
+			Refundable:    recurly.NewBool(s["refundable"].(bool)),
+			CreatedAt:     newTime("2009-11-22T13:10:38Z"),
+		}
+		txn.Invoice.Code = "ffc64d71d4b5404e93f13aac9c63b007"
+		txn.Subscription.Code = "6ab458a887d38070807ebb3bed7ac1e5"
+
+		expected := &PaymentNotification{
+			Type:          name,
+			Account:       expectedAccount(),
+			Transaction:   txn,
+			InvoiceNumber: 1000,
+			Message:       s["message"].(string),
+		}
+
+		if !reflect.DeepEqual(expected, given) {
+			t.Errorf("TestParsePaymentNotifications Error (%s): Expected notification to equal %#v, given %#v", name, expected, given)
+		}
+	}
+}
+
+func TestParseUnknownNotification(t *testing.T) {
+	n, err := Parse(openFixture(t, "unknown_notification"))
+	if n != nil {
+		t.Errorf("TestParseUnknownNotification Error: Expected nil notification, given %#v", n)
+	}
+
+	if !errors.Is(err, ErrUnknownNotification) {
+		t.Fatalf("TestParseUnknownNotification Error: Expected ErrUnknownNotification, given %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "dunning_notification") {
+		t.Errorf("TestParseUnknownNotification Error: Expected error to name the notification, given %s", err)
+	}
+}
+
+func TestParseInvalidBody(t *testing.T) {
+	for _, body := range []string{"", "not xml", "<new_account_notification><account>"} {
+		if _, err := Parse(strings.NewReader(body)); err == nil {
+			t.Errorf("TestParseInvalidBody Error: Expected error parsing %q, given nil", body)
+		}
+	}
+}
+
+func expectedAccount() recurly.Account {
+	return recurly.Account{
+		XMLName:   xml.Name{Local: "account"},
+		Code:      "1",
+		Email:     "verena@example.com",
+		FirstName: "Verena",
+		LastName:  "Example",
+	}
+}
+
+func newTime(str string) recurly.NullTime {
+	t, _ := time.Parse(time.RFC3339, str)
+	return recurly.NewTime(t)
+}
+
+func openFixture(t *testing.T, name string) *os.File {
+	f, err := os.Open(filepath.Join("testdata", name+".xml"))
+	if err != nil {
+		t.Fatalf("Error opening fixture %s: %s", name, err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func parseFixture(t *testing.T, name string) interface{} {
+	n, err := Parse(openFixture(t, name))
+	if err != nil {
+		t.Fatalf("Error parsing fixture %s: %s", name, err)
+	}
+	return n
+}